@@ -74,8 +74,13 @@ func (an AlgebraicNotation) IsPromotion() bool {
 	return an.PromotedTo() != Pawn
 }
 
+// The piece a pawn promotes to, Pawn if it doesn't, or zero when the piece is
+// missing after the "="
 func (an AlgebraicNotation) PromotedTo() Material {
 	if index := strings.Index(string(an), "="); index != -1 {
+		if index+1 >= len(an) {
+			return 0
+		}
 		materialCharacter := string(an[index+1])
 
 		return AlgebraicNotation(materialCharacter).Material()
@@ -97,11 +102,18 @@ func (an AlgebraicNotation) IsCastle() bool {
 }
 
 func (an AlgebraicNotation) IsCastleKingSide() bool {
-	return an == "O-O"
+	castle := an.stripAnnotations()
+	return castle == "O-O" || castle == "0-0"
 }
 
 func (an AlgebraicNotation) IsCastleQueenSide() bool {
-	return an == "O-O-O"
+	castle := an.stripAnnotations()
+	return castle == "O-O-O" || castle == "0-0-0"
+}
+
+// Strips check, checkmate and move assessment suffixes such as + # ! ?
+func (an AlgebraicNotation) stripAnnotations() AlgebraicNotation {
+	return AlgebraicNotation(strings.TrimRight(string(an), "+#!?"))
 }
 
 type AlgebraiclyNotated interface {
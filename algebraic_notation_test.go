@@ -109,6 +109,7 @@ func (s *AlgebraicNotationTestSuite) TestPromotion() {
 		"b1=R+": Rook,
 		"f1=Q":  Queen,
 		"f1=R":  Rook,
+		"e8=":   0,
 	}
 
 	for an, expectedMaterial := range expectations {
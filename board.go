@@ -11,13 +11,37 @@ var (
 )
 
 type Board struct {
-	Squares    []*Square
-	turnNumber int
-	moveMutex  *sync.Mutex
+	Squares       []*Square
+	turnNumber    int
+	moveMutex     *sync.Mutex
+	castling      castlingRights
+	enPassant     Position // Square a pawn may capture onto en passant
+	halfmoveClock int      // Moves since the last capture or pawn advance
+	history       []undo
+	hash          uint64
 }
 
 func NewBoard() *Board {
-	return &Board{Squares: AllSquares(), moveMutex: &sync.Mutex{}}
+	board := &Board{Squares: AllSquares(), moveMutex: &sync.Mutex{}, castling: allCastlingRights}
+	board.hash = board.computeHash()
+
+	return board
+}
+
+// A board that can be played through independently of this one, with the
+// same position and the moves that led to it
+func (b *Board) Copy() *Board {
+	board := *b
+	board.moveMutex = &sync.Mutex{}
+	board.history = append([]undo{}, b.history...)
+
+	board.Squares = make([]*Square, len(b.Squares))
+	for index, square := range b.Squares {
+		copied := *square
+		board.Squares[index] = &copied
+	}
+
+	return &board
 }
 
 // Returns 8 rows of 8 squares each starting at the top left and moving down
@@ -38,146 +62,18 @@ func (b Board) turnToMove() Color {
 	return colors[b.turnNumber%len(colors)]
 }
 
+// Plays the legal move described by an, returning ErrorIllegalMove or
+// ErrorAmbiguousMove if it doesn't describe exactly one
 func (b *Board) MoveFromAlgebraic(an AlgebraicNotation) (Move, error) {
 	b.moveMutex.Lock()
 	defer b.moveMutex.Unlock()
-	defer b.incrementTurnNumber()
-
-	switch {
-	case an.IsCastle():
-		return b.castle(an)
-	case an.Takes() && an.Material() == Pawn:
-		return b.pawnTakes(an)
-	default:
-		return b.moveFromAlgebraic(an)
-	}
-}
-
-func (b *Board) moveFromAlgebraic(an AlgebraicNotation) (Move, error) {
-	piece := Piece{b.turnToMove(), an.Material()}
-	destinationPosition := an.DestinationPosition()
-	destinationSquare := b.SquareAtPosition(destinationPosition)
-
-	squaresForPiece := b.SquaresForPiece(piece)
-
-	var fromSquare *Square
-
-OriginSquareSearch:
-	for _, squareForPiece := range squaresForPiece {
-		possiblePaths := squareForPiece.possiblePaths()
-		for _, path := range possiblePaths {
-			for _, position := range path {
-				if position == destinationPosition {
-					fromSquare = squareForPiece
-					if an.OriginDisambiguated() {
-						if squareForPiece.File == an.OriginFile() ||
-							squareForPiece.Rank == an.OriginRank() {
-							break OriginSquareSearch
-						}
-					} else {
-						break OriginSquareSearch
-					}
-				}
-			}
-		}
-	}
-
-	if fromSquare == nil {
-		panic(string(an))
-	}
-
-	move := Move{
-		From:  fromSquare.Position,
-		To:    destinationPosition,
-		Piece: piece,
-	}
-
-	pieceToMove := fromSquare.Piece
-	fromSquare.Piece = NoPiece
-	destinationSquare.Piece = pieceToMove
-
-	return move, nil
-}
-
-func (b *Board) castle(an AlgebraicNotation) (Move, error) {
-	var rank Rank
-
-	if b.turnToMove() == White {
-		rank = 1
-	} else {
-		rank = 8
-	}
-
-	var kingFrom, kingTo, rookFrom, rookTo Position
-
-	// TODO Moves usually only move one piece. Deal with Move struct
-	// encoding that both King and Rook moved?
-
-	switch {
-	case an.IsCastleKingSide():
-		kingFrom = Position{E, rank}
-		kingTo = Position{G, rank}
-		rookFrom = Position{H, rank}
-		rookTo = Position{F, rank}
-	case an.IsCastleQueenSide():
-		kingFrom = Position{E, rank}
-		kingTo = Position{C, rank}
-		rookFrom = Position{A, rank}
-		rookTo = Position{D, rank}
-	}
-
-	move := Move{
-		From:  kingFrom,
-		To:    kingTo,
-		Piece: Piece{b.turnToMove(), King},
-	}
-
-	b.SquareAtPosition(kingFrom).Piece = NoPiece
-	b.SquareAtPosition(rookFrom).Piece = NoPiece
-
-	b.SquareAtPosition(kingTo).Piece = Piece{b.turnToMove(), King}
-	b.SquareAtPosition(rookTo).Piece = Piece{b.turnToMove(), Rook}
-
-	return move, nil
-}
-
-// TODO Handle en passant
-func (b *Board) pawnTakes(an AlgebraicNotation) (Move, error) {
-	piece := Piece{b.turnToMove(), an.Material()}
-	destinationPosition := an.DestinationPosition()
-	destinationSquare := b.SquareAtPosition(destinationPosition)
-
-	squaresForPiece := b.SquaresForPiece(piece)
-
-	var fromSquare *Square
-OriginSquareSearch:
-	for _, squareForPiece := range squaresForPiece {
-		pathsToTake := squareForPiece.pathsToTake()
-		for _, path := range pathsToTake {
-			position := path[0]
-			if position == destinationPosition {
-				fromSquare = squareForPiece
-				if an.OriginDisambiguated() {
-					if squareForPiece.File == an.OriginFile() ||
-						squareForPiece.Rank == an.OriginRank() {
-						break OriginSquareSearch
-					}
-				} else {
-					break OriginSquareSearch
-				}
-			}
-		}
-	}
 
-	move := Move{
-		From:  fromSquare.Position,
-		To:    destinationPosition,
-		Piece: piece,
+	move, err := b.ResolveAlgebraic(an)
+	if err != nil {
+		return move, err
 	}
 
-	pieceToMove := fromSquare.Piece
-	fromSquare.Piece = NoPiece
-	destinationSquare.Piece = pieceToMove
+	b.MakeMove(move)
 
 	return move, nil
 }
@@ -206,9 +102,10 @@ func (b Board) SquaresForPiece(piece Piece) []*Square {
 
 type Move struct {
 	Piece
-	From  Position
-	To    Position
-	Takes bool
+	From      Position
+	To        Position
+	Takes     bool
+	Promotion Material // Zero unless a pawn promotes
 }
 
 type Game struct {
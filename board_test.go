@@ -24,6 +24,26 @@ func (s *BoardTestSuite) TestNewBoard() {
 	s.Equal(s.board.turnToMove(), White)
 }
 
+func (s *BoardTestSuite) TestCopy() {
+	for _, an := range []AlgebraicNotation{"e4", "e5", "Nf3"} {
+		_, err := s.board.MoveFromAlgebraic(an)
+		s.Require().Nil(err)
+	}
+
+	copied := s.board.Copy()
+	s.Equal(s.board.FEN(), copied.FEN())
+	s.Equal(s.board.Hash(), copied.Hash())
+
+	_, err := copied.MoveFromAlgebraic("Nc6")
+	s.Require().Nil(err)
+	s.NotEqual(s.board.FEN(), copied.FEN(), "moves on the copy leave the board alone")
+
+	copied.UnmakeMove()
+	copied.UnmakeMove()
+	s.Equal(Knight, copied.SquareAtPosition(G1).Material, "the copy has the moves that led to it")
+	s.Equal(Knight, s.board.SquareAtPosition(F3).Material)
+}
+
 func (s *BoardTestSuite) TestRows() {
 	rows := s.board.Rows()
 
@@ -118,6 +138,19 @@ func (s *BoardTestSuite) TestSquaresForPiece() {
 	}
 }
 
+func (s *BoardTestSuite) TestMoveFromAlgebraicErrors() {
+	_, err := s.board.MoveFromAlgebraic("e5")
+	s.Equal(ErrorIllegalMove, err)
+
+	_, err = s.board.MoveFromAlgebraic("Ne2")
+	s.Equal(ErrorIllegalMove, err)
+
+	_, err = s.board.MoveFromAlgebraic("$1")
+	s.Equal(ErrorInvalidNotation, err)
+
+	s.Equal(0, s.board.turnNumber)
+}
+
 func (s *BoardTestSuite) TestMoveFromAlgebraic() {
 	// 1.c4 c6 2.g3 d5 3.Bg2 Nf6 4.Nf3 Bf5 5.cxd5 cxd5 6.O-O Nc6 7.d3 e6
 	// 8.Be3 Be7 9.Qb3 Qd7 10.Nd4 Nxd4 11.Bxd4 O-O 12.Nc3 Bg6 13.Rfd1 Bd6
@@ -281,6 +314,7 @@ func (a AlgebraicMoveAssertion) assert() {
 			From:  a.from,
 			To:    a.to,
 			Piece: a.piece,
+			Takes: a.an.Takes(),
 		},
 		move,
 	)
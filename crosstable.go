@@ -0,0 +1,460 @@
+package pawn
+
+import (
+	"html"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Ways of separating players on the same points
+type Tiebreak int
+
+const (
+	// Points scored in games between the players tied
+	DirectEncounter Tiebreak = iota
+	// The points of each opponent beaten plus half those of each drawn with
+	SonnebornBerger
+	// The sum of the opponents' points
+	Buchholz
+	// Games won
+	Wins
+)
+
+var tiebreakNames = map[Tiebreak]string{
+	DirectEncounter: "DE",
+	SonnebornBerger: "SB",
+	Buchholz:        "Buch",
+	Wins:            "Wins",
+}
+
+func (t Tiebreak) String() string {
+	return tiebreakNames[t]
+}
+
+var (
+	roundRobinTiebreaks = []Tiebreak{DirectEncounter, SonnebornBerger, Wins}
+	swissTiebreaks      = []Tiebreak{Buchholz, SonnebornBerger, DirectEncounter, Wins}
+)
+
+// A game from one player's side
+type CrosstableGame struct {
+	Round    int // Zero if the Round tag doesn't say
+	Opponent int // Index into the crosstable's Players
+	Color    Color
+	Score    float64 // 1, 0.5 or 0
+}
+
+type CrosstableEntry struct {
+	Rank      int // Players level on points and every tiebreak share a rank
+	Name      string
+	Rating    int // The latest rating tagged, or zero
+	Points    float64
+	Games     []CrosstableGame // In round order
+	Tiebreaks []float64        // In the order of the crosstable's Tiebreaks
+}
+
+// Standings of one event's games: a grid of every player against every other
+// for a round robin, otherwise Swiss standings round by round. Games without
+// a result are left out.
+type Crosstable struct {
+	Event      string
+	RoundRobin bool // Whether every player met every other
+	Rounds     int
+	Tiebreaks  []Tiebreak
+	Players    []CrosstableEntry // Highest ranked first
+}
+
+// Builds the crosstable of the games in pgns, which should be those of one
+// event. Tiebreaks default to direct encounter, Sonneborn-Berger and wins for
+// a round robin and Buchholz, Sonneborn-Berger, direct encounter and wins for
+// a Swiss.
+func NewCrosstable(pgns []PGN, tiebreaks ...Tiebreak) *Crosstable {
+	return newCrosstable(pgns, nil, tiebreaks...)
+}
+
+// Byes score their players points without a game, by name
+func newCrosstable(pgns []PGN, byes map[string]float64, tiebreaks ...Tiebreak) *Crosstable {
+	crosstable := &Crosstable{}
+	index := map[string]int{}
+
+	playerIndex := func(name string) int {
+		if _, present := index[name]; !present {
+			index[name] = len(crosstable.Players)
+			crosstable.Players = append(crosstable.Players, CrosstableEntry{Name: name})
+		}
+
+		return index[name]
+	}
+
+	for _, pgn := range pgns {
+		if crosstable.Event == "" && !unknownTag(pgn.Tags["Event"]) {
+			crosstable.Event = pgn.Tags["Event"]
+		}
+
+		if !ratedGame(pgn) {
+			continue
+		}
+
+		round := roundNumber(pgn.Tags["Round"])
+		if round > crosstable.Rounds {
+			crosstable.Rounds = round
+		}
+
+		white, black := playerIndex(pgn.Tags["White"]), playerIndex(pgn.Tags["Black"])
+		score := whiteScore(pgn.Outcome)
+
+		crosstable.addGame(white, CrosstableGame{round, black, White, score}, pgn.Tags["WhiteElo"])
+		crosstable.addGame(black, CrosstableGame{round, white, Black, 1 - score}, pgn.Tags["BlackElo"])
+	}
+
+	byeNames := []string{}
+	for name := range byes {
+		byeNames = append(byeNames, name)
+	}
+	sort.Strings(byeNames)
+
+	for _, name := range byeNames {
+		crosstable.Players[playerIndex(name)].Points += byes[name]
+	}
+
+	crosstable.RoundRobin = crosstable.everyoneMet()
+
+	crosstable.Tiebreaks = tiebreaks
+	if len(tiebreaks) == 0 {
+		crosstable.Tiebreaks = swissTiebreaks
+		if crosstable.RoundRobin {
+			crosstable.Tiebreaks = roundRobinTiebreaks
+		}
+	}
+
+	for player := range crosstable.Players {
+		entry := &crosstable.Players[player]
+		sort.SliceStable(entry.Games, func(i, j int) bool {
+			return entry.Games[i].Round < entry.Games[j].Round
+		})
+
+		for _, tiebreak := range crosstable.Tiebreaks {
+			entry.Tiebreaks = append(entry.Tiebreaks, crosstable.tiebreak(player, tiebreak))
+		}
+	}
+
+	crosstable.rank()
+
+	return crosstable
+}
+
+// The round of a Round tag such as 3 or 3.1, the latter naming a board or game
+// of a match, or zero if unknown
+func roundNumber(round string) int {
+	number, _, _ := strings.Cut(round, ".")
+	parsed, err := strconv.Atoi(number)
+	if err != nil || parsed < 0 {
+		return 0
+	}
+
+	return parsed
+}
+
+func (c *Crosstable) addGame(player int, game CrosstableGame, elo string) {
+	entry := &c.Players[player]
+	entry.Games = append(entry.Games, game)
+	entry.Points += game.Score
+
+	if rating, err := strconv.Atoi(elo); err == nil && rating > 0 {
+		entry.Rating = rating
+	}
+}
+
+func (c *Crosstable) everyoneMet() bool {
+	if len(c.Players) < 2 {
+		return false
+	}
+
+	for _, entry := range c.Players {
+		met := map[int]bool{}
+		for _, game := range entry.Games {
+			met[game.Opponent] = true
+		}
+
+		if len(met) != len(c.Players)-1 {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (c *Crosstable) tiebreak(player int, tiebreak Tiebreak) float64 {
+	entry := c.Players[player]
+	value := 0.0
+
+	for _, game := range entry.Games {
+		opponent := c.Players[game.Opponent]
+
+		switch tiebreak {
+		case DirectEncounter:
+			if opponent.Points == entry.Points {
+				value += game.Score
+			}
+		case SonnebornBerger:
+			value += game.Score * opponent.Points
+		case Buchholz:
+			value += opponent.Points
+		case Wins:
+			if game.Score == 1 {
+				value++
+			}
+		}
+	}
+
+	return value
+}
+
+// Sorts the players into standings order, renumbering the opponents of their
+// games to match
+func (c *Crosstable) rank() {
+	order := make([]int, len(c.Players))
+	for i := range order {
+		order[i] = i
+	}
+
+	sort.SliceStable(order, func(i, j int) bool {
+		return c.Players[order[i]].before(c.Players[order[j]])
+	})
+
+	renumbered := make([]int, len(order))
+	for position, player := range order {
+		renumbered[player] = position
+	}
+
+	ranked := make([]CrosstableEntry, len(c.Players))
+	for position, player := range order {
+		ranked[position] = c.Players[player]
+
+		games := make([]CrosstableGame, len(ranked[position].Games))
+		for g, game := range ranked[position].Games {
+			game.Opponent = renumbered[game.Opponent]
+			games[g] = game
+		}
+		ranked[position].Games = games
+
+		ranked[position].Rank = position + 1
+		if position > 0 && !ranked[position-1].before(ranked[position]) {
+			ranked[position].Rank = ranked[position-1].Rank
+		}
+	}
+
+	c.Players = ranked
+}
+
+// Whether a player ranks above another on points and then tiebreaks
+func (e CrosstableEntry) before(other CrosstableEntry) bool {
+	if e.Points != other.Points {
+		return e.Points > other.Points
+	}
+
+	for i := range e.Tiebreaks {
+		if e.Tiebreaks[i] != other.Tiebreaks[i] {
+			return e.Tiebreaks[i] > other.Tiebreaks[i]
+		}
+	}
+
+	return false
+}
+
+func scoreSymbol(score float64) string {
+	switch score {
+	case 1:
+		return "1"
+	case 0.5:
+		return "½"
+	}
+
+	return "0"
+}
+
+func formatPoints(points float64) string {
+	return strconv.FormatFloat(points, 'f', -1, 64)
+}
+
+// The crosstable as a header and rows of cells. A round robin's grid has a
+// cell for each opponent listing the results against them; Swiss standings
+// have one per round giving the opponent's rank, the color played and the
+// result, e.g. 12w½.
+func (c *Crosstable) table() ([]string, [][]string) {
+	header := []string{"#", "Player", "Rating"}
+
+	// Games without a round number follow the numbered rounds, as many
+	// columns as the player with the most of them needs
+	columns := c.Rounds
+	if c.RoundRobin {
+		columns = len(c.Players)
+	} else {
+		most := 0
+		for _, entry := range c.Players {
+			unnumbered := len(entry.Games)
+			if c.Rounds > 0 {
+				unnumbered = 0
+				for _, game := range entry.Games {
+					if game.Round == 0 {
+						unnumbered++
+					}
+				}
+			}
+
+			if unnumbered > most {
+				most = unnumbered
+			}
+		}
+		columns += most
+	}
+
+	for column := 1; column <= columns; column++ {
+		switch {
+		case c.RoundRobin:
+			header = append(header, strconv.Itoa(column))
+		case c.Rounds > 0 && column > c.Rounds:
+			header = append(header, "R?")
+		default:
+			header = append(header, "R"+strconv.Itoa(column))
+		}
+	}
+
+	header = append(header, "Points")
+	for _, tiebreak := range c.Tiebreaks {
+		header = append(header, tiebreak.String())
+	}
+
+	rows := [][]string{}
+	for player, entry := range c.Players {
+		rating := ""
+		if entry.Rating > 0 {
+			rating = strconv.Itoa(entry.Rating)
+		}
+
+		row := []string{strconv.Itoa(entry.Rank), entry.Name, rating}
+
+		for column := 0; column < columns; column++ {
+			row = append(row, c.cell(player, column))
+		}
+
+		row = append(row, formatPoints(entry.Points))
+		for _, value := range entry.Tiebreaks {
+			row = append(row, formatPoints(value))
+		}
+
+		rows = append(rows, row)
+	}
+
+	return header, rows
+}
+
+func (c *Crosstable) cell(player, column int) string {
+	entry := c.Players[player]
+
+	if c.RoundRobin {
+		if column == player {
+			return "X"
+		}
+
+		results := ""
+		for _, game := range entry.Games {
+			if game.Opponent == column {
+				results += scoreSymbol(game.Score)
+			}
+		}
+
+		if results == "" {
+			return "."
+		}
+		return results
+	}
+
+	unnumbered := 0
+	for index, game := range entry.Games {
+		switch {
+		case c.Rounds == 0 && index == column,
+			c.Rounds > 0 && game.Round == column+1,
+			c.Rounds > 0 && game.Round == 0 && c.Rounds+unnumbered == column:
+			return strconv.Itoa(c.Players[game.Opponent].Rank) + strings.ToLower(string(game.Color)[:1]) + scoreSymbol(game.Score)
+		}
+
+		if game.Round == 0 {
+			unnumbered++
+		}
+	}
+
+	return "."
+}
+
+// Plain text with the columns aligned, names to the left and the rest to the
+// right
+func (c *Crosstable) String() string {
+	header, rows := c.table()
+
+	widths := make([]int, len(header))
+	for _, row := range append([][]string{header}, rows...) {
+		for column, value := range row {
+			if length := len([]rune(value)); length > widths[column] {
+				widths[column] = length
+			}
+		}
+	}
+
+	var text strings.Builder
+	if c.Event != "" {
+		text.WriteString(c.Event + "\n\n")
+	}
+
+	for _, row := range append([][]string{header}, rows...) {
+		cells := []string{}
+		for column, value := range row {
+			padding := strings.Repeat(" ", widths[column]-len([]rune(value)))
+			if column == 1 {
+				cells = append(cells, value+padding)
+			} else {
+				cells = append(cells, padding+value)
+			}
+		}
+
+		text.WriteString(strings.TrimRight(strings.Join(cells, "  "), " ") + "\n")
+	}
+
+	return text.String()
+}
+
+// A standalone HTML table
+func (c *Crosstable) HTML() string {
+	header, rows := c.table()
+
+	var table strings.Builder
+	table.WriteString("<table class=\"crosstable\">\n")
+	if c.Event != "" {
+		table.WriteString("  <caption>" + html.EscapeString(c.Event) + "</caption>\n")
+	}
+
+	table.WriteString("  <thead>\n    <tr>")
+	for _, value := range header {
+		table.WriteString("<th>" + html.EscapeString(value) + "</th>")
+	}
+	table.WriteString("</tr>\n  </thead>\n  <tbody>\n")
+
+	for _, row := range rows {
+		table.WriteString("    <tr>")
+		for _, value := range row {
+			table.WriteString("<td>" + html.EscapeString(value) + "</td>")
+		}
+		table.WriteString("</tr>\n")
+	}
+
+	table.WriteString("  </tbody>\n</table>\n")
+
+	return table.String()
+}
+
+// The header and rows for writing as CSV
+func (c *Crosstable) Records() [][]string {
+	header, rows := c.table()
+	return append([][]string{header}, rows...)
+}
@@ -0,0 +1,156 @@
+package pawn
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type CrosstableTestSuite struct {
+	suite.Suite
+}
+
+func TestCrosstableTestSuite(t *testing.T) {
+	suite.Run(t, new(CrosstableTestSuite))
+}
+
+func (s *CrosstableTestSuite) game(round, white, black string, outcome Outcome) PGN {
+	pgn := ParsePGN("1. e4 e5 " + string(outcome))
+	pgn.Tags = Tags{"Event": "Club Championship", "Round": round, "White": white, "Black": black}
+
+	return pgn
+}
+
+func (s *CrosstableTestSuite) roundRobin() []PGN {
+	return []PGN{
+		s.game("1", "Ann", "Bob", WhiteWin),
+		s.game("1", "Cat", "Dan", Draw),
+		s.game("2", "Bob", "Cat", WhiteWin),
+		s.game("2", "Dan", "Ann", Draw),
+		s.game("3", "Ann", "Cat", BlackWin),
+		s.game("3", "Bob", "Dan", WhiteWin),
+	}
+}
+
+func (s *CrosstableTestSuite) TestRoundRobin() {
+	crosstable := NewCrosstable(s.roundRobin())
+
+	s.True(crosstable.RoundRobin)
+	s.Equal("Club Championship", crosstable.Event)
+	s.Equal(3, crosstable.Rounds)
+	s.Equal([]Tiebreak{DirectEncounter, SonnebornBerger, Wins}, crosstable.Tiebreaks)
+
+	names, points := []string{}, []float64{}
+	for _, entry := range crosstable.Players {
+		names = append(names, entry.Name)
+		points = append(points, entry.Points)
+	}
+
+	// Cat beat Ann, who's also on 1½
+	s.Equal([]string{"Bob", "Cat", "Ann", "Dan"}, names)
+	s.Equal([]float64{2, 1.5, 1.5, 1}, points)
+
+	// Cat drew with Dan on 1 and beat Ann on 1½
+	cat := crosstable.Players[1]
+	s.Equal([]float64{1, 2, 1}, cat.Tiebreaks)
+	s.Equal(CrosstableGame{Round: 3, Opponent: 2, Color: Black, Score: 1}, cat.Games[2])
+}
+
+func (s *CrosstableTestSuite) TestSharedRank() {
+	crosstable := NewCrosstable([]PGN{s.game("1", "Ann", "Bob", Draw)})
+
+	s.Equal(1, crosstable.Players[0].Rank)
+	s.Equal(1, crosstable.Players[1].Rank)
+}
+
+func (s *CrosstableTestSuite) TestRoundRobinGrid() {
+	crosstable := NewCrosstable(append(s.roundRobin(), s.game("4", "Dan", "Cat", WhiteWin)))
+
+	s.Equal(strings.Join([]string{
+		"Club Championship",
+		"",
+		"#  Player  Rating  1   2   3  4  Points  DE   SB  Wins",
+		"1  Bob             X   1   1  0       2   1  3.5     2",
+		"2  Dan             0   X  ½1  ½       2   0    3     1",
+		"3  Cat             0  ½0   X  1     1.5   1  2.5     1",
+		"4  Ann             1   ½   0  X     1.5   0    3     1",
+		"",
+	}, "\n"), crosstable.String())
+
+	s.Equal([]string{"2", "Dan", "", "0", "X", "½1", "½", "2", "0", "3", "1"}, crosstable.Records()[2])
+
+	html := crosstable.HTML()
+	s.Contains(html, "<caption>Club Championship</caption>")
+	s.Contains(html, "<tr><td>1</td><td>Bob</td><td></td><td>X</td><td>1</td>")
+}
+
+func (s *CrosstableTestSuite) TestSwiss() {
+	pgns := []PGN{
+		s.game("1", "Ann", "Bob", WhiteWin),
+		s.game("1", "Cat", "Dan", WhiteWin),
+		s.game("1", "Eve", "Fay", Draw),
+		s.game("2", "Cat", "Ann", BlackWin),
+		s.game("2", "Bob", "Eve", WhiteWin),
+		s.game("2", "Fay", "Dan", Ongoing),
+	}
+	pgns[0].Tags["WhiteElo"] = "2100"
+
+	crosstable := NewCrosstable(pgns)
+
+	s.False(crosstable.RoundRobin)
+	s.Equal([]Tiebreak{Buchholz, SonnebornBerger, DirectEncounter, Wins}, crosstable.Tiebreaks)
+
+	ann := crosstable.Players[0]
+	s.Equal("Ann", ann.Name)
+	s.Equal(2100, ann.Rating)
+	s.Equal(2.0, ann.Points)
+	s.Equal(2.0, ann.Tiebreaks[0], "Bob 1, Cat 1")
+
+	// Bob and Cat on 1 point: Bob's opponents Ann 2 and Eve ½ outscore Cat's,
+	// Dan 0 and Ann 2
+	s.Equal("Bob", crosstable.Players[1].Name)
+	s.Equal("Cat", crosstable.Players[2].Name)
+	s.Equal([]string{"2", "Bob", "", "1b0", "4w1", "1", "2.5", "0.5", "0", "1"}, crosstable.Records()[2])
+
+	// Fay's unfinished game is left out
+	fay := crosstable.Players[len(crosstable.Players)-1]
+	s.Len(fay.Games, 1)
+	s.Equal(".", crosstable.Records()[len(crosstable.Players)][4])
+}
+
+func (s *CrosstableTestSuite) TestUnnumberedRound() {
+	crosstable := NewCrosstable([]PGN{
+		s.game("1", "Ann", "Bob", WhiteWin),
+		s.game("1", "Cat", "Dan", WhiteWin),
+		s.game("?", "Ann", "Cat", Draw),
+		s.game("2", "Bob", "Dan", Draw),
+	})
+	records := crosstable.Records()
+
+	s.Equal(2, crosstable.Rounds)
+	s.Equal([]string{"R1", "R2", "R?", "Points"}, records[0][3:7])
+
+	// Every game counted in the points has a cell
+	for player, entry := range crosstable.Players {
+		cells := 0
+		for _, cell := range records[player+1][3:6] {
+			if cell != "." {
+				cells++
+			}
+		}
+
+		s.Equal(len(entry.Games), cells, entry.Name)
+	}
+
+	ann := records[1]
+	s.Equal("Ann", ann[1])
+	s.Equal([]string{".", "w½"}, []string{ann[4], ann[5][1:]})
+}
+
+func (s *CrosstableTestSuite) TestRoundNumber() {
+	s.Equal(3, roundNumber("3"))
+	s.Equal(12, roundNumber("12.4"))
+	s.Equal(0, roundNumber("?"))
+	s.Equal(0, roundNumber(""))
+}
@@ -0,0 +1,353 @@
+package pawn
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+)
+
+var (
+	ErrorInvalidDatabase = errors.New("pawn: not a pawn database")
+	ErrorNoSuchGame      = errors.New("pawn: no such game in database")
+)
+
+// A database file starts with databaseMagic and the offset of its index. The
+// moves of every game follow, each game as a uvarint ply count and then one
+// byte per move as written by EncodeMoves. The index at the end holds every
+// tag name and value once in a string table followed by each game's tags as
+// string table references, its outcome and the offset of its moves.
+const (
+	databaseMagic      = "PAWNDB\x00\x01"
+	databaseHeaderSize = len(databaseMagic) + 8
+)
+
+// Converts games to database format, writing their tags, outcome and main
+// line. Comments and variations aren't kept. Fails on the first game with an
+// illegal move.
+func WriteDatabase(w io.Writer, pgns []PGN) error {
+	var moves bytes.Buffer
+	index := databaseIndex{strings: []string{}, ids: map[string]uint64{}}
+
+	for number, pgn := range pgns {
+		game, err := pgn.Game()
+		if err != nil {
+			return fmt.Errorf("game %d: %w", number+1, err)
+		}
+
+		encoded, err := EncodeMoves(game.Moves)
+		if err != nil {
+			return fmt.Errorf("game %d: %w", number+1, err)
+		}
+
+		index.addGame(pgn, uint64(moves.Len()))
+		moves.Write(binary.AppendUvarint(nil, uint64(len(encoded))))
+		moves.Write(encoded)
+	}
+
+	header := make([]byte, databaseHeaderSize)
+	copy(header, databaseMagic)
+	binary.BigEndian.PutUint64(header[len(databaseMagic):], uint64(databaseHeaderSize+moves.Len()))
+
+	out := bufio.NewWriter(w)
+	out.Write(header)
+	out.Write(moves.Bytes())
+	out.Write(index.bytes())
+
+	return out.Flush()
+}
+
+type databaseIndex struct {
+	strings []string
+	ids     map[string]uint64
+	games   []byte
+	count   int
+}
+
+func (d *databaseIndex) intern(str string) uint64 {
+	id, seen := d.ids[str]
+	if !seen {
+		id = uint64(len(d.strings))
+		d.strings = append(d.strings, str)
+		d.ids[str] = id
+	}
+
+	return id
+}
+
+func (d *databaseIndex) addGame(pgn PGN, offset uint64) {
+	names := make([]string, 0, len(pgn.Tags))
+	for name := range pgn.Tags {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	d.games = binary.AppendUvarint(d.games, offset)
+	d.games = binary.AppendUvarint(d.games, d.intern(string(pgn.Outcome)))
+	d.games = binary.AppendUvarint(d.games, uint64(len(names)))
+
+	for _, name := range names {
+		d.games = binary.AppendUvarint(d.games, d.intern(name))
+		d.games = binary.AppendUvarint(d.games, d.intern(pgn.Tags[name]))
+	}
+
+	d.count++
+}
+
+func (d *databaseIndex) bytes() []byte {
+	encoded := binary.AppendUvarint(nil, uint64(len(d.strings)))
+	for _, str := range d.strings {
+		encoded = binary.AppendUvarint(encoded, uint64(len(str)))
+		encoded = append(encoded, str...)
+	}
+
+	encoded = binary.AppendUvarint(encoded, uint64(d.count))
+
+	return append(encoded, d.games...)
+}
+
+// A database opened for reading. Only the index is read up front; each
+// game's moves are read and decoded when asked for.
+type Database struct {
+	reader  io.ReaderAt
+	closer  io.Closer
+	strings []string
+	games   []databaseGame
+}
+
+type databaseGame struct {
+	tags    [][2]uint64 // Names and values as string table ids
+	outcome uint64
+	offset  int64 // Of the game's moves from the start of the file
+	length  int64
+}
+
+// Opens a database file, which should be closed when done with
+func OpenDatabase(path string) (*Database, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+
+	database, err := NewDatabase(file)
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+	database.closer = file
+
+	return database, nil
+}
+
+// Reads the index of a database in r
+func NewDatabase(r io.ReaderAt) (*Database, error) {
+	header := make([]byte, databaseHeaderSize)
+	if _, err := r.ReadAt(header, 0); err != nil || string(header[:len(databaseMagic)]) != databaseMagic {
+		return nil, ErrorInvalidDatabase
+	}
+
+	indexOffset := int64(binary.BigEndian.Uint64(header[len(databaseMagic):]))
+	if indexOffset < int64(databaseHeaderSize) {
+		return nil, ErrorInvalidDatabase
+	}
+
+	index, err := io.ReadAll(io.NewSectionReader(r, indexOffset, 1<<62))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrorInvalidDatabase, err)
+	}
+
+	database := &Database{reader: r}
+	if err := database.readIndex(bytes.NewReader(index)); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrorInvalidDatabase, err)
+	}
+
+	for i := range database.games {
+		end := indexOffset
+		if i+1 < len(database.games) {
+			end = database.games[i+1].offset
+		}
+
+		database.games[i].length = end - database.games[i].offset
+		if database.games[i].length < 0 {
+			return nil, ErrorInvalidDatabase
+		}
+	}
+
+	return database, nil
+}
+
+// Reads the index, checking every count against the bytes left before
+// allocating for it, so a corrupt one can't ask for more memory than the
+// file's size. Each string, game and tag takes at least a byte.
+func (d *Database) readIndex(r *bytes.Reader) error {
+	count, err := binary.ReadUvarint(r)
+	if err != nil {
+		return err
+	}
+	if count > uint64(r.Len()) {
+		return errors.New("too many strings")
+	}
+
+	d.strings = make([]string, 0, count)
+	for i := uint64(0); i < count; i++ {
+		length, err := binary.ReadUvarint(r)
+		if err != nil {
+			return err
+		}
+		if length > uint64(r.Len()) {
+			return errors.New("string too long")
+		}
+
+		str := make([]byte, length)
+		if _, err := io.ReadFull(r, str); err != nil {
+			return err
+		}
+		d.strings = append(d.strings, string(str))
+	}
+
+	games, err := binary.ReadUvarint(r)
+	if err != nil {
+		return err
+	}
+	if games > uint64(r.Len()) {
+		return errors.New("too many games")
+	}
+
+	d.games = make([]databaseGame, 0, games)
+	for i := uint64(0); i < games; i++ {
+		var values [3]uint64
+		for v := range values {
+			if values[v], err = binary.ReadUvarint(r); err != nil {
+				return err
+			}
+		}
+
+		if values[2] > uint64(r.Len()) {
+			return errors.New("too many tags")
+		}
+
+		game := databaseGame{
+			offset:  int64(databaseHeaderSize) + int64(values[0]),
+			outcome: values[1],
+			tags:    make([][2]uint64, values[2]),
+		}
+
+		for t := range game.tags {
+			for n := range game.tags[t] {
+				if game.tags[t][n], err = binary.ReadUvarint(r); err != nil {
+					return err
+				}
+			}
+		}
+
+		d.games = append(d.games, game)
+	}
+
+	return d.checkIds()
+}
+
+func (d *Database) checkIds() error {
+	strings := uint64(len(d.strings))
+
+	for _, game := range d.games {
+		if game.outcome >= strings {
+			return errors.New("outcome out of range")
+		}
+
+		for _, tag := range game.tags {
+			if tag[0] >= strings || tag[1] >= strings {
+				return errors.New("tag out of range")
+			}
+		}
+	}
+
+	return nil
+}
+
+func (d *Database) Close() error {
+	if d.closer == nil {
+		return nil
+	}
+
+	return d.closer.Close()
+}
+
+// The number of games in the database
+func (d *Database) Len() int {
+	return len(d.games)
+}
+
+// The tags of a game, counted from 0, without reading its moves
+func (d *Database) Tags(game int) Tags {
+	tags := Tags{}
+	if game < 0 || game >= len(d.games) {
+		return tags
+	}
+
+	for _, tag := range d.games[game].tags {
+		tags[d.strings[tag[0]]] = d.strings[tag[1]]
+	}
+
+	return tags
+}
+
+// Reads and decodes the moves of a game
+func (d *Database) Game(game int) (Game, error) {
+	if game < 0 || game >= len(d.games) {
+		return Game{}, ErrorNoSuchGame
+	}
+
+	entry := d.games[game]
+	encoded := make([]byte, entry.length)
+	if _, err := d.reader.ReadAt(encoded, entry.offset); err != nil {
+		return Game{}, err
+	}
+
+	plies, read := binary.Uvarint(encoded)
+	if read <= 0 || uint64(len(encoded)-read) != plies {
+		return Game{}, fmt.Errorf("%w: game %d's moves are corrupt", ErrorInvalidDatabase, game+1)
+	}
+
+	moves, board, err := decodeMoves(encoded[read:])
+	if err != nil {
+		return Game{}, fmt.Errorf("%w: game %d's moves are corrupt", ErrorInvalidDatabase, game+1)
+	}
+
+	return Game{Board: *board, Moves: moves}, nil
+}
+
+// Reads a game and writes its moves back out in Standard Algebraic Notation
+func (d *Database) PGN(game int) (PGN, error) {
+	decoded, err := d.Game(game)
+	if err != nil {
+		return PGN{}, err
+	}
+
+	return PGN{
+		Tags:     d.Tags(game),
+		Movetext: movetextFor(decoded.Moves),
+		Outcome:  Outcome(d.strings[d.games[game].outcome]),
+	}, nil
+}
+
+func movetextFor(moves []Move) Movetext {
+	movetext := Movetext{Moves: []*MovetextMove{}}
+	board := NewBoard()
+
+	for ply, move := range moves {
+		an := board.Algebraic(move)
+		board.MakeMove(move)
+
+		if ply%2 == 0 {
+			movetext.Moves = append(movetext.Moves, &MovetextMove{Number: uint8(ply/2 + 1), WhiteMove: an})
+		} else {
+			movetext.Moves[len(movetext.Moves)-1].BlackMove = an
+		}
+	}
+
+	return movetext
+}
@@ -0,0 +1,118 @@
+package pawn
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type DatabaseTestSuite struct {
+	suite.Suite
+	pgns     []PGN
+	database *Database
+	size     int
+}
+
+func TestDatabaseTestSuite(t *testing.T) {
+	suite.Run(t, new(DatabaseTestSuite))
+}
+
+func (s *DatabaseTestSuite) SetupTest() {
+	s.pgns = NewPGNParserFromReader(strings.NewReader(win + draw + finalMoveByWhite + multipleEntries + ongoing)).ParseAll()
+
+	var buffer bytes.Buffer
+	s.Require().Nil(WriteDatabase(&buffer, s.pgns))
+	s.size = buffer.Len()
+
+	database, err := NewDatabase(bytes.NewReader(buffer.Bytes()))
+	s.Require().Nil(err)
+	s.database = database
+}
+
+func (s *DatabaseTestSuite) TestRoundTrip() {
+	s.Equal(len(s.pgns), s.database.Len())
+
+	for index, pgn := range s.pgns {
+		s.Equal(pgn.Tags, s.database.Tags(index))
+
+		original, err := pgn.Game()
+		s.Nil(err)
+
+		decoded, err := s.database.Game(index)
+		s.Nil(err)
+		s.Equal(original.Moves, decoded.Moves)
+		s.Equal(original.FEN(), decoded.FEN())
+
+		read, err := s.database.PGN(index)
+		s.Nil(err)
+		s.Equal(pgn.Outcome, read.Outcome)
+
+		replayed, err := read.Game()
+		s.Nil(err)
+		s.Equal(original.Moves, replayed.Moves)
+	}
+}
+
+func (s *DatabaseTestSuite) TestCompact() {
+	text := len(win + draw + finalMoveByWhite + multipleEntries + ongoing)
+	s.True(s.size < text/2, "%d bytes for %d of PGN", s.size, text)
+}
+
+func (s *DatabaseTestSuite) TestMissingGame() {
+	_, err := s.database.Game(len(s.pgns))
+	s.Equal(ErrorNoSuchGame, err)
+	s.Empty(s.database.Tags(-1))
+}
+
+func (s *DatabaseTestSuite) TestInvalid() {
+	_, err := NewDatabase(strings.NewReader("[Event \"Not a database\"]"))
+	s.Equal(ErrorInvalidDatabase, err)
+
+	var buffer bytes.Buffer
+	s.Nil(WriteDatabase(&buffer, s.pgns))
+	truncated := buffer.Bytes()[:buffer.Len()-3]
+
+	_, err = NewDatabase(bytes.NewReader(truncated))
+	s.True(errors.Is(err, ErrorInvalidDatabase))
+}
+
+func (s *DatabaseTestSuite) TestCorrupt() {
+	index := func(values ...uint64) []byte {
+		encoded := []byte(databaseMagic + "\x00\x00\x00\x00\x00\x00\x00\x10")
+		for _, value := range values {
+			encoded = binary.AppendUvarint(encoded, value)
+		}
+
+		return encoded
+	}
+
+	for name, corrupt := range map[string][]byte{
+		"strings":       index(1 << 60),
+		"string length": index(1, 1<<60),
+		"games":         index(0, 1<<60),
+		"tags":          index(1, 0, 1, 0, 0, 1<<60),
+	} {
+		_, err := NewDatabase(bytes.NewReader(corrupt))
+		s.True(errors.Is(err, ErrorInvalidDatabase), name)
+	}
+
+	// Cut short anywhere
+	var buffer bytes.Buffer
+	s.Nil(WriteDatabase(&buffer, s.pgns))
+	for length := 0; length < buffer.Len(); length++ {
+		_, err := NewDatabase(bytes.NewReader(buffer.Bytes()[:length]))
+		s.True(errors.Is(err, ErrorInvalidDatabase), "%d bytes", length)
+	}
+}
+
+func (s *DatabaseTestSuite) TestIllegalGame() {
+	var buffer bytes.Buffer
+	err := WriteDatabase(&buffer, []PGN{ParsePGN(win), ParsePGN("[Result \"*\"]\n\n1.e4 e5 2.Ke3 *")})
+
+	s.True(errors.Is(err, ErrorIllegalMove))
+	s.Contains(err.Error(), "game 2")
+}
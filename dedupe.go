@@ -0,0 +1,359 @@
+package pawn
+
+import (
+	"encoding/binary"
+	"hash/fnv"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// Hashes a game's moves so copies of the same game from different sources
+// collide. Moves are replayed and hashed as the moves they resolve to, so
+// notation such as 0-0 for O-O or needless disambiguation doesn't matter.
+// From an illegal move on, they're hashed as written less check, mate and
+// annotation symbols and the = of promotions.
+func MoveSequenceHash(pgn PGN) uint64 {
+	hash := fnv.New64a()
+	board := NewBoard()
+	replaying := true
+	encoded := make([]byte, 2)
+
+	for _, an := range pgn.Turns() {
+		if an == "" {
+			continue
+		}
+
+		if replaying {
+			move, err := board.ResolveAlgebraic(an)
+			if err == nil {
+				binary.BigEndian.PutUint16(encoded, uint16(board.EncodeMove(move)))
+				hash.Write(encoded)
+				board.MakeMove(move)
+				continue
+			}
+
+			replaying = false
+		}
+
+		hash.Write([]byte(strings.Map(func(r rune) rune {
+			if strings.ContainsRune("+#!?=", r) {
+				return -1
+			}
+			return r
+		}, string(an))))
+		hash.Write([]byte{' '})
+	}
+
+	return hash.Sum64()
+}
+
+// Whether two games are copies of the same game: their moves are the same
+// and their players, date, event and result don't contradict each other.
+// Tags compare loosely so "Carlsen, M." matches "Magnus Carlsen" and a date
+// of 2019.??.?? matches 2019.05.21.
+func IsDuplicate(a, b PGN) bool {
+	return MoveSequenceHash(a) == MoveSequenceHash(b) && similarTags(a, b)
+}
+
+func similarTags(a, b PGN) bool {
+	return similarNames(a.Tags["White"], b.Tags["White"]) &&
+		similarNames(a.Tags["Black"], b.Tags["Black"]) &&
+		similarDates(a.Tags["Date"], b.Tags["Date"]) &&
+		similarEvents(a.Tags["Event"], b.Tags["Event"]) &&
+		similarOutcomes(a.Outcome, b.Outcome)
+}
+
+func unknownTag(value string) bool {
+	return strings.Trim(value, "?. ") == ""
+}
+
+// Lowercase words of a name or event with punctuation dropped
+func nameTokens(value string) []string {
+	return strings.FieldsFunc(strings.ToLower(value), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// Names match when they share a word longer than an initial, taken to be the
+// surname, and every initial in either matches the first letter of a word in
+// the other. A bare surname matches any first name.
+func similarNames(a, b string) bool {
+	if unknownTag(a) || unknownTag(b) {
+		return true
+	}
+
+	aTokens, bTokens := nameTokens(a), nameTokens(b)
+
+	shared := false
+	for _, token := range aTokens {
+		if len(token) > 1 && containsToken(bTokens, token) {
+			shared = true
+		}
+	}
+
+	return shared && initialsMatch(aTokens, bTokens) && initialsMatch(bTokens, aTokens)
+}
+
+func containsToken(tokens []string, token string) bool {
+	for _, other := range tokens {
+		if other == token {
+			return true
+		}
+	}
+
+	return false
+}
+
+func initialsMatch(initials, words []string) bool {
+	if len(words) < 2 {
+		return true
+	}
+
+	for _, initial := range initials {
+		if len(initial) != 1 {
+			continue
+		}
+
+		matched := false
+		for _, word := range words {
+			if strings.HasPrefix(word, initial) {
+				matched = true
+			}
+		}
+
+		if !matched {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Dates match when each of their year, month and day match or either is
+// unknown
+func similarDates(a, b string) bool {
+	aParts, bParts := strings.Split(a, "."), strings.Split(b, ".")
+
+	for i := 0; i < len(aParts) && i < len(bParts); i++ {
+		if !unknownTag(aParts[i]) && !unknownTag(bParts[i]) && aParts[i] != bParts[i] {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Events match when either is unknown, one names the other, e.g. "Tata Steel"
+// and "Tata Steel Masters", or at least half their words are shared
+func similarEvents(a, b string) bool {
+	if unknownTag(a) || unknownTag(b) {
+		return true
+	}
+
+	aTokens, bTokens := nameTokens(a), nameTokens(b)
+	aJoined, bJoined := strings.Join(aTokens, " "), strings.Join(bTokens, " ")
+	if strings.Contains(aJoined, bJoined) || strings.Contains(bJoined, aJoined) {
+		return true
+	}
+
+	shared := 0
+	for _, token := range aTokens {
+		if containsToken(bTokens, token) {
+			shared++
+		}
+	}
+
+	return 2*shared >= len(aTokens) && 2*shared >= len(bTokens)
+}
+
+func similarOutcomes(a, b Outcome) bool {
+	return a == b || a == "" || a == Ongoing || b == "" || b == Ongoing
+}
+
+// Groups games that are copies of one another. Each group lists the indexes
+// of two or more games in order; groups are ordered by their first game.
+func FindDuplicates(pgns []PGN) [][]int {
+	buckets := map[uint64][]int{}
+	hashes := []uint64{}
+
+	for index, pgn := range pgns {
+		hash := MoveSequenceHash(pgn)
+		if _, seen := buckets[hash]; !seen {
+			hashes = append(hashes, hash)
+		}
+		buckets[hash] = append(buckets[hash], index)
+	}
+
+	groups := [][]int{}
+	for _, hash := range hashes {
+		groups = append(groups, clusterDuplicates(pgns, buckets[hash])...)
+	}
+
+	sort.Slice(groups, func(i, j int) bool {
+		return groups[i][0] < groups[j][0]
+	})
+
+	return groups
+}
+
+// Clusters games with the same moves, each joining the first cluster whose
+// every game has similar tags to it
+func clusterDuplicates(pgns []PGN, indexes []int) [][]int {
+	clusters := [][]int{}
+
+next:
+	for _, index := range indexes {
+		for c, cluster := range clusters {
+			joins := true
+			for _, member := range cluster {
+				joins = joins && similarTags(pgns[member], pgns[index])
+			}
+
+			if joins {
+				clusters[c] = append(cluster, index)
+				continue next
+			}
+		}
+
+		clusters = append(clusters, []int{index})
+	}
+
+	groups := [][]int{}
+	for _, cluster := range clusters {
+		if len(cluster) > 1 {
+			groups = append(groups, cluster)
+		}
+	}
+
+	return groups
+}
+
+// Combines copies of a game into one. The movetext is that of the copy with
+// the most comments, with comments from the others filling moves it leaves
+// bare. Each tag takes its most complete value among the copies and a
+// decided result wins over an unknown one.
+func MergeDuplicates(group []PGN) PGN {
+	if len(group) == 0 {
+		return NewPGN()
+	}
+
+	base := group[0]
+	for _, pgn := range group[1:] {
+		if pgn.commentCount() > base.commentCount() {
+			base = pgn
+		}
+	}
+
+	merged := PGN{Tags: Tags{}, Movetext: base.Movetext.copy(), Outcome: base.Outcome}
+
+	for _, pgn := range group {
+		merged.Movetext.fillComments(pgn.Movetext)
+
+		for tag, value := range pgn.Tags {
+			if current, present := merged.Tags[tag]; !present || moreComplete(value, current) {
+				merged.Tags[tag] = value
+			}
+		}
+
+		if pgn.Outcome != "" && pgn.Outcome != Ongoing {
+			merged.Outcome = pgn.Outcome
+		}
+	}
+
+	if _, present := merged.Tags["Result"]; present && merged.Outcome != "" {
+		merged.Tags["Result"] = string(merged.Outcome)
+	}
+
+	return merged
+}
+
+// Whether a tag value says more than another: a known value over an unknown
+// one, then fewer unknown ? parts, then the longer
+func moreComplete(value, than string) bool {
+	if unknownTag(value) != unknownTag(than) {
+		return unknownTag(than)
+	}
+
+	if unknowns, other := strings.Count(value, "?"), strings.Count(than, "?"); unknowns != other {
+		return unknowns < other
+	}
+
+	return len(value) > len(than)
+}
+
+func (m Movetext) commentCount() int {
+	count := 0
+	if m.Comment != "" {
+		count++
+	}
+
+	for _, move := range m.Moves {
+		if move.WhiteComment != "" {
+			count++
+		}
+		if move.BlackComment != "" {
+			count++
+		}
+	}
+
+	return count
+}
+
+func (m Movetext) copy() Movetext {
+	copied := Movetext{Moves: make([]*MovetextMove, len(m.Moves)), Comment: m.Comment}
+	for i, move := range m.Moves {
+		moveCopy := *move
+		copied.Moves[i] = &moveCopy
+	}
+
+	return copied
+}
+
+// Copies comments from other onto the same moves where m has none
+func (m *Movetext) fillComments(other Movetext) {
+	if m.Comment == "" {
+		m.Comment = other.Comment
+	}
+
+	for i := 0; i < len(m.Moves) && i < len(other.Moves); i++ {
+		if m.Moves[i].WhiteComment == "" {
+			m.Moves[i].WhiteComment = other.Moves[i].WhiteComment
+		}
+		if m.Moves[i].BlackComment == "" {
+			m.Moves[i].BlackComment = other.Moves[i].BlackComment
+		}
+	}
+}
+
+// Replaces each group of duplicates with their merge, in place of the group's
+// first game, returning the games left and how many were removed
+func Dedupe(pgns []PGN) ([]PGN, int) {
+	merged := map[int]PGN{}
+	removed := map[int]bool{}
+
+	for _, group := range FindDuplicates(pgns) {
+		copies := []PGN{}
+		for _, index := range group {
+			copies = append(copies, pgns[index])
+			removed[index] = true
+		}
+
+		merged[group[0]] = MergeDuplicates(copies)
+		delete(removed, group[0])
+	}
+
+	deduped := []PGN{}
+	for index, pgn := range pgns {
+		if removed[index] {
+			continue
+		}
+
+		if merge, present := merged[index]; present {
+			pgn = merge
+		}
+		deduped = append(deduped, pgn)
+	}
+
+	return deduped, len(removed)
+}
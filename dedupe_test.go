@@ -0,0 +1,114 @@
+package pawn
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type DedupeTestSuite struct {
+	suite.Suite
+}
+
+func TestDedupeTestSuite(t *testing.T) {
+	suite.Run(t, new(DedupeTestSuite))
+}
+
+func (s *DedupeTestSuite) TestMoveSequenceHash() {
+	s.Equal(
+		MoveSequenceHash(ParsePGN("1. e4 e5 2. Qh5 Nc6 3. Bc4 Nf6 4. Qxf7# 1-0")),
+		MoveSequenceHash(ParsePGN("1.e4 {Scholar's mate} e5 2.Qh5 Nc6 3.Bc4 Nf6? 4.Qxf7+ 1-0")),
+	)
+
+	s.NotEqual(
+		MoveSequenceHash(ParsePGN("1. e4 e5 2. Nf3 *")),
+		MoveSequenceHash(ParsePGN("1. e4 e5 *")),
+	)
+
+	// Notation that differs for the same moves
+	s.Equal(
+		MoveSequenceHash(ParsePGN("1. e4 e5 2. Nf3 Nc6 3. Bc4 Bc5 4. O-O Nf6 5. d3 d6 6. Nbc3 *")),
+		MoveSequenceHash(ParsePGN("1. e4 e5 2. Nf3 Nc6 3. Bc4 Bc5 4. 0-0 Nf6 5. d3 d6 6. Nc3 *")),
+	)
+
+	// Games with an illegal move still hash by what follows it
+	s.NotEqual(
+		MoveSequenceHash(ParsePGN("1. e4 e5 2. Ke3 Nc6 *")),
+		MoveSequenceHash(ParsePGN("1. e4 e5 2. Ke3 Nf6 *")),
+	)
+}
+
+func (s *DedupeTestSuite) TestSimilarNames() {
+	s.True(similarNames("Carlsen, Magnus", "Magnus Carlsen"))
+	s.True(similarNames("Carlsen,M", "Carlsen, Magnus"))
+	s.True(similarNames("Carlsen", "?"))
+	s.True(similarNames("Carlsen", "Carlsen, Magnus"))
+	s.False(similarNames("Carlsen,H", "Carlsen, Magnus"))
+	s.False(similarNames("Carlsen, Magnus", "Anand, Viswanathan"))
+}
+
+func (s *DedupeTestSuite) TestSimilarDates() {
+	s.True(similarDates("2013.11.22", "2013.11.22"))
+	s.True(similarDates("2013.??.??", "2013.11.22"))
+	s.True(similarDates("????.??.??", "2013.11.22"))
+	s.True(similarDates("", "2013.11.22"))
+	s.False(similarDates("2013.11.21", "2013.11.22"))
+}
+
+func (s *DedupeTestSuite) TestSimilarEvents() {
+	s.True(similarEvents("Tata Steel", "Tata Steel Masters"))
+	s.True(similarEvents("WCh 2013", "?"))
+	s.True(similarEvents("World Championship 2013", "2013 World Championship Match"))
+	s.False(similarEvents("Tata Steel", "Norway Chess"))
+}
+
+func (s *DedupeTestSuite) TestIsDuplicate() {
+	original := ParsePGN(win)
+
+	copied := ParsePGN(win)
+	copied.Tags = Tags{"White": "Vishy Anand", "Black": "M. Carlsen", "Date": "2013.??.??"}
+	s.True(IsDuplicate(original, copied))
+
+	copied.Tags["Event"] = "Norway Chess"
+	s.False(IsDuplicate(original, copied))
+
+	s.False(IsDuplicate(original, ParsePGN(draw)))
+}
+
+func (s *DedupeTestSuite) TestFindDuplicates() {
+	annotated := ParsePGN(win)
+	annotated.Tags = Tags{"White": "Anand", "Black": "Carlsen", "Event": "?", "Result": "0-1"}
+	annotated.Moves[0].WhiteComment = "A Nimzo-Indian"
+
+	other := ParsePGN(win)
+	other.Tags = Tags{"White": "Kramnik", "Black": "Carlsen"}
+
+	pgns := []PGN{ParsePGN(draw), ParsePGN(win), other, annotated, ParsePGN(draw)}
+
+	s.Equal([][]int{{0, 4}, {1, 3}}, FindDuplicates(pgns))
+
+	deduped, removed := Dedupe(pgns)
+	s.Equal(2, removed)
+	s.Len(deduped, 3)
+
+	merged := deduped[1]
+	s.Equal("A Nimzo-Indian", merged.Moves[0].WhiteComment)
+	s.Equal("WCh 2013", merged.Tags["Event"])
+	s.Equal("Anand,V", merged.Tags["White"])
+	s.Equal("2870", merged.Tags["BlackElo"])
+	s.Equal(Outcome(BlackWin), merged.Outcome)
+
+	s.Equal("", pgns[1].Moves[0].WhiteComment, "copies are merged without being changed")
+	s.Equal("Kramnik", deduped[2].Tags["White"])
+}
+
+func (s *DedupeTestSuite) TestMergeDuplicates() {
+	first := ParsePGN("[Date \"2019.??.??\"]\n[Result \"*\"]\n\n1. e4 {Best by test} e5 *")
+	second := ParsePGN("[Date \"2019.05.21\"]\n[Result \"1-0\"]\n\n1. e4 e5 {Symmetry} 1-0")
+
+	merged := MergeDuplicates([]PGN{first, second})
+	s.Equal("2019.05.21", merged.Tags["Date"])
+	s.Equal("1-0", merged.Tags["Result"])
+	s.Equal(Outcome(WhiteWin), merged.Outcome)
+	s.Equal("1.e4 {Best by test} 1...e5 {Symmetry}", merged.Movetext.String())
+}
@@ -0,0 +1,118 @@
+package pawn
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// An opening line from the Encyclopaedia of Chess Openings
+type Opening struct {
+	ECO       string // Code from A00 to E99
+	Name      string
+	Variation string
+	Moves     string // Movetext from the initial position, e.g. "1.e4 c5 2.Nf3"
+}
+
+func (o Opening) String() string {
+	if o.Variation == "" {
+		return fmt.Sprintf("%s %s", o.ECO, o.Name)
+	}
+
+	return fmt.Sprintf("%s %s: %s", o.ECO, o.Name, o.Variation)
+}
+
+// The moves of the opening in Standard Algebraic Notation
+func (o Opening) Turns() []AlgebraicNotation {
+	moveNumberPattern := regexp.MustCompile(`^[0-9]+\.+`)
+	turns := []AlgebraicNotation{}
+
+	for _, token := range strings.Fields(o.Moves) {
+		if an := moveNumberPattern.ReplaceAllString(token, ""); an != "" {
+			turns = append(turns, AlgebraicNotation(an))
+		}
+	}
+
+	return turns
+}
+
+// Sets the ECO, Opening and Variation tags the opening describes unless they
+// already have a value. Opening and Variation are left alone when an existing
+// ECO tag names a different code so the tags don't contradict each other.
+func (o Opening) FillTags(tags Tags) {
+	missing := func(tag string) bool {
+		value := tags[tag]
+		return value == "" || value == "?"
+	}
+
+	if missing("ECO") {
+		tags["ECO"] = o.ECO
+	} else if tags["ECO"] != o.ECO {
+		return
+	}
+
+	if missing("Opening") {
+		tags["Opening"] = o.Name
+	}
+
+	if missing("Variation") && o.Variation != "" {
+		tags["Variation"] = o.Variation
+	}
+}
+
+// Openings by the hash of the position their line reaches
+type openingIndex map[uint64]Opening
+
+var (
+	ecoIndex     openingIndex
+	ecoIndexOnce sync.Once
+)
+
+func indexOpenings(openings []Opening) openingIndex {
+	index := openingIndex{}
+
+	for _, opening := range openings {
+		board := NewBoard()
+
+		for _, an := range opening.Turns() {
+			if _, err := board.MoveFromAlgebraic(an); err != nil {
+				panic(fmt.Sprintf("pawn: invalid ECO line %s %s: %s", opening.ECO, opening.Moves, err))
+			}
+		}
+
+		if _, exists := index[board.Hash()]; !exists {
+			index[board.Hash()] = opening
+		}
+	}
+
+	return index
+}
+
+// Finds the opening whose line is the deepest position reached in the game.
+// Lines are matched by position rather than move order so games that
+// transpose into an opening are classified as it.
+func ClassifyOpening(game Game) (Opening, bool) {
+	ecoIndexOnce.Do(func() {
+		ecoIndex = indexOpenings(ecoTable)
+	})
+
+	return ecoIndex.classify(game.Moves)
+}
+
+func (index openingIndex) classify(moves []Move) (Opening, bool) {
+	var deepest Opening
+	found := false
+
+	board := NewBoard()
+	for _, move := range moves {
+		board.MakeMove(move)
+
+		if opening, matches := index[board.Hash()]; matches {
+			deepest = opening
+			found = true
+		}
+	}
+
+	return deepest, found
+}
@@ -0,0 +1,238 @@
+package pawn
+
+// Encyclopaedia of Chess Openings classification of common lines. Entries
+// are matched by the position their moves reach so their order only matters
+// when two lines reach the same position, in which case the first wins.
+var ecoTable = []Opening{
+	// A: Flank openings
+	{"A00", "Polish Opening", "", "1.b4"},
+	{"A00", "Grob Opening", "", "1.g4"},
+	{"A00", "Hungarian Opening", "", "1.g3"},
+	{"A00", "Van Geet Opening", "", "1.Nc3"},
+	{"A00", "Van't Kruijs Opening", "", "1.e3"},
+	{"A00", "Mieses Opening", "", "1.d3"},
+	{"A00", "Saragossa Opening", "", "1.c3"},
+	{"A00", "Anderssen's Opening", "", "1.a3"},
+	{"A00", "Amar Opening", "", "1.Nh3"},
+	{"A01", "Nimzo-Larsen Attack", "", "1.b3"},
+	{"A02", "Bird Opening", "", "1.f4"},
+	{"A02", "Bird Opening", "From's Gambit", "1.f4 e5"},
+	{"A03", "Bird Opening", "Dutch Variation", "1.f4 d5"},
+	{"A04", "Zukertort Opening", "", "1.Nf3"},
+	{"A04", "Zukertort Opening", "Sicilian Invitation", "1.Nf3 c5"},
+	{"A05", "Zukertort Opening", "", "1.Nf3 Nf6"},
+	{"A05", "King's Indian Attack", "", "1.Nf3 Nf6 2.g3"},
+	{"A06", "Zukertort Opening", "", "1.Nf3 d5"},
+	{"A07", "King's Indian Attack", "", "1.Nf3 d5 2.g3"},
+	{"A09", "Reti Opening", "", "1.Nf3 d5 2.c4"},
+	{"A10", "English Opening", "", "1.c4"},
+	{"A13", "English Opening", "Agincourt Defense", "1.c4 e6"},
+	{"A15", "English Opening", "Anglo-Indian Defense", "1.c4 Nf6"},
+	{"A16", "English Opening", "Anglo-Indian Defense", "1.c4 Nf6 2.Nc3"},
+	{"A17", "English Opening", "Anglo-Indian Defense, Hedgehog System", "1.c4 Nf6 2.Nc3 e6"},
+	{"A20", "English Opening", "King's English Variation", "1.c4 e5"},
+	{"A21", "English Opening", "King's English Variation", "1.c4 e5 2.Nc3"},
+	{"A22", "English Opening", "King's English Variation, Two Knights", "1.c4 e5 2.Nc3 Nf6"},
+	{"A25", "English Opening", "King's English Variation, Reversed Closed Sicilian", "1.c4 e5 2.Nc3 Nc6"},
+	{"A29", "English Opening", "King's English Variation, Four Knights, Fianchetto", "1.c4 e5 2.Nc3 Nc6 3.Nf3 Nf6 4.g3"},
+	{"A30", "English Opening", "Symmetrical Variation", "1.c4 c5"},
+	{"A34", "English Opening", "Symmetrical Variation", "1.c4 c5 2.Nc3"},
+	{"A36", "English Opening", "Symmetrical Variation, Fianchetto", "1.c4 c5 2.Nc3 Nc6 3.g3"},
+	{"A40", "Queen's Pawn Game", "", "1.d4"},
+	{"A40", "Englund Gambit", "", "1.d4 e5"},
+	{"A40", "Horwitz Defense", "", "1.d4 e6"},
+	{"A41", "Queen's Pawn Game", "Wade Defense", "1.d4 d6"},
+	{"A43", "Benoni Defense", "Old Benoni", "1.d4 c5"},
+	{"A45", "Indian Defense", "", "1.d4 Nf6"},
+	{"A45", "Trompowsky Attack", "", "1.d4 Nf6 2.Bg5"},
+	{"A46", "Indian Defense", "Knights Variation", "1.d4 Nf6 2.Nf3"},
+	{"A46", "Indian Defense", "London System", "1.d4 Nf6 2.Nf3 e6 3.Bf4"},
+	{"A46", "Torre Attack", "", "1.d4 Nf6 2.Nf3 e6 3.Bg5"},
+	{"A48", "Indian Defense", "East Indian Defense", "1.d4 Nf6 2.Nf3 g6"},
+	{"A48", "Indian Defense", "London System", "1.d4 Nf6 2.Nf3 g6 3.Bf4"},
+	{"A50", "Indian Defense", "Normal Variation", "1.d4 Nf6 2.c4"},
+	{"A51", "Budapest Defense", "", "1.d4 Nf6 2.c4 e5"},
+	{"A52", "Budapest Defense", "Adler Variation", "1.d4 Nf6 2.c4 e5 3.dxe5 Ng4"},
+	{"A53", "Old Indian Defense", "", "1.d4 Nf6 2.c4 d6"},
+	{"A56", "Benoni Defense", "", "1.d4 Nf6 2.c4 c5"},
+	{"A57", "Benko Gambit", "", "1.d4 Nf6 2.c4 c5 3.d5 b5"},
+	{"A60", "Benoni Defense", "Modern Variation", "1.d4 Nf6 2.c4 c5 3.d5 e6"},
+	{"A80", "Dutch Defense", "", "1.d4 f5"},
+	{"A81", "Dutch Defense", "Fianchetto Attack", "1.d4 f5 2.g3"},
+	{"A83", "Dutch Defense", "Staunton Gambit", "1.d4 f5 2.e4"},
+	{"A84", "Dutch Defense", "", "1.d4 f5 2.c4"},
+	{"A86", "Dutch Defense", "Leningrad Variation", "1.d4 f5 2.c4 Nf6 3.g3 g6"},
+
+	// B: Semi-open games other than the French
+	{"B00", "King's Pawn Game", "", "1.e4"},
+	{"B00", "Nimzowitsch Defense", "", "1.e4 Nc6"},
+	{"B00", "Owen Defense", "", "1.e4 b6"},
+	{"B00", "St. George Defense", "", "1.e4 a6"},
+	{"B01", "Scandinavian Defense", "", "1.e4 d5"},
+	{"B01", "Scandinavian Defense", "Mieses-Kotroc Variation", "1.e4 d5 2.exd5 Qxd5"},
+	{"B01", "Scandinavian Defense", "Modern Variation", "1.e4 d5 2.exd5 Nf6"},
+	{"B02", "Alekhine Defense", "", "1.e4 Nf6"},
+	{"B03", "Alekhine Defense", "", "1.e4 Nf6 2.e5 Nd5 3.d4"},
+	{"B04", "Alekhine Defense", "Modern Variation", "1.e4 Nf6 2.e5 Nd5 3.d4 d6 4.Nf3"},
+	{"B06", "Modern Defense", "", "1.e4 g6"},
+	{"B07", "Pirc Defense", "", "1.e4 d6 2.d4 Nf6"},
+	{"B08", "Pirc Defense", "Classical Variation", "1.e4 d6 2.d4 Nf6 3.Nc3 g6 4.Nf3"},
+	{"B09", "Pirc Defense", "Austrian Attack", "1.e4 d6 2.d4 Nf6 3.Nc3 g6 4.f4"},
+	{"B10", "Caro-Kann Defense", "", "1.e4 c6"},
+	{"B12", "Caro-Kann Defense", "", "1.e4 c6 2.d4 d5"},
+	{"B12", "Caro-Kann Defense", "Advance Variation", "1.e4 c6 2.d4 d5 3.e5"},
+	{"B13", "Caro-Kann Defense", "Exchange Variation", "1.e4 c6 2.d4 d5 3.exd5 cxd5"},
+	{"B13", "Caro-Kann Defense", "Panov Attack", "1.e4 c6 2.d4 d5 3.exd5 cxd5 4.c4"},
+	{"B15", "Caro-Kann Defense", "", "1.e4 c6 2.d4 d5 3.Nc3"},
+	{"B17", "Caro-Kann Defense", "Karpov Variation", "1.e4 c6 2.d4 d5 3.Nc3 dxe4 4.Nxe4 Nd7"},
+	{"B18", "Caro-Kann Defense", "Classical Variation", "1.e4 c6 2.d4 d5 3.Nc3 dxe4 4.Nxe4 Bf5"},
+	{"B20", "Sicilian Defense", "", "1.e4 c5"},
+	{"B21", "Sicilian Defense", "Grand Prix Attack", "1.e4 c5 2.f4"},
+	{"B21", "Sicilian Defense", "Smith-Morra Gambit", "1.e4 c5 2.d4 cxd4 3.c3"},
+	{"B22", "Sicilian Defense", "Alapin Variation", "1.e4 c5 2.c3"},
+	{"B23", "Sicilian Defense", "Closed", "1.e4 c5 2.Nc3"},
+	{"B27", "Sicilian Defense", "", "1.e4 c5 2.Nf3"},
+	{"B28", "Sicilian Defense", "O'Kelly Variation", "1.e4 c5 2.Nf3 a6"},
+	{"B29", "Sicilian Defense", "Nimzowitsch Variation", "1.e4 c5 2.Nf3 Nf6"},
+	{"B30", "Sicilian Defense", "Old Sicilian", "1.e4 c5 2.Nf3 Nc6"},
+	{"B30", "Sicilian Defense", "Rossolimo Variation", "1.e4 c5 2.Nf3 Nc6 3.Bb5"},
+	{"B32", "Sicilian Defense", "Open", "1.e4 c5 2.Nf3 Nc6 3.d4 cxd4 4.Nxd4"},
+	{"B32", "Sicilian Defense", "Lowenthal Variation", "1.e4 c5 2.Nf3 Nc6 3.d4 cxd4 4.Nxd4 e5"},
+	{"B33", "Sicilian Defense", "Open", "1.e4 c5 2.Nf3 Nc6 3.d4 cxd4 4.Nxd4 Nf6"},
+	{"B33", "Sicilian Defense", "Sveshnikov Variation", "1.e4 c5 2.Nf3 Nc6 3.d4 cxd4 4.Nxd4 Nf6 5.Nc3 e5"},
+	{"B34", "Sicilian Defense", "Accelerated Dragon", "1.e4 c5 2.Nf3 Nc6 3.d4 cxd4 4.Nxd4 g6"},
+	{"B40", "Sicilian Defense", "French Variation", "1.e4 c5 2.Nf3 e6"},
+	{"B41", "Sicilian Defense", "Kan Variation", "1.e4 c5 2.Nf3 e6 3.d4 cxd4 4.Nxd4 a6"},
+	{"B44", "Sicilian Defense", "Taimanov Variation", "1.e4 c5 2.Nf3 e6 3.d4 cxd4 4.Nxd4 Nc6"},
+	{"B50", "Sicilian Defense", "Modern Variations", "1.e4 c5 2.Nf3 d6"},
+	{"B51", "Sicilian Defense", "Moscow Variation", "1.e4 c5 2.Nf3 d6 3.Bb5+"},
+	{"B52", "Sicilian Defense", "Moscow Variation", "1.e4 c5 2.Nf3 d6 3.Bb5+ Bd7"},
+	{"B53", "Sicilian Defense", "Chekhover Variation", "1.e4 c5 2.Nf3 d6 3.d4 cxd4 4.Qxd4"},
+	{"B54", "Sicilian Defense", "Modern Variations", "1.e4 c5 2.Nf3 d6 3.d4 cxd4 4.Nxd4"},
+	{"B56", "Sicilian Defense", "Open", "1.e4 c5 2.Nf3 d6 3.d4 cxd4 4.Nxd4 Nf6 5.Nc3"},
+	{"B56", "Sicilian Defense", "Classical Variation", "1.e4 c5 2.Nf3 d6 3.d4 cxd4 4.Nxd4 Nf6 5.Nc3 Nc6"},
+	{"B57", "Sicilian Defense", "Classical Variation, Sozin Attack", "1.e4 c5 2.Nf3 d6 3.d4 cxd4 4.Nxd4 Nf6 5.Nc3 Nc6 6.Bc4"},
+	{"B58", "Sicilian Defense", "Classical Variation", "1.e4 c5 2.Nf3 d6 3.d4 cxd4 4.Nxd4 Nf6 5.Nc3 Nc6 6.Be2"},
+	{"B60", "Sicilian Defense", "Richter-Rauzer Variation", "1.e4 c5 2.Nf3 d6 3.d4 cxd4 4.Nxd4 Nf6 5.Nc3 Nc6 6.Bg5"},
+	{"B70", "Sicilian Defense", "Dragon Variation", "1.e4 c5 2.Nf3 d6 3.d4 cxd4 4.Nxd4 Nf6 5.Nc3 g6"},
+	{"B72", "Sicilian Defense", "Dragon Variation, Classical", "1.e4 c5 2.Nf3 d6 3.d4 cxd4 4.Nxd4 Nf6 5.Nc3 g6 6.Be3"},
+	{"B75", "Sicilian Defense", "Dragon Variation, Yugoslav Attack", "1.e4 c5 2.Nf3 d6 3.d4 cxd4 4.Nxd4 Nf6 5.Nc3 g6 6.Be3 Bg7 7.f3"},
+	{"B80", "Sicilian Defense", "Scheveningen Variation", "1.e4 c5 2.Nf3 d6 3.d4 cxd4 4.Nxd4 Nf6 5.Nc3 e6"},
+	{"B90", "Sicilian Defense", "Najdorf Variation", "1.e4 c5 2.Nf3 d6 3.d4 cxd4 4.Nxd4 Nf6 5.Nc3 a6"},
+	{"B90", "Sicilian Defense", "Najdorf Variation, English Attack", "1.e4 c5 2.Nf3 d6 3.d4 cxd4 4.Nxd4 Nf6 5.Nc3 a6 6.Be3"},
+	{"B92", "Sicilian Defense", "Najdorf Variation, Opocensky Variation", "1.e4 c5 2.Nf3 d6 3.d4 cxd4 4.Nxd4 Nf6 5.Nc3 a6 6.Be2"},
+	{"B94", "Sicilian Defense", "Najdorf Variation", "1.e4 c5 2.Nf3 d6 3.d4 cxd4 4.Nxd4 Nf6 5.Nc3 a6 6.Bg5"},
+	{"B96", "Sicilian Defense", "Najdorf Variation", "1.e4 c5 2.Nf3 d6 3.d4 cxd4 4.Nxd4 Nf6 5.Nc3 a6 6.Bg5 e6 7.f4"},
+	{"B97", "Sicilian Defense", "Najdorf Variation, Poisoned Pawn", "1.e4 c5 2.Nf3 d6 3.d4 cxd4 4.Nxd4 Nf6 5.Nc3 a6 6.Bg5 e6 7.f4 Qb6"},
+
+	// C: Open games and the French
+	{"C00", "French Defense", "", "1.e4 e6"},
+	{"C01", "French Defense", "Exchange Variation", "1.e4 e6 2.d4 d5 3.exd5"},
+	{"C02", "French Defense", "Advance Variation", "1.e4 e6 2.d4 d5 3.e5"},
+	{"C03", "French Defense", "Tarrasch Variation", "1.e4 e6 2.d4 d5 3.Nd2"},
+	{"C10", "French Defense", "Paulsen Variation", "1.e4 e6 2.d4 d5 3.Nc3"},
+	{"C10", "French Defense", "Rubinstein Variation", "1.e4 e6 2.d4 d5 3.Nc3 dxe4"},
+	{"C11", "French Defense", "Classical Variation", "1.e4 e6 2.d4 d5 3.Nc3 Nf6"},
+	{"C15", "French Defense", "Winawer Variation", "1.e4 e6 2.d4 d5 3.Nc3 Bb4"},
+	{"C18", "French Defense", "Winawer Variation", "1.e4 e6 2.d4 d5 3.Nc3 Bb4 4.e5 c5 5.a3 Bxc3+ 6.bxc3"},
+	{"C20", "King's Pawn Game", "", "1.e4 e5"},
+	{"C21", "Center Game", "", "1.e4 e5 2.d4 exd4"},
+	{"C21", "Danish Gambit", "", "1.e4 e5 2.d4 exd4 3.c3"},
+	{"C23", "Bishop's Opening", "", "1.e4 e5 2.Bc4"},
+	{"C25", "Vienna Game", "", "1.e4 e5 2.Nc3"},
+	{"C30", "King's Gambit", "", "1.e4 e5 2.f4"},
+	{"C31", "King's Gambit Declined", "Falkbeer Countergambit", "1.e4 e5 2.f4 d5"},
+	{"C33", "King's Gambit Accepted", "", "1.e4 e5 2.f4 exf4"},
+	{"C40", "King's Knight Opening", "", "1.e4 e5 2.Nf3"},
+	{"C40", "Latvian Gambit", "", "1.e4 e5 2.Nf3 f5"},
+	{"C41", "Philidor Defense", "", "1.e4 e5 2.Nf3 d6"},
+	{"C42", "Petrov's Defense", "", "1.e4 e5 2.Nf3 Nf6"},
+	{"C44", "King's Pawn Game", "", "1.e4 e5 2.Nf3 Nc6"},
+	{"C44", "Ponziani Opening", "", "1.e4 e5 2.Nf3 Nc6 3.c3"},
+	{"C44", "Scotch Game", "", "1.e4 e5 2.Nf3 Nc6 3.d4"},
+	{"C45", "Scotch Game", "", "1.e4 e5 2.Nf3 Nc6 3.d4 exd4 4.Nxd4"},
+	{"C46", "Three Knights Opening", "", "1.e4 e5 2.Nf3 Nc6 3.Nc3"},
+	{"C47", "Four Knights Game", "", "1.e4 e5 2.Nf3 Nc6 3.Nc3 Nf6"},
+	{"C47", "Four Knights Game", "Scotch Variation", "1.e4 e5 2.Nf3 Nc6 3.Nc3 Nf6 4.d4"},
+	{"C48", "Four Knights Game", "Spanish Variation", "1.e4 e5 2.Nf3 Nc6 3.Nc3 Nf6 4.Bb5"},
+	{"C49", "Four Knights Game", "Double Spanish", "1.e4 e5 2.Nf3 Nc6 3.Nc3 Nf6 4.Bb5 Bb4"},
+	{"C50", "Italian Game", "", "1.e4 e5 2.Nf3 Nc6 3.Bc4"},
+	{"C50", "Italian Game", "Giuoco Piano", "1.e4 e5 2.Nf3 Nc6 3.Bc4 Bc5"},
+	{"C50", "Italian Game", "Giuoco Pianissimo", "1.e4 e5 2.Nf3 Nc6 3.Bc4 Bc5 4.d3"},
+	{"C51", "Italian Game", "Evans Gambit", "1.e4 e5 2.Nf3 Nc6 3.Bc4 Bc5 4.b4"},
+	{"C53", "Italian Game", "Classical Variation", "1.e4 e5 2.Nf3 Nc6 3.Bc4 Bc5 4.c3"},
+	{"C54", "Italian Game", "Classical Variation", "1.e4 e5 2.Nf3 Nc6 3.Bc4 Bc5 4.c3 Nf6 5.d4"},
+	{"C55", "Italian Game", "Two Knights Defense", "1.e4 e5 2.Nf3 Nc6 3.Bc4 Nf6"},
+	{"C57", "Italian Game", "Two Knights Defense, Knight Attack", "1.e4 e5 2.Nf3 Nc6 3.Bc4 Nf6 4.Ng5"},
+	{"C60", "Ruy Lopez", "", "1.e4 e5 2.Nf3 Nc6 3.Bb5"},
+	{"C62", "Ruy Lopez", "Steinitz Defense", "1.e4 e5 2.Nf3 Nc6 3.Bb5 d6"},
+	{"C63", "Ruy Lopez", "Schliemann Defense", "1.e4 e5 2.Nf3 Nc6 3.Bb5 f5"},
+	{"C64", "Ruy Lopez", "Classical Variation", "1.e4 e5 2.Nf3 Nc6 3.Bb5 Bc5"},
+	{"C65", "Ruy Lopez", "Berlin Defense", "1.e4 e5 2.Nf3 Nc6 3.Bb5 Nf6"},
+	{"C67", "Ruy Lopez", "Berlin Defense, Rio de Janeiro Variation", "1.e4 e5 2.Nf3 Nc6 3.Bb5 Nf6 4.O-O Nxe4"},
+	{"C67", "Ruy Lopez", "Berlin Defense, Berlin Wall", "1.e4 e5 2.Nf3 Nc6 3.Bb5 Nf6 4.O-O Nxe4 5.d4 Nd6 6.Bxc6 dxc6 7.dxe5 Nf5 8.Qxd8+ Kxd8"},
+	{"C68", "Ruy Lopez", "Exchange Variation", "1.e4 e5 2.Nf3 Nc6 3.Bb5 a6 4.Bxc6"},
+	{"C70", "Ruy Lopez", "Morphy Defense", "1.e4 e5 2.Nf3 Nc6 3.Bb5 a6 4.Ba4"},
+	{"C77", "Ruy Lopez", "Morphy Defense", "1.e4 e5 2.Nf3 Nc6 3.Bb5 a6 4.Ba4 Nf6"},
+	{"C78", "Ruy Lopez", "Morphy Defense", "1.e4 e5 2.Nf3 Nc6 3.Bb5 a6 4.Ba4 Nf6 5.O-O"},
+	{"C80", "Ruy Lopez", "Open Variation", "1.e4 e5 2.Nf3 Nc6 3.Bb5 a6 4.Ba4 Nf6 5.O-O Nxe4"},
+	{"C84", "Ruy Lopez", "Closed Variations", "1.e4 e5 2.Nf3 Nc6 3.Bb5 a6 4.Ba4 Nf6 5.O-O Be7"},
+	{"C88", "Ruy Lopez", "Closed", "1.e4 e5 2.Nf3 Nc6 3.Bb5 a6 4.Ba4 Nf6 5.O-O Be7 6.Re1 b5 7.Bb3"},
+	{"C89", "Ruy Lopez", "Marshall Attack", "1.e4 e5 2.Nf3 Nc6 3.Bb5 a6 4.Ba4 Nf6 5.O-O Be7 6.Re1 b5 7.Bb3 O-O 8.c3 d5"},
+	{"C92", "Ruy Lopez", "Closed", "1.e4 e5 2.Nf3 Nc6 3.Bb5 a6 4.Ba4 Nf6 5.O-O Be7 6.Re1 b5 7.Bb3 d6 8.c3 O-O 9.h3"},
+
+	// D: Closed and semi-closed games
+	{"D00", "Queen's Pawn Game", "", "1.d4 d5"},
+	{"D00", "Queen's Pawn Game", "Accelerated London System", "1.d4 d5 2.Bf4"},
+	{"D00", "Blackmar-Diemer Gambit", "", "1.d4 d5 2.e4"},
+	{"D02", "Queen's Pawn Game", "Zukertort Variation", "1.d4 d5 2.Nf3"},
+	{"D02", "Queen's Pawn Game", "London System", "1.d4 d5 2.Nf3 Nf6 3.Bf4"},
+	{"D04", "Queen's Pawn Game", "Colle System", "1.d4 d5 2.Nf3 Nf6 3.e3"},
+	{"D06", "Queen's Gambit", "", "1.d4 d5 2.c4"},
+	{"D07", "Queen's Gambit Declined", "Chigorin Defense", "1.d4 d5 2.c4 Nc6"},
+	{"D08", "Queen's Gambit Declined", "Albin Countergambit", "1.d4 d5 2.c4 e5"},
+	{"D10", "Slav Defense", "", "1.d4 d5 2.c4 c6"},
+	{"D11", "Slav Defense", "Modern Line", "1.d4 d5 2.c4 c6 3.Nf3"},
+	{"D15", "Slav Defense", "Three Knights Variation", "1.d4 d5 2.c4 c6 3.Nf3 Nf6 4.Nc3"},
+	{"D16", "Slav Defense", "Alapin Variation", "1.d4 d5 2.c4 c6 3.Nf3 Nf6 4.Nc3 dxc4 5.a4"},
+	{"D20", "Queen's Gambit Accepted", "", "1.d4 d5 2.c4 dxc4"},
+	{"D30", "Queen's Gambit Declined", "", "1.d4 d5 2.c4 e6"},
+	{"D31", "Queen's Gambit Declined", "", "1.d4 d5 2.c4 e6 3.Nc3"},
+	{"D32", "Tarrasch Defense", "", "1.d4 d5 2.c4 e6 3.Nc3 c5"},
+	{"D35", "Queen's Gambit Declined", "Normal Defense", "1.d4 d5 2.c4 e6 3.Nc3 Nf6"},
+	{"D35", "Queen's Gambit Declined", "Exchange Variation", "1.d4 d5 2.c4 e6 3.Nc3 Nf6 4.cxd5"},
+	{"D37", "Queen's Gambit Declined", "Three Knights Variation", "1.d4 d5 2.c4 e6 3.Nc3 Nf6 4.Nf3"},
+	{"D43", "Semi-Slav Defense", "", "1.d4 d5 2.c4 e6 3.Nc3 Nf6 4.Nf3 c6"},
+	{"D45", "Semi-Slav Defense", "Normal Variation", "1.d4 d5 2.c4 e6 3.Nc3 Nf6 4.Nf3 c6 5.e3"},
+	{"D47", "Semi-Slav Defense", "Meran Variation", "1.d4 d5 2.c4 e6 3.Nc3 Nf6 4.Nf3 c6 5.e3 Nbd7 6.Bd3 dxc4 7.Bxc4 b5"},
+	{"D50", "Queen's Gambit Declined", "Modern Variation", "1.d4 d5 2.c4 e6 3.Nc3 Nf6 4.Bg5"},
+	{"D53", "Queen's Gambit Declined", "Modern Variation", "1.d4 d5 2.c4 e6 3.Nc3 Nf6 4.Bg5 Be7"},
+	{"D70", "Neo-Grunfeld Defense", "", "1.d4 Nf6 2.c4 g6 3.f3 d5"},
+	{"D80", "Grunfeld Defense", "", "1.d4 Nf6 2.c4 g6 3.Nc3 d5"},
+	{"D85", "Grunfeld Defense", "Exchange Variation", "1.d4 Nf6 2.c4 g6 3.Nc3 d5 4.cxd5 Nxd5"},
+	{"D90", "Grunfeld Defense", "Three Knights Variation", "1.d4 Nf6 2.c4 g6 3.Nc3 d5 4.Nf3"},
+
+	// E: Indian defenses
+	{"E00", "Indian Defense", "East Indian Defense", "1.d4 Nf6 2.c4 e6"},
+	{"E00", "Catalan Opening", "", "1.d4 Nf6 2.c4 e6 3.g3"},
+	{"E10", "Indian Defense", "Anti-Nimzo-Indian", "1.d4 Nf6 2.c4 e6 3.Nf3"},
+	{"E11", "Bogo-Indian Defense", "", "1.d4 Nf6 2.c4 e6 3.Nf3 Bb4+"},
+	{"E12", "Queen's Indian Defense", "", "1.d4 Nf6 2.c4 e6 3.Nf3 b6"},
+	{"E15", "Queen's Indian Defense", "Fianchetto Variation", "1.d4 Nf6 2.c4 e6 3.Nf3 b6 4.g3"},
+	{"E20", "Nimzo-Indian Defense", "", "1.d4 Nf6 2.c4 e6 3.Nc3 Bb4"},
+	{"E20", "Nimzo-Indian Defense", "Kmoch Variation", "1.d4 Nf6 2.c4 e6 3.Nc3 Bb4 4.f3"},
+	{"E21", "Nimzo-Indian Defense", "Three Knights Variation", "1.d4 Nf6 2.c4 e6 3.Nc3 Bb4 4.Nf3"},
+	{"E24", "Nimzo-Indian Defense", "Samisch Variation", "1.d4 Nf6 2.c4 e6 3.Nc3 Bb4 4.a3 Bxc3+ 5.bxc3"},
+	{"E32", "Nimzo-Indian Defense", "Classical Variation", "1.d4 Nf6 2.c4 e6 3.Nc3 Bb4 4.Qc2"},
+	{"E40", "Nimzo-Indian Defense", "Rubinstein Variation", "1.d4 Nf6 2.c4 e6 3.Nc3 Bb4 4.e3"},
+	{"E60", "King's Indian Defense", "", "1.d4 Nf6 2.c4 g6"},
+	{"E61", "King's Indian Defense", "", "1.d4 Nf6 2.c4 g6 3.Nc3"},
+	{"E62", "King's Indian Defense", "Fianchetto Variation", "1.d4 Nf6 2.c4 g6 3.Nc3 Bg7 4.Nf3 d6 5.g3"},
+	{"E70", "King's Indian Defense", "Normal Variation", "1.d4 Nf6 2.c4 g6 3.Nc3 Bg7 4.e4"},
+	{"E70", "King's Indian Defense", "Normal Variation", "1.d4 Nf6 2.c4 g6 3.Nc3 Bg7 4.e4 d6"},
+	{"E76", "King's Indian Defense", "Four Pawns Attack", "1.d4 Nf6 2.c4 g6 3.Nc3 Bg7 4.e4 d6 5.f4"},
+	{"E80", "King's Indian Defense", "Samisch Variation", "1.d4 Nf6 2.c4 g6 3.Nc3 Bg7 4.e4 d6 5.f3"},
+	{"E90", "King's Indian Defense", "Normal Variation", "1.d4 Nf6 2.c4 g6 3.Nc3 Bg7 4.e4 d6 5.Nf3"},
+	{"E92", "King's Indian Defense", "Classical Variation", "1.d4 Nf6 2.c4 g6 3.Nc3 Bg7 4.e4 d6 5.Nf3 O-O 6.Be2 e5"},
+	{"E97", "King's Indian Defense", "Mar del Plata Variation", "1.d4 Nf6 2.c4 g6 3.Nc3 Bg7 4.e4 d6 5.Nf3 O-O 6.Be2 e5 7.O-O Nc6"},
+}
@@ -0,0 +1,85 @@
+package pawn
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestECOTable(t *testing.T) {
+	index := indexOpenings(ecoTable)
+
+	assert.True(t, len(index) > 200)
+
+	for _, opening := range ecoTable {
+		assert.Regexp(t, "^[A-E][0-9]{2}$", opening.ECO)
+		assert.NotEmpty(t, opening.Name)
+	}
+}
+
+func TestOpeningTurns(t *testing.T) {
+	opening := Opening{"C67", "Ruy Lopez", "Berlin Defense", "1.e4 e5 2.Nf3 Nc6 3.Bb5 Nf6 4.O-O"}
+
+	assert.Equal(t, []AlgebraicNotation{"e4", "e5", "Nf3", "Nc6", "Bb5", "Nf6", "O-O"}, opening.Turns())
+	assert.Equal(t, "C67 Ruy Lopez: Berlin Defense", opening.String())
+	assert.Equal(t, "A10 English Opening", Opening{ECO: "A10", Name: "English Opening"}.String())
+}
+
+func classifyPGN(t *testing.T, movetext string) (Opening, bool) {
+	game, err := ParsePGN("[Event \"?\"]\n\n" + movetext + " *").Game()
+	assert.Nil(t, err)
+
+	return ClassifyOpening(game)
+}
+
+func TestClassifyOpening(t *testing.T) {
+	game, err := ParsePGN(win).Game()
+	assert.Nil(t, err)
+
+	opening, found := ClassifyOpening(game)
+	assert.True(t, found)
+	assert.Equal(t, Opening{"E20", "Nimzo-Indian Defense", "Kmoch Variation", "1.d4 Nf6 2.c4 e6 3.Nc3 Bb4 4.f3"}, opening)
+
+	opening, _ = classifyPGN(t, "1.e4 c5 2.Nf3 d6 3.d4 cxd4 4.Nxd4 Nf6 5.Nc3 a6 6.h3 e5")
+	assert.Equal(t, "B90", opening.ECO)
+
+	_, found = classifyPGN(t, "")
+	assert.False(t, found)
+}
+
+func TestClassifyTransposition(t *testing.T) {
+	opening, found := classifyPGN(t, "1.Nf3 d5 2.d4 Nf6 3.c4 e6 4.Nc3 Be7")
+	assert.True(t, found)
+	assert.Equal(t, "D37", opening.ECO)
+	assert.Equal(t, "Three Knights Variation", opening.Variation)
+
+	opening, _ = classifyPGN(t, "1.c4 e6 2.Nc3 d5 3.d4 Nf6 4.Bg5")
+	assert.Equal(t, "D50", opening.ECO)
+}
+
+func TestFillTags(t *testing.T) {
+	tags := Tags{"ECO": "", "Opening": "Sicilian"}
+	Opening{"B90", "Sicilian Defense", "Najdorf Variation", ""}.FillTags(tags)
+
+	assert.Equal(t, Tags{"ECO": "B90", "Opening": "Sicilian", "Variation": "Najdorf Variation"}, tags)
+
+	tags = Tags{}
+	Opening{ECO: "B00", Name: "King's Pawn Game"}.FillTags(tags)
+	assert.Equal(t, Tags{"ECO": "B00", "Opening": "King's Pawn Game"}, tags)
+
+	tags = Tags{"ECO": "D12"}
+	Opening{"D04", "Queen's Pawn Game", "Colle System", ""}.FillTags(tags)
+	assert.Equal(t, Tags{"ECO": "D12"}, tags)
+}
+
+func TestPGNGame(t *testing.T) {
+	game, err := ParsePGN(checkMate).Game()
+	assert.Nil(t, err)
+	assert.Equal(t, 20, len(game.Moves))
+	assert.True(t, game.IsCheckmate())
+
+	game, err = ParsePGN(movetextWithRAV).Game()
+	assert.True(t, strings.HasSuffix(err.Error(), ": Bc5"))
+	assert.Equal(t, 5, len(game.Moves))
+}
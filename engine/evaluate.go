@@ -0,0 +1,382 @@
+package engine
+
+import "github.com/marcel/pawn"
+
+// A weight or score for the middlegame and for the endgame, blended by how
+// much material is left
+type Phased struct {
+	Middlegame int
+	Endgame    int
+}
+
+func (p *Phased) add(other Phased, times int) {
+	p.Middlegame += other.Middlegame * times
+	p.Endgame += other.Endgame * times
+}
+
+// Blends the two by the game phase, from 0 for bare kings and pawns to
+// maxPhase for all the pieces on the board
+func (p Phased) blend(phase int) int {
+	return (p.Middlegame*phase + p.Endgame*(maxPhase-phase)) / maxPhase
+}
+
+// What each piece counts towards the game phase
+var phaseWeights = [...]int{pawn.Knight: 1, pawn.Bishop: 1, pawn.Rook: 2, pawn.Queen: 4, pawn.King: 0}
+
+const maxPhase = 24
+
+// Every term of the evaluation in centipawns, positive being good for White.
+// Tables and arrays are indexed by pawn.Material.
+type Weights struct {
+	Material [pawn.King + 1]Phased
+
+	// Bonuses for each piece on each square from White's side of the board,
+	// a8 to h8 first down to a1 to h1 last, so they read as a diagram. Black's
+	// are mirrored.
+	PieceSquares [pawn.King + 1][64]Phased
+
+	// Per square a knight, bishop, rook or queen attacks that isn't occupied
+	// by a piece of its own color
+	Mobility [pawn.King + 1]Phased
+
+	DoubledPawn  Phased // For each pawn behind another of its color
+	IsolatedPawn Phased // Without pawns of its color on the files either side
+	// By rank counted from the pawn's side of the board, for a pawn no pawn
+	// of the other color can stop
+	PassedPawn [8]Phased
+
+	BishopPair       Phased
+	RookOpenFile     Phased // On a file without pawns
+	RookSemiOpenFile Phased // On a file without pawns of its color
+
+	KingShield Phased // For each pawn of its color on the three squares in front of the king or the three beyond them
+	KingAttack Phased // For each square around the king the other side's pieces attack
+}
+
+// The evaluation broken into its terms, each from the side to move's point
+// of view and summing to Evaluate's score
+type Terms struct {
+	Material      int
+	PieceSquares  int
+	Mobility      int
+	PawnStructure int
+	Pieces        int // Bishop pair and rooks on open files
+	KingSafety    int
+}
+
+func (t Terms) Total() int {
+	return t.Material + t.PieceSquares + t.Mobility + t.PawnStructure + t.Pieces + t.KingSafety
+}
+
+// The position's worth in centipawns to the side to move with DefaultWeights
+func Evaluate(board *pawn.Board) int {
+	return DefaultWeights.Evaluate(board)
+}
+
+func (w *Weights) Evaluate(board *pawn.Board) int {
+	return w.Terms(board).Total()
+}
+
+// A square's number from 0 for a1, 1 for b1 ... to 63 for h8
+func squareOf(position pawn.Position) int {
+	return (int(position.Rank)-1)*8 + int(position.File[0]-'a')
+}
+
+// The pieces on the board by square number, which evaluation reads a lot
+// more quickly than Board's squares
+type mailbox [64]pawn.Piece
+
+func newMailbox(board *pawn.Board) *mailbox {
+	pieces := &mailbox{}
+
+	for _, square := range board.Squares {
+		if square.Piece != pawn.NoPiece {
+			pieces[squareOf(square.Position)] = square.Piece
+		}
+	}
+
+	return pieces
+}
+
+func (w *Weights) Terms(board *pawn.Board) Terms {
+	pieces := newMailbox(board)
+
+	// Each term from White's point of view
+	var material, pieceSquares, mobility, pawnStructure, piecesTerm, kingSafety Phased
+
+	phase := 0
+	bishops := [2]int{}
+	kings := [2]int{}
+	pawnFiles := [2][8]int{}
+
+	for square, piece := range pieces {
+		if piece == pawn.NoPiece {
+			continue
+		}
+
+		side := sideOf(piece.Color)
+		sign := 1 - 2*side
+
+		material.add(w.Material[piece.Material], sign)
+		pieceSquares.add(w.PieceSquares[piece.Material][tableIndex(square, piece.Color)], sign)
+		phase += phaseWeights[piece.Material]
+
+		switch piece.Material {
+		case pawn.Pawn:
+			pawnFiles[side][square%8]++
+		case pawn.Bishop:
+			bishops[side]++
+		case pawn.King:
+			kings[side] = square
+		}
+	}
+
+	if phase > maxPhase {
+		phase = maxPhase
+	}
+
+	for side, color := range [...]pawn.Color{pawn.White, pawn.Black} {
+		sign := 1 - 2*side
+		other := 1 - side
+
+		if bishops[side] >= 2 {
+			piecesTerm.add(w.BishopPair, sign)
+		}
+
+		for file := 0; file < 8; file++ {
+			if pawnFiles[side][file] > 1 {
+				pawnStructure.add(w.DoubledPawn, sign*(pawnFiles[side][file]-1))
+			}
+
+			isolated := (file == 0 || pawnFiles[side][file-1] == 0) && (file == 7 || pawnFiles[side][file+1] == 0)
+			if isolated && pawnFiles[side][file] > 0 {
+				pawnStructure.add(w.IsolatedPawn, sign*pawnFiles[side][file])
+			}
+		}
+
+		zone := kingZone(kings[other])
+
+		for square, piece := range pieces {
+			if piece.Color != color {
+				continue
+			}
+
+			switch piece.Material {
+			case pawn.Pawn:
+				if passed(pieces, square, color) {
+					pawnStructure.add(w.PassedPawn[relativeRank(square, color)], sign)
+				}
+			case pawn.Rook:
+				file := square % 8
+				if pawnFiles[side][file] == 0 && pawnFiles[other][file] == 0 {
+					piecesTerm.add(w.RookOpenFile, sign)
+				} else if pawnFiles[side][file] == 0 {
+					piecesTerm.add(w.RookSemiOpenFile, sign)
+				}
+			}
+
+			if piece.Material == pawn.Pawn || piece.Material == pawn.King {
+				continue
+			}
+
+			reachable, attacking := pieces.mobility(square, piece, zone)
+			mobility.add(w.Mobility[piece.Material], sign*reachable)
+			kingSafety.add(w.KingAttack, -sign*attacking)
+		}
+
+		kingSafety.add(w.KingShield, sign*pieces.shield(kings[side], color))
+	}
+
+	terms := Terms{
+		Material:      material.blend(phase),
+		PieceSquares:  pieceSquares.blend(phase),
+		Mobility:      mobility.blend(phase),
+		PawnStructure: pawnStructure.blend(phase),
+		Pieces:        piecesTerm.blend(phase),
+		KingSafety:    kingSafety.blend(phase),
+	}
+
+	if board.SideToMove() == pawn.Black {
+		terms = Terms{-terms.Material, -terms.PieceSquares, -terms.Mobility, -terms.PawnStructure, -terms.Pieces, -terms.KingSafety}
+	}
+
+	return terms
+}
+
+func sideOf(color pawn.Color) int {
+	if color == pawn.White {
+		return 0
+	}
+
+	return 1
+}
+
+// Where a piece's bonus is in a piece-square table laid out as a diagram
+func tableIndex(square int, color pawn.Color) int {
+	if color == pawn.White {
+		return square ^ 56
+	}
+
+	return square
+}
+
+// From 0 on the color's back rank to 7 on the far side
+func relativeRank(square int, color pawn.Color) int {
+	if color == pawn.White {
+		return square / 8
+	}
+
+	return 7 - square/8
+}
+
+// Whether no pawn of the other color is ahead of the pawn on its own or an
+// adjacent file
+func passed(pieces *mailbox, square int, color pawn.Color) bool {
+	enemy := pawn.Piece{Color: color.Opponent(), Material: pawn.Pawn}
+	step := 8
+	if color == pawn.Black {
+		step = -8
+	}
+
+	file := square % 8
+	for ahead := square + step; ahead >= 0 && ahead < 64; ahead += step {
+		for adjacent := file - 1; adjacent <= file+1; adjacent++ {
+			if adjacent >= 0 && adjacent < 8 && pieces[ahead-file+adjacent] == enemy {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+// The squares a king stands on or next to, as a bitmask
+func kingZone(king int) uint64 {
+	zone := uint64(1) << king
+	for _, square := range kingSteps[king] {
+		zone |= 1 << square
+	}
+
+	return zone
+}
+
+// The squares a knight, bishop, rook or queen can move to, and how many of
+// those are in zone
+func (m *mailbox) mobility(square int, piece pawn.Piece, zone uint64) (int, int) {
+	reachable, attacking := 0, 0
+
+	count := func(target int) {
+		if m[target].Color != piece.Color {
+			reachable++
+		}
+		if zone&(1<<target) != 0 {
+			attacking++
+		}
+	}
+
+	if piece.Material == pawn.Knight {
+		for _, target := range knightJumps[square] {
+			count(target)
+		}
+
+		return reachable, attacking
+	}
+
+	for direction, ray := range rays[square] {
+		diagonal := direction >= 4
+		if (piece.Material == pawn.Rook && diagonal) || (piece.Material == pawn.Bishop && !diagonal) {
+			continue
+		}
+
+		for _, target := range ray {
+			count(target)
+			if m[target] != pawn.NoPiece {
+				break
+			}
+		}
+	}
+
+	return reachable, attacking
+}
+
+// Pawns of the king's color on the two ranks in front of it on its own and
+// the adjacent files
+func (m *mailbox) shield(king int, color pawn.Color) int {
+	own := pawn.Piece{Color: color, Material: pawn.Pawn}
+	step := 8
+	if color == pawn.Black {
+		step = -8
+	}
+
+	shield := 0
+	file := king % 8
+	for rank := 1; rank <= 2; rank++ {
+		row := king + rank*step
+		if row < 0 || row >= 64 {
+			break
+		}
+
+		for adjacent := file - 1; adjacent <= file+1; adjacent++ {
+			if adjacent >= 0 && adjacent < 8 && m[row-file+adjacent] == own {
+				shield++
+			}
+		}
+	}
+
+	return shield
+}
+
+// Whether neither side has the material to mate: bare kings or a king and a
+// single minor piece against a bare king
+func InsufficientMaterial(board *pawn.Board) bool {
+	minors := 0
+
+	for _, square := range board.Squares {
+		switch square.Material {
+		case pawn.Pawn, pawn.Rook, pawn.Queen:
+			return false
+		case pawn.Knight, pawn.Bishop:
+			minors++
+		}
+	}
+
+	return minors <= 1
+}
+
+// Destinations from every square by square number. Rays run outwards in the
+// order up, down, left, right then the diagonals up-left, up-right,
+// down-left and down-right.
+var (
+	rays        [64][8][]int
+	knightJumps [64][]int
+	kingSteps   [64][]int
+)
+
+func init() {
+	onBoard := func(file, rank int) bool {
+		return file >= 0 && file < 8 && rank >= 0 && rank < 8
+	}
+
+	directions := [8][2]int{{0, 1}, {0, -1}, {-1, 0}, {1, 0}, {-1, 1}, {1, 1}, {-1, -1}, {1, -1}}
+	jumps := [8][2]int{{1, 2}, {2, 1}, {2, -1}, {1, -2}, {-1, -2}, {-2, -1}, {-2, 1}, {-1, 2}}
+
+	for square := 0; square < 64; square++ {
+		file, rank := square%8, square/8
+
+		for index, direction := range directions {
+			for f, r := file+direction[0], rank+direction[1]; onBoard(f, r); f, r = f+direction[0], r+direction[1] {
+				rays[square][index] = append(rays[square][index], r*8+f)
+			}
+
+			if f, r := file+direction[0], rank+direction[1]; onBoard(f, r) {
+				kingSteps[square] = append(kingSteps[square], r*8+f)
+			}
+		}
+
+		for _, jump := range jumps {
+			if f, r := file+jump[0], rank+jump[1]; onBoard(f, r) {
+				knightJumps[square] = append(knightJumps[square], r*8+f)
+			}
+		}
+	}
+}
@@ -0,0 +1,129 @@
+package engine
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/marcel/pawn"
+	"github.com/stretchr/testify/suite"
+)
+
+type EvaluateTestSuite struct {
+	suite.Suite
+}
+
+func TestEvaluateTestSuite(t *testing.T) {
+	suite.Run(t, new(EvaluateTestSuite))
+}
+
+func (s *EvaluateTestSuite) evaluate(fen string) int {
+	board, err := pawn.ParseFEN(fen)
+	s.Require().Nil(err, fen)
+
+	return Evaluate(board)
+}
+
+// The same position with the colors swapped and the board turned around
+func mirror(fen string) string {
+	fields := strings.Fields(fen)
+
+	swapCase := func(text string) string {
+		return strings.Map(func(r rune) rune {
+			if r >= 'a' && r <= 'z' {
+				return r - 'a' + 'A'
+			}
+			if r >= 'A' && r <= 'Z' {
+				return r - 'A' + 'a'
+			}
+			return r
+		}, text)
+	}
+
+	ranks := strings.Split(fields[0], "/")
+	for i, j := 0, len(ranks)-1; i < j; i, j = i+1, j-1 {
+		ranks[i], ranks[j] = ranks[j], ranks[i]
+	}
+	fields[0] = swapCase(strings.Join(ranks, "/"))
+
+	fields[1] = map[string]string{"w": "b", "b": "w"}[fields[1]]
+	fields[2] = swapCase(fields[2])
+	if fields[3] != "-" {
+		fields[3] = fields[3][:1] + map[byte]string{'3': "6", '6': "3"}[fields[3][1]]
+	}
+
+	return strings.Join(fields, " ")
+}
+
+func (s *EvaluateTestSuite) TestSymmetry() {
+	s.Equal(0, s.evaluate(pawn.InitialFEN))
+
+	for _, fen := range []string{
+		"rnbqkbnr/pppppppp/8/8/4P3/8/PPPP1PPP/RNBQKBNR b KQkq e3 0 1",
+		"r1bqkb1r/pppp1ppp/2n2n2/4p3/2B1P3/5N2/PPPP1PPP/RNBQK2R w KQkq - 4 4",
+		"8/5pk1/6p1/3P4/8/2K5/8/8 w - - 0 1",
+		"2r3k1/5pp1/p6p/1p6/3N4/1P4P1/P4P1P/2R3K1 b - - 0 30",
+	} {
+		s.Equal(s.evaluate(fen), s.evaluate(mirror(fen)), fen)
+	}
+}
+
+func (s *EvaluateTestSuite) TestSideToMove() {
+	white := s.evaluate("4k3/8/8/8/8/8/8/R3K3 w - - 0 1")
+	s.True(white > 400)
+	s.Equal(-white, s.evaluate("4k3/8/8/8/8/8/8/R3K3 b - - 0 1"))
+}
+
+func (s *EvaluateTestSuite) TestTerms() {
+	board, _ := pawn.ParseFEN("r1bqkb1r/pppp1ppp/2n2n2/4p3/2B1P3/5N2/PPPP1PPP/RNBQK2R w KQkq - 4 4")
+	terms := DefaultWeights.Terms(board)
+
+	s.Equal(Evaluate(board), terms.Total())
+	s.Equal(0, terms.Material)
+	s.True(terms.Mobility > 0, "the bishop on c4 is out")
+}
+
+// The king belongs in the corner with the queens on and in the center
+// without them
+func (s *EvaluateTestSuite) TestTapering() {
+	board, _ := pawn.ParseFEN("4k3/8/8/8/3K4/8/8/8 w - - 0 1")
+	centralized := DefaultWeights.Terms(board).PieceSquares
+
+	board, _ = pawn.ParseFEN("4k3/8/8/8/8/8/8/6K1 w - - 0 1")
+	s.True(centralized > DefaultWeights.Terms(board).PieceSquares)
+
+	board, _ = pawn.ParseFEN("rnbqkbnr/pppppppp/8/8/3K4/8/PPPPPPPP/RNBQ1R2 w kq - 0 1")
+	centralized = DefaultWeights.Terms(board).PieceSquares
+
+	board, _ = pawn.ParseFEN("rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQ1RK1 w kq - 0 1")
+	s.True(centralized < DefaultWeights.Terms(board).PieceSquares)
+}
+
+func (s *EvaluateTestSuite) TestPawnStructure() {
+	pawnStructure := func(fen string) int {
+		board, _ := pawn.ParseFEN(fen)
+		return DefaultWeights.Terms(board).PawnStructure
+	}
+
+	// A passed pawn against one the other side's pawns can stop
+	s.True(pawnStructure("4k3/8/8/3P4/8/8/8/4K3 w - - 0 1") > pawnStructure("4k3/2p5/8/3P4/8/8/8/4K3 w - - 0 1"))
+
+	// Doubled, isolated and passed, all at the endgame weights without pieces
+	weights := DefaultWeights
+	s.Equal(
+		weights.DoubledPawn.Endgame+2*weights.IsolatedPawn.Endgame+weights.PassedPawn[1].Endgame+weights.PassedPawn[2].Endgame,
+		pawnStructure("4k3/8/8/8/8/4P3/4P3/4K3 w - - 0 1"),
+	)
+}
+
+func (s *EvaluateTestSuite) TestWeights() {
+	board, _ := pawn.ParseFEN("4k3/8/8/8/8/8/8/R3K3 w - - 0 1")
+
+	weights := DefaultWeights
+	weights.Material[pawn.Rook] = Phased{}
+	s.Equal(0, weights.Terms(board).Material)
+	s.Equal(DefaultWeights.Terms(board).Mobility, weights.Terms(board).Mobility)
+
+	engine := New()
+	engine.Weights = weights
+	s.Equal(weights.Evaluate(board), engine.Weights.Evaluate(board))
+}
@@ -0,0 +1,70 @@
+package engine
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var ErrorUnknownOption = errors.New("engine: unknown option")
+
+// The search's heuristics, each of which can be turned off to measure what
+// it's worth. DefaultOptions has them all on.
+type Options struct {
+	// Move ordering
+	HashMove bool // The best move the table or the last iteration found first
+	MVVLVA   bool // Captures of the most valuable victim by the least valuable attacker before others
+	Killers  bool // Quiet moves that caused a cutoff at the same ply next
+	History  bool // Other quiet moves by how often they've caused cutoffs
+
+	// Pruning, reductions and extensions
+	NullMove           bool // Pruning when passing still leaves the side to move above beta
+	LateMoveReductions bool // Searching quiet moves ordered late less deeply
+	Futility           bool // Skipping quiet moves near the leaves that can't raise alpha
+	CheckExtensions    bool // Searching a ply deeper when in check
+}
+
+var DefaultOptions = Options{
+	HashMove:           true,
+	MVVLVA:             true,
+	Killers:            true,
+	History:            true,
+	NullMove:           true,
+	LateMoveReductions: true,
+	Futility:           true,
+	CheckExtensions:    true,
+}
+
+// The names options are set by, as UCI and the command line know them
+var OptionNames = []string{"HashMove", "MVVLVA", "Killers", "History", "NullMove", "LateMoveReductions", "Futility", "CheckExtensions"}
+
+func (o *Options) toggle(name string) *bool {
+	toggles := []*bool{&o.HashMove, &o.MVVLVA, &o.Killers, &o.History, &o.NullMove, &o.LateMoveReductions, &o.Futility, &o.CheckExtensions}
+
+	for index, optionName := range OptionNames {
+		if strings.EqualFold(name, optionName) {
+			return toggles[index]
+		}
+	}
+
+	return nil
+}
+
+// Turns an option on or off by its name, in any case
+func (o *Options) Set(name string, on bool) error {
+	toggle := o.toggle(name)
+	if toggle == nil {
+		return fmt.Errorf("%w: %s", ErrorUnknownOption, name)
+	}
+
+	*toggle = on
+
+	return nil
+}
+
+// Whether the option with the name is on, false for one that doesn't exist
+func (o Options) Enabled(name string) bool {
+	toggle := o.toggle(name)
+
+	return toggle != nil && *toggle
+}
@@ -0,0 +1,35 @@
+package engine
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type OptionsTestSuite struct {
+	suite.Suite
+}
+
+func TestOptionsTestSuite(t *testing.T) {
+	suite.Run(t, new(OptionsTestSuite))
+}
+
+func (s *OptionsTestSuite) TestSet() {
+	options := DefaultOptions
+	for _, name := range OptionNames {
+		s.True(options.Enabled(name), name)
+	}
+
+	s.Nil(options.Set("nullmove", false))
+	s.False(options.NullMove)
+	s.False(options.Enabled("NullMove"))
+	s.True(DefaultOptions.NullMove)
+
+	s.Nil(options.Set("LateMoveReductions", false))
+	s.False(options.LateMoveReductions)
+
+	err := options.Set("Nonsense", false)
+	s.True(errors.Is(err, ErrorUnknownOption))
+	s.False(options.Enabled("Nonsense"))
+}
@@ -0,0 +1,765 @@
+// Package engine searches chess positions for the best move, playing through
+// them with the pawn package's Board.
+package engine
+
+import (
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/marcel/pawn"
+)
+
+// Scores are in centipawns from the side to move's point of view. A mate is
+// scored Mate less the plies until it, so nearer mates score higher.
+const (
+	Mate     = 32000
+	MaxDepth = 64
+
+	maxPly   = 128
+	infinity = Mate + 1
+
+	// How often, in nodes, the search checks whether to stop
+	stopCheckInterval = 1024
+
+	// History scores are halved past this, so recent cutoffs count for more
+	maxHistory = 1 << 16
+)
+
+// What the static evaluation must fall short of alpha by for quiet moves to
+// be skipped, by the depth left
+var futilityMargins = [...]int{0, 200, 500}
+
+// Whether score is a forced mate for either side
+func IsMateScore(score int) bool {
+	return score > Mate-maxPly || score < -Mate+maxPly
+}
+
+// The moves until mate for a mate score, negative when the side to move is
+// the one being mated
+func MateIn(score int) int {
+	if score > 0 {
+		return (Mate - score + 1) / 2
+	}
+
+	return -(Mate + score) / 2
+}
+
+// When to stop searching. The zero value searches to MaxDepth.
+type Limits struct {
+	Depth    int           // Plies, or zero for no limit
+	Nodes    uint64        // Zero for no limit
+	MoveTime time.Duration // Exactly this long, or zero for no limit
+
+	// The side to move's clock, which the engine budgets from when there's no
+	// MoveTime: the time it has left, its increment per move and the moves to
+	// the next time control, or zero for the rest of the game
+	Time      time.Duration
+	Increment time.Duration
+	MovesToGo int
+
+	// Setting PonderHit searches on the opponent's time, without a time limit
+	// until it's closed, when the time counts from then
+	PonderHit <-chan struct{}
+
+	// Closing Stop stops the search as Engine.Stop does
+	Stop <-chan struct{}
+
+	// The lines to find, each starting with a different move, or zero for
+	// just the best
+	MultiPV int
+}
+
+// The best line's move, score and principal variation, and with a MultiPV
+// limit the others found, ranked
+type Result struct {
+	Move  pawn.Move // The zero Move when the side to move has no moves
+	Score int
+	Depth int // Of the deepest iteration completed
+	PV    []pawn.Move
+	Lines []Line // Best first, the first being the result's own
+	Nodes uint64
+	Time  time.Duration
+}
+
+// A move with its score and the principal variation it starts
+type Line struct {
+	Move  pawn.Move
+	Score int
+	PV    []pawn.Move
+}
+
+// An engine searches one position at a time and can be stopped from another
+// goroutine
+type Engine struct {
+	// Called with the result so far each time an iteration completes
+	Progress func(Result)
+
+	// What positions are evaluated with, DefaultWeights to begin with
+	Weights Weights
+
+	// Kept between searches, so should be cleared for a new game
+	Table *TranspositionTable
+
+	// The wall clock unless set
+	Clock Clock
+
+	// Which heuristics the search uses, DefaultOptions to begin with
+	Options Options
+
+	// Goroutines to search with, 1 unless set. Past the first they're
+	// helpers, searching the same position on their own boards to fill the
+	// table for the first.
+	Threads int
+
+	stopped int32
+	halted  int32 // Set when the first worker is done, to stop the helpers
+
+	limits  Limits
+	start   time.Time
+	timer   timeManager
+	nodes   uint64 // Counted by the workers together, a stopCheckInterval at a time
+	workers []*worker
+}
+
+// Searches the position for an engine, each of its threads having its own
+type worker struct {
+	engine *Engine
+	main   bool // Whether it keeps time and reports progress
+	board  *pawn.Board
+	stats  TableStats
+	result Result
+
+	nodes     uint64
+	aborted   bool
+	followPV  bool
+	lastPV    []pawn.Move
+	lastLines []Line      // Found by the last iteration completed
+	excluded  []pawn.Move // Moves at the root already leading a line this iteration
+	pv        [maxPly][maxPly]pawn.Move
+	pvLength  [maxPly]int
+
+	killers [maxPly][2]pawn.Move // The last two quiet moves to cause a cutoff at each ply
+	history [2][64][64]int       // Cutoffs by quiet moves for each side, from and to square
+	null    [maxPly]bool         // Whether the move to each ply was a null move
+}
+
+func New() *Engine {
+	return &Engine{
+		Weights: DefaultWeights,
+		Table:   NewTranspositionTable(DefaultHashMegabytes),
+		Options: DefaultOptions,
+		Threads: 1,
+	}
+}
+
+// Searches board with a new engine
+func Search(board *pawn.Board, limits Limits) Result {
+	return New().Search(board, limits)
+}
+
+// Stops a search in progress, which returns the result of its last complete
+// iteration. A stop made before a search starts stops that search at once.
+func (e *Engine) Stop() {
+	atomic.StoreInt32(&e.stopped, 1)
+}
+
+// Finds the best move by iterative deepening, searching one ply deeper each
+// iteration until a limit is reached or a mate is found within the depth
+// searched. The board is played through and left as it was. With a MultiPV
+// limit, each iteration searches the next best moves too, for lines that
+// rank what else was playable.
+//
+// With more than one thread, helpers search alongside from every other
+// depth, sharing the table, until the first thread is done. The deepest
+// iteration any of them completed is the result.
+func (e *Engine) Search(board *pawn.Board, limits Limits) Result {
+	defer atomic.StoreInt32(&e.stopped, 0)
+
+	clock := e.Clock
+	if clock == nil {
+		clock = wallClock{}
+	}
+
+	e.limits, e.timer = limits, newTimeManager(clock, limits)
+	e.start, e.nodes, e.halted = e.timer.start, 0, 0
+	e.Table.newSearch()
+
+	threads := e.Threads
+	if threads < 1 {
+		threads = 1
+	}
+	for len(e.workers) < threads {
+		e.workers = append(e.workers, &worker{engine: e, main: len(e.workers) == 0})
+	}
+	workers := e.workers[:threads]
+
+	var helpers sync.WaitGroup
+	for index, helper := range workers[1:] {
+		helpers.Add(1)
+		go func(helper *worker, board *pawn.Board, depth int) {
+			defer helpers.Done()
+			helper.iterate(board, depth)
+		}(helper, board.Copy(), 1+(index+1)%2)
+	}
+
+	workers[0].iterate(board, 1)
+
+	atomic.StoreInt32(&e.halted, 1)
+	helpers.Wait()
+
+	result := workers[0].result
+	nodes := uint64(0)
+	for _, worker := range workers {
+		if worker.result.Depth > result.Depth {
+			result = worker.result
+		}
+
+		nodes += worker.nodes
+		e.Table.record(worker.stats)
+	}
+
+	result.Nodes, result.Time = nodes, e.timer.clock.Now().Sub(e.start)
+
+	return result
+}
+
+// Searches board one ply deeper at a time from depth, keeping the result of
+// each iteration completed
+func (w *worker) iterate(board *pawn.Board, depth int) {
+	e := w.engine
+	w.board, w.stats, w.nodes, w.aborted, w.lastPV, w.lastLines = board, TableStats{}, 0, false, nil, nil
+	w.killers, w.null = [maxPly][2]pawn.Move{}, [maxPly]bool{}
+	w.ageHistory()
+
+	maxDepth := MaxDepth
+	if e.limits.Depth > 0 && e.limits.Depth < MaxDepth {
+		maxDepth = e.limits.Depth
+	}
+
+	w.result = Result{}
+	moves := board.LegalMoves()
+	if len(moves) > 0 {
+		w.result.Move, w.result.PV = moves[0], []pawn.Move{moves[0]}
+		w.result.Lines = []Line{{Move: moves[0], PV: w.result.PV}}
+	} else if board.InCheck() {
+		w.result.Score = -Mate
+	}
+
+	count := e.limits.MultiPV
+	if count < 1 {
+		count = 1
+	}
+	if count > len(moves) {
+		count = len(moves)
+	}
+
+	for ; depth <= maxDepth && len(w.result.PV) > 0; depth++ {
+		lines := w.searchLines(depth, count)
+
+		// A partial iteration is only worth keeping if it's the first
+		if w.aborted && (depth > 1 || !w.main) {
+			break
+		}
+
+		if len(lines) > 0 {
+			w.lastLines = lines
+			w.result = Result{Move: lines[0].Move, Score: lines[0].Score, Depth: depth, PV: lines[0].PV, Lines: lines}
+		}
+
+		if w.aborted {
+			break
+		}
+
+		score := w.result.Score
+		mated := IsMateScore(score) && abs(Mate-abs(score)) <= depth
+		if !w.main {
+			if mated || w.stopRequested() {
+				break
+			}
+			continue
+		}
+
+		if e.Progress != nil {
+			progress := w.result
+			progress.Nodes = atomic.LoadUint64(&e.nodes) + w.nodes%stopCheckInterval
+			progress.Time = e.timer.clock.Now().Sub(e.start)
+			e.Progress(progress)
+		}
+
+		e.timer.iterationDone(w.result.Move, score)
+
+		if mated || w.stopRequested() || !e.timer.nextIteration() {
+			break
+		}
+	}
+}
+
+// Searches the root for count lines, each time leaving out the moves that
+// lead the lines already found, and ranks them. Each follows the line the
+// last iteration found for its move, if any. Once aborted, only the first
+// line is kept, as far as it got.
+func (w *worker) searchLines(depth, count int) []Line {
+	lines := []Line{}
+	w.excluded = w.excluded[:0]
+	defer func() { w.excluded = w.excluded[:0] }()
+
+	for len(lines) < count {
+		w.followPV, w.lastPV = true, nil
+		for _, line := range w.lastLines {
+			if !w.isExcluded(line.Move) {
+				w.lastPV = line.PV
+				break
+			}
+		}
+
+		score := w.negamax(depth, 0, -infinity, infinity)
+		if w.pvLength[0] == 0 || (w.aborted && len(lines) > 0) {
+			break
+		}
+
+		pv := w.extendPV(append([]pawn.Move{}, w.pv[0][:w.pvLength[0]]...), depth)
+		lines = append(lines, Line{Move: pv[0], Score: score, PV: pv})
+		w.excluded = append(w.excluded, pv[0])
+
+		if w.aborted {
+			break
+		}
+	}
+
+	sort.SliceStable(lines, func(i, j int) bool {
+		return lines[i].Score > lines[j].Score
+	})
+
+	return lines
+}
+
+// Counts a node, noting when a limit has been reached
+func (w *worker) visit() {
+	e := w.engine
+	w.nodes++
+
+	if w.nodes%stopCheckInterval == 0 {
+		atomic.AddUint64(&e.nodes, stopCheckInterval)
+
+		if w.stopRequested() || (w.main && e.timer.outOfTime()) {
+			w.aborted = true
+		}
+	}
+
+	if e.limits.Nodes > 0 && atomic.LoadUint64(&e.nodes)+w.nodes%stopCheckInterval >= e.limits.Nodes {
+		w.aborted = true
+	}
+}
+
+func (w *worker) stopRequested() bool {
+	e := w.engine
+
+	select {
+	case <-e.limits.Stop:
+		return true
+	default:
+		return atomic.LoadInt32(&e.stopped) == 1 || atomic.LoadInt32(&e.halted) == 1
+	}
+}
+
+func (w *worker) drawn() bool {
+	return w.board.HalfmoveClock() >= 100 || w.board.IsRepetition() || InsufficientMaterial(w.board)
+}
+
+// Alpha-beta search in negamax form, scores always being from the side to
+// move's point of view. The principal variation found from ply on is left in
+// pv[ply].
+func (w *worker) negamax(depth, ply, alpha, beta int) int {
+	w.pvLength[ply] = ply
+	onPV := w.followPV
+	options := &w.engine.Options
+
+	if ply > 0 && w.drawn() {
+		return 0
+	}
+
+	inCheck := w.board.InCheck()
+	if inCheck && options.CheckExtensions {
+		depth++
+	}
+
+	if depth <= 0 || ply >= maxPly-1 {
+		return w.quiesce(ply, alpha, beta)
+	}
+
+	w.visit()
+
+	hash := w.board.Hash()
+	entry, found := w.engine.Table.probe(hash, &w.stats)
+	if found && ply > 0 && entry.depth >= depth {
+		score := scoreFromTable(entry.score, ply)
+
+		switch {
+		case entry.bound == exactBound,
+			entry.bound == lowerBound && score >= beta,
+			entry.bound == upperBound && score <= alpha:
+			return score
+		}
+	}
+
+	// If passing still leaves the side to move at or above beta, a real move
+	// almost certainly would, except in zugzwang, which is rare with pieces
+	// left. Mates aren't proved this way.
+	if options.NullMove && ply > 0 && !onPV && !inCheck && !w.null[ply] && depth >= 3 && !IsMateScore(beta) && w.hasPieces() {
+		reduction := 2
+		if depth > 6 {
+			reduction = 3
+		}
+
+		w.null[ply+1] = true
+		w.board.MakeNullMove()
+		score := -w.negamax(depth-1-reduction, ply+1, -beta, -beta+1)
+		w.board.UnmakeMove()
+		w.null[ply+1] = false
+
+		if w.aborted {
+			return 0
+		}
+
+		if score >= beta {
+			return beta
+		}
+	}
+
+	moves := w.board.LegalMoves()
+	if len(moves) == 0 {
+		if inCheck {
+			return -Mate + ply
+		}
+		return 0
+	}
+
+	var hashMove pawn.Move
+	if options.HashMove {
+		if onPV && ply < len(w.lastPV) {
+			hashMove = w.lastPV[ply]
+		} else if found && entry.move != 0 {
+			hashMove, _ = w.board.DecodeMove(entry.move)
+		}
+	}
+	w.orderMoves(moves, hashMove, ply)
+
+	// Near the leaves, quiet moves can't make up for a position this far
+	// below alpha
+	futile, futilityScore := false, 0
+	if options.Futility && ply > 0 && depth < len(futilityMargins) && !inCheck && !IsMateScore(alpha) {
+		futilityScore = w.engine.Weights.Evaluate(w.board) + futilityMargins[depth]
+		futile = futilityScore <= alpha
+	}
+
+	originalAlpha := alpha
+	best, bestMove := -infinity, pawn.Move{}
+	searched := 0
+	for _, move := range moves {
+		if ply == 0 && w.isExcluded(move) {
+			continue
+		}
+
+		quiet := !move.Takes && move.Promotion == 0
+		w.followPV = onPV && move == hashMove
+
+		w.board.MakeMove(move)
+
+		checks := false
+		if quiet && (futile || searched >= 3) {
+			checks = w.board.InCheck()
+		}
+
+		if futile && quiet && !checks && searched > 0 {
+			w.board.UnmakeMove()
+
+			if futilityScore > best {
+				best = futilityScore
+			}
+			continue
+		}
+
+		w.null[ply+1] = false
+
+		// Moves ordered late rarely turn out best, so they're searched less
+		// deeply with a null window first, then fully if they beat alpha
+		var score int
+		reduction := 0
+		if options.LateMoveReductions && searched >= 3 && depth >= 3 && quiet && !checks && !inCheck && !w.isKiller(ply, move) {
+			reduction = 1
+			if searched >= 6 && depth >= 6 {
+				reduction = 2
+			}
+
+			score = -w.negamax(depth-1-reduction, ply+1, -alpha-1, -alpha)
+		}
+		if reduction == 0 || (score > alpha && !w.aborted) {
+			score = -w.negamax(depth-1, ply+1, -beta, -alpha)
+		}
+
+		w.board.UnmakeMove()
+		searched++
+
+		if w.aborted {
+			return 0
+		}
+
+		if score > best {
+			best, bestMove = score, move
+		}
+
+		if score > alpha {
+			alpha = score
+			w.updatePV(ply, move)
+
+			if score >= beta {
+				if quiet {
+					w.cutoff(ply, depth, move)
+				}
+				break
+			}
+		}
+	}
+
+	// With moves left out, the root's score isn't the position's
+	if ply == 0 && len(w.excluded) > 0 {
+		return best
+	}
+
+	stored := tableData{move: w.board.EncodeMove(bestMove), score: scoreToTable(best, ply), depth: depth, bound: exactBound}
+	switch {
+	case best <= originalAlpha:
+		stored.bound, stored.move = upperBound, 0
+	case best >= beta:
+		stored.bound = lowerBound
+	}
+	w.engine.Table.store(hash, stored, &w.stats)
+
+	return best
+}
+
+// Searches captures and promotions until the position is quiet, so the
+// evaluation isn't taken in the middle of an exchange. The side to move may
+// stand pat on the evaluation rather than capture, unless in check, when
+// every move is searched.
+func (w *worker) quiesce(ply, alpha, beta int) int {
+	w.pvLength[ply] = ply
+	w.visit()
+
+	if InsufficientMaterial(w.board) {
+		return 0
+	}
+
+	if ply >= maxPly-1 {
+		return w.engine.Weights.Evaluate(w.board)
+	}
+
+	inCheck := w.board.InCheck()
+
+	best := -infinity
+	if !inCheck {
+		best = w.engine.Weights.Evaluate(w.board)
+		if best >= beta {
+			return best
+		}
+		if best > alpha {
+			alpha = best
+		}
+	}
+
+	moves := w.board.LegalMoves()
+	if len(moves) == 0 {
+		if inCheck {
+			return -Mate + ply
+		}
+		return 0
+	}
+	w.orderMoves(moves, pawn.Move{}, ply)
+
+	for _, move := range moves {
+		if !inCheck && !move.Takes && move.Promotion == 0 {
+			continue
+		}
+
+		w.board.MakeMove(move)
+		score := -w.quiesce(ply+1, -beta, -alpha)
+		w.board.UnmakeMove()
+
+		if w.aborted {
+			return 0
+		}
+
+		if score > best {
+			best = score
+		}
+
+		if score > alpha {
+			alpha = score
+			if score >= beta {
+				break
+			}
+		}
+	}
+
+	return best
+}
+
+// Lengthens a principal variation cut short by a table hit with the best
+// moves stored for the positions following it, up to depth moves
+func (w *worker) extendPV(pv []pawn.Move, depth int) []pawn.Move {
+	for _, move := range pv {
+		w.board.MakeMove(move)
+	}
+
+	for len(pv) < depth {
+		entry, found := w.engine.Table.lookup(w.board.Hash())
+		if !found || entry.move == 0 {
+			break
+		}
+
+		move, err := w.board.DecodeMove(entry.move)
+		if err != nil || !w.board.IsLegal(move) || w.board.IsRepetition() {
+			break
+		}
+
+		pv = append(pv, move)
+		w.board.MakeMove(move)
+	}
+
+	for range pv {
+		w.board.UnmakeMove()
+	}
+
+	return pv
+}
+
+func (w *worker) updatePV(ply int, move pawn.Move) {
+	w.pv[ply][ply] = move
+	copy(w.pv[ply][ply+1:], w.pv[ply+1][ply+1:w.pvLength[ply+1]])
+	w.pvLength[ply] = w.pvLength[ply+1]
+}
+
+// Whether the side to move has a piece other than pawns and its king, without
+// which passing is too often the best it could do
+func (w *worker) hasPieces() bool {
+	color := w.board.SideToMove()
+
+	for _, square := range w.board.Squares {
+		piece := square.Piece
+		if piece != pawn.NoPiece && piece.Color == color && piece.Material != pawn.Pawn && piece.Material != pawn.King {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (w *worker) isExcluded(move pawn.Move) bool {
+	for _, excluded := range w.excluded {
+		if move == excluded {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (w *worker) isKiller(ply int, move pawn.Move) bool {
+	return w.engine.Options.Killers && (move == w.killers[ply][0] || move == w.killers[ply][1])
+}
+
+// Notes a quiet move that caused a cutoff, as a killer at its ply and in the
+// history, which counts deeper cutoffs for more
+func (w *worker) cutoff(ply, depth int, move pawn.Move) {
+	if move != w.killers[ply][0] {
+		w.killers[ply][1], w.killers[ply][0] = w.killers[ply][0], move
+	}
+
+	history := &w.history[sideOf(move.Color)][squareOf(move.From)][squareOf(move.To)]
+	*history += depth * depth
+	if *history > maxHistory {
+		w.ageHistory()
+	}
+}
+
+// Halves the history, so it's soon dominated by the cutoffs of the current
+// search
+func (w *worker) ageHistory() {
+	for side := range w.history {
+		for from := range w.history[side] {
+			for to := range w.history[side][from] {
+				w.history[side][from][to] /= 2
+			}
+		}
+	}
+}
+
+// The order moves are searched in, highest first
+const (
+	hashMoveOrder = 1 << 30
+	captureOrder  = 1 << 20
+	killerOrder   = 1 << 18
+)
+
+// What pieces are worth when ordering captures, in the order of their
+// values rather than pawn.Material's
+var orderValues = [...]int{pawn.Pawn: 1, pawn.Knight: 2, pawn.Bishop: 2, pawn.Rook: 3, pawn.Queen: 4, pawn.King: 5}
+
+// Puts the hash move first, then captures and promotions, most valuable
+// victim by least valuable attacker first, then killers and other quiet
+// moves by their history, as far as the engine's options allow
+func (w *worker) orderMoves(moves []pawn.Move, hashMove pawn.Move, ply int) {
+	options := &w.engine.Options
+
+	key := func(move pawn.Move) int {
+		switch {
+		case move == hashMove:
+			return hashMoveOrder
+		case move.Takes || move.Promotion != 0:
+			if !options.MVVLVA {
+				return captureOrder
+			}
+
+			victim := 0
+			if move.Takes {
+				victim = orderValues[pawn.Pawn] // Taken en passant when there's nothing on the square
+				if piece := w.board.SquareAtPosition(move.To).Piece; piece != pawn.NoPiece {
+					victim = orderValues[piece.Material]
+				}
+			}
+
+			return captureOrder + (victim+orderValues[move.Promotion])*16 - orderValues[move.Material]
+		case options.Killers && move == w.killers[ply][0]:
+			return killerOrder + 1
+		case options.Killers && move == w.killers[ply][1]:
+			return killerOrder
+		case options.History:
+			return w.history[sideOf(move.Color)][squareOf(move.From)][squareOf(move.To)]
+		default:
+			return 0
+		}
+	}
+
+	keys := make([]int, len(moves))
+	for index, move := range moves {
+		keys[index] = key(move)
+	}
+
+	// Insertion sort is stable and quick for so few moves
+	for i := 1; i < len(moves); i++ {
+		for j := i; j > 0 && keys[j] > keys[j-1]; j-- {
+			moves[j], moves[j-1] = moves[j-1], moves[j]
+			keys[j], keys[j-1] = keys[j-1], keys[j]
+		}
+	}
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+
+	return n
+}
@@ -0,0 +1,260 @@
+package engine
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/marcel/pawn"
+	"github.com/stretchr/testify/suite"
+)
+
+type SearchTestSuite struct {
+	suite.Suite
+}
+
+func TestSearchTestSuite(t *testing.T) {
+	suite.Run(t, new(SearchTestSuite))
+}
+
+func (s *SearchTestSuite) board(fen string) *pawn.Board {
+	board, err := pawn.ParseFEN(fen)
+	s.Require().Nil(err)
+
+	return board
+}
+
+// Plays the principal variation through, checking every move is legal
+func (s *SearchTestSuite) playPV(board *pawn.Board, pv []pawn.Move) {
+	for _, move := range pv {
+		s.Require().True(board.IsLegal(move), "%v", move)
+		board.MakeMove(move)
+	}
+}
+
+func (s *SearchTestSuite) TestMateInOne() {
+	board := s.board("6k1/5ppp/8/8/8/8/5PPP/3R2K1 w - - 0 1")
+	result := Search(board, Limits{Depth: 4})
+
+	s.Equal(pawn.Move{Piece: pawn.Piece{Color: pawn.White, Material: pawn.Rook}, From: pawn.D1, To: pawn.D8}, result.Move)
+	s.Equal(Mate-1, result.Score)
+	s.Equal(1, MateIn(result.Score))
+	s.Equal(1, result.Depth, "stops once the mate is found")
+}
+
+func (s *SearchTestSuite) TestMateInTwo() {
+	board := s.board("7k/8/8/8/8/8/R7/1R5K w - - 0 1")
+	result := Search(board, Limits{Depth: 5})
+
+	s.True(IsMateScore(result.Score))
+	s.Equal(2, MateIn(result.Score))
+	s.Len(result.PV, 3)
+
+	s.playPV(board, result.PV)
+	s.True(board.IsCheckmate())
+}
+
+func (s *SearchTestSuite) TestMated() {
+	board := s.board("7k/R7/1R6/8/8/8/8/7K b - - 0 1")
+	result := Search(board, Limits{Depth: 4})
+
+	s.Equal(-1, MateIn(result.Score))
+	s.Equal(pawn.G8, result.Move.To)
+
+	checkmated := Search(s.board("1R5k/R7/8/8/8/8/8/7K b - - 0 1"), Limits{Depth: 4})
+	s.Equal(pawn.Move{}, checkmated.Move)
+	s.Equal(-Mate, checkmated.Score)
+	s.Empty(checkmated.PV)
+}
+
+func (s *SearchTestSuite) TestDraws() {
+	stalemate := Search(s.board("7k/5Q2/6K1/8/8/8/8/8 b - - 0 1"), Limits{Depth: 3})
+	s.Equal(0, stalemate.Score)
+	s.Equal(pawn.Move{}, stalemate.Move)
+
+	// Taking the last pawn leaves a bishop that can't mate
+	bare := Search(s.board("8/8/4k3/8/8/3pK3/8/7B b - - 0 1"), Limits{Depth: 3})
+	s.Equal(0, bare.Score)
+}
+
+func (s *SearchTestSuite) TestWinsMaterial() {
+	result := Search(s.board("4k3/8/8/3q4/8/8/8/3RK3 w - - 0 1"), Limits{Depth: 3})
+
+	s.Equal(pawn.D5, result.Move.To)
+	s.True(result.Score > 400)
+}
+
+// Quiescence sees the recapture past the horizon of a one ply search
+func (s *SearchTestSuite) TestQuiescence() {
+	result := Search(s.board("4k3/8/3p4/4p3/8/8/8/4QK2 w - - 0 1"), Limits{Depth: 1})
+
+	s.NotEqual(pawn.E5, result.Move.To)
+	s.True(result.Score > 600)
+}
+
+func (s *SearchTestSuite) TestLimits() {
+	board := pawn.NewBoard()
+	fen := board.FEN()
+
+	engine := New()
+	depths := []int{}
+	engine.Progress = func(result Result) {
+		depths = append(depths, result.Depth)
+		s.Equal(result.PV[0], result.Move)
+	}
+
+	result := engine.Search(board, Limits{Depth: 3})
+	s.Equal(3, result.Depth)
+	s.Equal([]int{1, 2, 3}, depths)
+	s.Equal(fen, board.FEN(), "the board is left as it was")
+	s.playPV(pawn.NewBoard(), result.PV)
+
+	limited := engine.Search(board, Limits{Nodes: 2000})
+	s.Equal(uint64(2000), limited.Nodes)
+	s.NotEqual(pawn.Move{}, limited.Move)
+	s.Equal(fen, board.FEN())
+
+	engine.Stop()
+	stopped := engine.Search(board, Limits{})
+	s.True(stopped.Depth <= 1)
+	s.NotEqual(pawn.Move{}, stopped.Move)
+}
+
+func (s *SearchTestSuite) TestThreads() {
+	board := s.board("r1bqkb1r/pppp1ppp/2n2n2/4p3/2B1P3/5N2/PPPP1PPP/RNBQK2R w KQkq - 4 4")
+	fen := board.FEN()
+
+	engine := New()
+	engine.Threads = 3
+	progress := 0
+	engine.Progress = func(Result) {
+		progress++
+	}
+
+	result := engine.Search(board, Limits{Depth: 3})
+	s.True(result.Depth >= 3)
+	s.True(progress >= 1, "the first thread reports progress")
+	s.Equal(fen, board.FEN())
+	s.playPV(s.board(fen), result.PV)
+	for _, worker := range engine.workers {
+		s.True(worker.nodes > 0, "every thread searches")
+	}
+
+	mate := engine.Search(s.board("7k/8/8/8/8/8/R7/1R5K w - - 0 1"), Limits{Depth: 5})
+	s.Equal(2, MateIn(mate.Score))
+
+	limited := engine.Search(board, Limits{Nodes: 5000})
+	s.True(limited.Nodes >= 5000 && limited.Nodes < 5000+3*stopCheckInterval, "%d", limited.Nodes)
+
+	stop := make(chan struct{})
+	time.AfterFunc(100*time.Millisecond, func() {
+		close(stop)
+	})
+	stopped := engine.Search(board, Limits{Stop: stop})
+	s.NotEqual(pawn.Move{}, stopped.Move)
+}
+
+func (s *SearchTestSuite) TestMultiPV() {
+	board := s.board("r1bq1rk1/pp2bppp/2n1pn2/3p4/2PP4/2N1PN2/PP3PPP/R1BQKB1R w KQ - 0 8")
+	fen := board.FEN()
+
+	result := Search(board, Limits{Depth: 3, MultiPV: 4})
+	s.Equal(fen, board.FEN())
+	s.Len(result.Lines, 4)
+	s.Equal(result.Move, result.Lines[0].Move)
+	s.Equal(result.Score, result.Lines[0].Score)
+	s.Equal(result.PV, result.Lines[0].PV)
+
+	moves := map[pawn.Move]bool{}
+	for index, line := range result.Lines {
+		s.Equal(line.Move, line.PV[0])
+		s.False(moves[line.Move], "each line starts with a different move")
+		moves[line.Move] = true
+
+		if index > 0 {
+			s.LessOrEqual(line.Score, result.Lines[index-1].Score)
+		}
+
+		s.playPV(board.Copy(), line.PV)
+	}
+
+	// The best line is the one found without MultiPV
+	s.Equal(Search(board, Limits{Depth: 3}).Move, result.Move)
+
+	// No more lines than there are moves
+	kings := Search(s.board("7k/8/8/8/8/8/8/K7 w - - 0 1"), Limits{Depth: 2, MultiPV: 10})
+	s.Len(kings.Lines, 3)
+}
+
+func (s *SearchTestSuite) TestMoveOrder() {
+	// A knight can take a rook on d5 and a rook a knight on a1
+	board := s.board("4k3/8/8/3r4/8/2N5/8/n2R2K1 w - - 0 1")
+	w := &worker{engine: New(), board: board}
+
+	moves := board.LegalMoves()
+	w.orderMoves(moves, pawn.Move{}, 0)
+
+	order := map[pawn.AlgebraicNotation]int{}
+	for index, move := range moves {
+		order[board.Algebraic(move)] = index
+	}
+
+	s.Less(order["Nxd5"], order["Rxa1"], "knight takes rook before rook takes knight")
+	s.Less(order["Nxd5"], order["Rxd5"], "the least valuable attacker first")
+	s.Less(order["Rxd5"], order["Rxa1"], "the most valuable victim first")
+}
+
+func (s *SearchTestSuite) TestOptions() {
+	// Every heuristic can be turned off without losing the mate
+	for _, name := range OptionNames {
+		engine := New()
+		s.Nil(engine.Options.Set(name, false))
+
+		mate := engine.Search(s.board("7k/8/8/8/8/8/R7/1R5K w - - 0 1"), Limits{Depth: 5})
+		s.Equal(2, MateIn(mate.Score), name)
+	}
+
+	// Together they search far fewer nodes. Without MVV-LVA the captures
+	// searched until a position is quiet are too many to compare with.
+	plain := New()
+	for _, name := range OptionNames {
+		plain.Options.Set(name, name == "MVVLVA")
+	}
+
+	board := s.board("r1bq1rk1/pp2bppp/2n1pn2/3p4/2PP4/2N1PN2/PP3PPP/R1BQKB1R w KQ - 0 8")
+	heuristics := New().Search(board, Limits{Depth: 3})
+	s.Less(2*heuristics.Nodes, plain.Search(board, Limits{Depth: 3}).Nodes)
+}
+
+func (s *SearchTestSuite) TestMateIn() {
+	s.Equal(1, MateIn(Mate-1))
+	s.Equal(3, MateIn(Mate-5))
+	s.Equal(-2, MateIn(-Mate+4))
+	s.True(IsMateScore(-Mate + 10))
+	s.False(IsMateScore(900))
+}
+
+// Nodes searched per second with more threads, which grows with the cores
+// there are to run them
+func BenchmarkThreads(b *testing.B) {
+	board, err := pawn.ParseFEN("r1bq1rk1/pp2bppp/2n1pn2/3p4/2PP4/2N1PN2/PP2BPPP/R2QKB1R w KQ - 0 8")
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	for _, threads := range []int{1, 2, 4, 8} {
+		b.Run(fmt.Sprintf("threads=%d", threads), func(b *testing.B) {
+			engine := New()
+			engine.Threads = threads
+
+			nodes, elapsed := uint64(0), time.Duration(0)
+			for i := 0; i < b.N; i++ {
+				engine.Table.Clear()
+				result := engine.Search(board, Limits{Nodes: 50000})
+				nodes, elapsed = nodes+result.Nodes, elapsed+result.Time
+			}
+
+			b.ReportMetric(float64(nodes)/elapsed.Seconds(), "nodes/s")
+		})
+	}
+}
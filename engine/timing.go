@@ -0,0 +1,166 @@
+package engine
+
+import (
+	"time"
+
+	"github.com/marcel/pawn"
+)
+
+const (
+	// Kept back from the clock for the time a move takes to reach the GUI
+	moveOverhead = 50 * time.Millisecond
+
+	// The moves a clock is shared between when there's no time control to
+	// reach
+	defaultMovesToGo = 30
+
+	// How many times its share a move may take when the search is unsettled
+	hardLimitScale = 4
+
+	// A fall in score between iterations, in centipawns, that counts as the
+	// root failing low
+	failLowMargin = 30
+)
+
+// Where the search reads the time from. Engine uses the wall clock unless
+// given another, as tests do to control time.
+type Clock interface {
+	Now() time.Time
+}
+
+type wallClock struct{}
+
+func (wallClock) Now() time.Time {
+	return time.Now()
+}
+
+// Decides when a search is out of time. It isn't meant to start an iteration
+// past its soft limit, which is stretched while the best move keeps changing
+// or the score falls, and is stopped mid-iteration at its hard limit. A fixed
+// move time is both limits. While pondering neither applies, the time
+// counting from the ponder hit.
+type timeManager struct {
+	clock Clock
+	start time.Time
+
+	soft, hard time.Duration // Zero for no limit
+	stretch    bool          // Whether the soft limit may be stretched
+
+	ponderHit <-chan struct{}
+	pondering bool
+
+	iterations  int
+	lastMove    pawn.Move
+	lastScore   int
+	instability float64 // Best move changes, halved each iteration
+	failingLow  bool
+}
+
+func newTimeManager(clock Clock, limits Limits) timeManager {
+	t := timeManager{clock: clock, start: clock.Now(), ponderHit: limits.PonderHit, pondering: limits.PonderHit != nil}
+
+	switch {
+	case limits.MoveTime > 0:
+		t.soft, t.hard = limits.MoveTime, limits.MoveTime
+	case limits.Time > 0:
+		t.soft, t.hard = budget(limits.Time, limits.Increment, limits.MovesToGo)
+		t.stretch = true
+	}
+
+	return t
+}
+
+// The soft and hard limits for a move with remaining on the clock: an even
+// share of the time to the next time control, or of 30 moves without one,
+// plus most of the increment, and several times that. Neither is more than
+// is left.
+func budget(remaining, increment time.Duration, movesToGo int) (time.Duration, time.Duration) {
+	if movesToGo <= 0 {
+		movesToGo = defaultMovesToGo
+	}
+
+	available := remaining - moveOverhead
+	if available < time.Millisecond {
+		available = time.Millisecond
+	}
+
+	soft := remaining/time.Duration(movesToGo) + increment*3/4
+	hard := soft * hardLimitScale
+
+	if soft > available {
+		soft = available
+	}
+	if hard > available {
+		hard = available
+	}
+
+	return soft, hard
+}
+
+// Notices the ponder hit, starting the clock from it
+func (t *timeManager) checkPonderHit() {
+	if !t.pondering {
+		return
+	}
+
+	select {
+	case <-t.ponderHit:
+		t.pondering, t.start = false, t.clock.Now()
+	default:
+	}
+}
+
+func (t *timeManager) elapsed() time.Duration {
+	return t.clock.Now().Sub(t.start)
+}
+
+// Whether the search must stop at once
+func (t *timeManager) outOfTime() bool {
+	t.checkPonderHit()
+
+	return !t.pondering && t.hard > 0 && t.elapsed() >= t.hard
+}
+
+// Notes an iteration's best move and score, which decide how far the soft
+// limit is stretched
+func (t *timeManager) iterationDone(move pawn.Move, score int) {
+	t.iterations++
+
+	if t.iterations > 1 {
+		t.instability /= 2
+		if move != t.lastMove {
+			t.instability++
+		}
+
+		t.failingLow = t.lastScore-score >= failLowMargin
+	}
+
+	t.lastMove, t.lastScore = move, score
+}
+
+// The soft limit stretched by half again for each recent best move change
+// and half again if the score is falling, but no further than the hard limit
+func (t *timeManager) softLimit() time.Duration {
+	if !t.stretch {
+		return t.soft
+	}
+
+	scale := 1 + t.instability/2
+	if t.failingLow {
+		scale *= 1.5
+	}
+
+	limit := time.Duration(float64(t.soft) * scale)
+	if limit > t.hard {
+		limit = t.hard
+	}
+
+	return limit
+}
+
+// Whether there's time for another iteration
+func (t *timeManager) nextIteration() bool {
+	t.checkPonderHit()
+
+	return t.pondering || t.soft == 0 || t.elapsed() < t.softLimit()
+}
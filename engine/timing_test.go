@@ -0,0 +1,168 @@
+package engine
+
+import (
+	"testing"
+	"time"
+
+	"github.com/marcel/pawn"
+	"github.com/stretchr/testify/suite"
+)
+
+// A clock that moves on only when told to, or by tick every time it's read
+type testClock struct {
+	now  time.Time
+	tick time.Duration
+}
+
+func (c *testClock) Now() time.Time {
+	now := c.now
+	c.now = c.now.Add(c.tick)
+
+	return now
+}
+
+func (c *testClock) advance(duration time.Duration) {
+	c.now = c.now.Add(duration)
+}
+
+type TimingTestSuite struct {
+	suite.Suite
+	clock *testClock
+}
+
+func TestTimingTestSuite(t *testing.T) {
+	suite.Run(t, new(TimingTestSuite))
+}
+
+func (s *TimingTestSuite) SetupTest() {
+	s.clock = &testClock{now: time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)}
+}
+
+func (s *TimingTestSuite) TestBudget() {
+	soft, hard := budget(60*time.Second, time.Second, 0)
+	s.Equal(2*time.Second+750*time.Millisecond, soft)
+	s.Equal(11*time.Second, hard)
+
+	soft, hard = budget(50*time.Second, 0, 5)
+	s.Equal(10*time.Second, soft)
+	s.Equal(40*time.Second, hard)
+
+	soft, hard = budget(time.Second, 0, 1)
+	s.Equal(950*time.Millisecond, soft, "never more than is left")
+	s.Equal(950*time.Millisecond, hard)
+
+	soft, _ = budget(20*time.Millisecond, 2*time.Second, 0)
+	s.Equal(time.Millisecond, soft)
+}
+
+func (s *TimingTestSuite) TestMoveTime() {
+	timer := newTimeManager(s.clock, Limits{MoveTime: time.Second, Time: time.Minute})
+
+	// A fixed move time isn't stretched
+	timer.iterationDone(pawn.Move{From: pawn.E2, To: pawn.E4}, 50)
+	timer.iterationDone(pawn.Move{From: pawn.D2, To: pawn.D4}, -50)
+
+	s.clock.advance(999 * time.Millisecond)
+	s.True(timer.nextIteration())
+	s.False(timer.outOfTime())
+
+	s.clock.advance(time.Millisecond)
+	s.False(timer.nextIteration())
+	s.True(timer.outOfTime())
+}
+
+func (s *TimingTestSuite) TestNoLimit() {
+	timer := newTimeManager(s.clock, Limits{Depth: 5})
+
+	s.clock.advance(time.Hour)
+	s.True(timer.nextIteration())
+	s.False(timer.outOfTime())
+}
+
+func (s *TimingTestSuite) TestStretching() {
+	e4, d4 := pawn.Move{From: pawn.E2, To: pawn.E4}, pawn.Move{From: pawn.D2, To: pawn.D4}
+
+	// 2s of soft limit and 8s of hard
+	timer := newTimeManager(s.clock, Limits{Time: time.Minute})
+	s.Equal(2*time.Second, timer.softLimit())
+
+	timer.iterationDone(e4, 20)
+	timer.iterationDone(e4, 25)
+	s.Equal(2*time.Second, timer.softLimit(), "settled")
+
+	timer.iterationDone(d4, 30)
+	s.Equal(3*time.Second, timer.softLimit(), "the best move changed")
+
+	timer.iterationDone(d4, 30)
+	s.Equal(2500*time.Millisecond, timer.softLimit(), "settling again")
+
+	timer.iterationDone(d4, -10)
+	s.Equal(3375*time.Millisecond, timer.softLimit(), "failing low")
+
+	timer.iterationDone(e4, -10)
+	timer.iterationDone(d4, -100)
+	s.Equal(5343750*time.Microsecond, timer.softLimit())
+
+	s.clock.advance(5 * time.Second)
+	s.True(timer.nextIteration())
+
+	s.clock.advance(time.Second)
+	s.False(timer.nextIteration())
+	s.False(timer.outOfTime())
+
+	s.clock.advance(2 * time.Second)
+	s.True(timer.outOfTime())
+
+	// 5s of soft limit, but only 9.95s left
+	timer = newTimeManager(s.clock, Limits{Time: 10 * time.Second, MovesToGo: 2})
+	timer.iterationDone(e4, 0)
+	timer.iterationDone(d4, -50)
+	s.Equal(9950*time.Millisecond, timer.softLimit(), "no further than the hard limit")
+}
+
+func (s *TimingTestSuite) TestPonder() {
+	ponderHit := make(chan struct{})
+	timer := newTimeManager(s.clock, Limits{Time: time.Minute, PonderHit: ponderHit})
+
+	s.clock.advance(time.Minute)
+	s.True(timer.nextIteration())
+	s.False(timer.outOfTime())
+
+	close(ponderHit)
+	s.True(timer.nextIteration(), "the time counts from the ponder hit")
+
+	s.clock.advance(2 * time.Second)
+	s.False(timer.nextIteration())
+	s.False(timer.outOfTime())
+
+	s.clock.advance(6 * time.Second)
+	s.True(timer.outOfTime())
+}
+
+func (s *TimingTestSuite) TestSearch() {
+	board := pawn.NewBoard()
+	engine := New()
+	engine.Clock = s.clock
+
+	// Every look at the clock takes a second, so the soft limit has passed
+	// once the first iteration is done
+	s.clock.tick = time.Second
+	result := engine.Search(board, Limits{Time: 30 * time.Second, Depth: 4})
+	s.Equal(1, result.Depth)
+
+	// Pondering searches on regardless
+	result = engine.Search(board, Limits{Time: 30 * time.Second, Depth: 4, PonderHit: make(chan struct{})})
+	s.Equal(4, result.Depth)
+
+	// The hard limit stops the search mid-iteration
+	engine.Table.Clear()
+	s.clock.tick = 0
+	engine.Progress = func(result Result) {
+		if result.Depth == 3 {
+			s.clock.tick = 5 * time.Second
+		}
+	}
+	result = engine.Search(board, Limits{MoveTime: 5 * time.Second})
+	s.Equal(3, result.Depth)
+	s.NotEqual(pawn.Move{}, result.Move)
+}
@@ -0,0 +1,254 @@
+package engine
+
+import (
+	"sync/atomic"
+
+	"github.com/marcel/pawn"
+)
+
+const DefaultHashMegabytes = 16
+
+// What a stored score says about a position's true score
+type bound uint8
+
+const (
+	upperBound bound = 1 << iota // At most the score: no move reached alpha
+	lowerBound                   // At least the score: a move reached beta
+	exactBound = upperBound | lowerBound
+)
+
+// A table entry is a position's data packed into a word: the best move in
+// bits 0-15, score in 16-31, depth in 32-39, bound in 40-41 and the age of the
+// search that stored it in 42-49. An entry that was never stored is all
+// zeros.
+//
+// Searches running together share the table without locks. The key is kept
+// xored with the data, so an entry half written by one while another reads
+// it doesn't match the position and is taken for a miss.
+type tableEntry struct {
+	key  uint64 // The position's hash xor data
+	data uint64
+}
+
+func (e *tableEntry) load() (uint64, uint64) {
+	data := atomic.LoadUint64(&e.data)
+	return atomic.LoadUint64(&e.key) ^ data, data
+}
+
+func (e *tableEntry) save(hash, data uint64) {
+	atomic.StoreUint64(&e.key, hash^data)
+	atomic.StoreUint64(&e.data, data)
+}
+
+type tableData struct {
+	move  pawn.EncodedMove
+	score int
+	depth int
+	bound bound
+	age   uint8
+}
+
+func (d tableData) pack() uint64 {
+	return uint64(d.move) | uint64(uint16(int16(d.score)))<<16 | uint64(uint8(d.depth))<<32 |
+		uint64(d.bound)<<40 | uint64(d.age)<<42
+}
+
+func unpack(data uint64) tableData {
+	return tableData{
+		move:  pawn.EncodedMove(data),
+		score: int(int16(uint16(data >> 16))),
+		depth: int(uint8(data >> 32)),
+		bound: bound(data>>40) & exactBound,
+		age:   uint8(data >> 42),
+	}
+}
+
+// Entries come in clusters of two a hash picks between: the first keeps
+// whichever of the current search's positions was searched deepest and the
+// second is always replaced
+const clusterSize = 2
+
+// Positions searched before, by Zobrist hash, with the score and best move
+// found. Its size is fixed when it's made; once it's full new positions
+// replace old ones, preferring to keep the results of deeper searches. Any
+// number of goroutines may search with it at once, but it mustn't be cleared
+// while they do.
+type TranspositionTable struct {
+	entries []tableEntry
+	mask    uint64 // Picks a cluster from a hash
+	age     uint8
+
+	stats TableStats
+}
+
+// How well the table has served since it was made or last cleared
+type TableStats struct {
+	Probes   uint64
+	Hits     uint64 // Probes finding the position
+	Stores   uint64
+	Replaced uint64 // Stores overwriting a different position
+}
+
+// Hits as a fraction of probes
+func (s TableStats) HitRate() float64 {
+	if s.Probes == 0 {
+		return 0
+	}
+
+	return float64(s.Hits) / float64(s.Probes)
+}
+
+// Makes a table taking up to megabytes of memory, rounded down to a power of
+// two entries
+func NewTranspositionTable(megabytes int) *TranspositionTable {
+	if megabytes < 1 {
+		megabytes = 1
+	}
+
+	const entryBytes = 16
+	clusters := uint64(1)
+	for clusters*2*clusterSize*entryBytes <= uint64(megabytes)<<20 {
+		clusters *= 2
+	}
+
+	return &TranspositionTable{entries: make([]tableEntry, clusters*clusterSize), mask: clusters - 1}
+}
+
+// Forgets every position, as before a new game
+func (t *TranspositionTable) Clear() {
+	for i := range t.entries {
+		t.entries[i] = tableEntry{}
+	}
+	t.age, t.stats = 0, TableStats{}
+}
+
+func (t *TranspositionTable) Stats() TableStats {
+	return TableStats{
+		Probes:   atomic.LoadUint64(&t.stats.Probes),
+		Hits:     atomic.LoadUint64(&t.stats.Hits),
+		Stores:   atomic.LoadUint64(&t.stats.Stores),
+		Replaced: atomic.LoadUint64(&t.stats.Replaced),
+	}
+}
+
+// Adds a search's stats, which it counts apart so goroutines searching
+// together don't contend for them
+func (t *TranspositionTable) record(stats TableStats) {
+	atomic.AddUint64(&t.stats.Probes, stats.Probes)
+	atomic.AddUint64(&t.stats.Hits, stats.Hits)
+	atomic.AddUint64(&t.stats.Stores, stats.Stores)
+	atomic.AddUint64(&t.stats.Replaced, stats.Replaced)
+}
+
+// Per mille of the table in use by the current search, from a sample of its
+// first thousand entries, as UCI's hashfull reports it
+func (t *TranspositionTable) Hashfull() int {
+	sample := len(t.entries)
+	if sample > 1000 {
+		sample = 1000
+	}
+
+	used := 0
+	for i := range t.entries[:sample] {
+		if _, data := t.entries[i].load(); data != 0 && unpack(data).age == t.age {
+			used++
+		}
+	}
+
+	return used * 1000 / sample
+}
+
+// Marks the start of a search, so entries from earlier ones give way first
+func (t *TranspositionTable) newSearch() {
+	t.age++
+}
+
+func (t *TranspositionTable) cluster(hash uint64) []tableEntry {
+	start := (hash & t.mask) * clusterSize
+	return t.entries[start : start+clusterSize]
+}
+
+// Looks a position up for the search, counting the probe in stats
+func (t *TranspositionTable) probe(hash uint64, stats *TableStats) (tableData, bool) {
+	stats.Probes++
+
+	data, found := t.lookup(hash)
+	if found {
+		stats.Hits++
+	}
+
+	return data, found
+}
+
+func (t *TranspositionTable) lookup(hash uint64) (tableData, bool) {
+	cluster := t.cluster(hash)
+	for i := range cluster {
+		if key, data := cluster[i].load(); key == hash && data != 0 {
+			return unpack(data), true
+		}
+	}
+
+	return tableData{}, false
+}
+
+func (t *TranspositionTable) store(hash uint64, data tableData, stats *TableStats) {
+	stats.Stores++
+	data.age = t.age
+	cluster := t.cluster(hash)
+
+	var keys, packed [clusterSize]uint64
+	for i := range cluster {
+		keys[i], packed[i] = cluster[i].load()
+	}
+
+	slot := clusterSize - 1
+	for i := range cluster {
+		if keys[i] == hash && packed[i] != 0 {
+			slot = i
+
+			// Keep the move of an earlier search of the position if this one
+			// didn't find one
+			if data.move == 0 {
+				data.move = unpack(packed[i]).move
+			}
+			break
+		}
+	}
+
+	if slot == clusterSize-1 && keys[slot] != hash {
+		first := unpack(packed[0])
+		if packed[0] == 0 || first.age != t.age || data.depth >= first.depth {
+			slot = 0
+		}
+	}
+
+	if packed[slot] != 0 && keys[slot] != hash {
+		stats.Replaced++
+	}
+
+	cluster[slot].save(hash, data.pack())
+}
+
+// Mate scores are stored as distances from the position rather than from
+// the root, so they stay right when the position is reached at another ply
+func scoreToTable(score, ply int) int {
+	switch {
+	case score > Mate-maxPly:
+		return score + ply
+	case score < -Mate+maxPly:
+		return score - ply
+	}
+
+	return score
+}
+
+func scoreFromTable(score, ply int) int {
+	switch {
+	case score > Mate-maxPly:
+		return score - ply
+	case score < -Mate+maxPly:
+		return score + ply
+	}
+
+	return score
+}
@@ -0,0 +1,117 @@
+package engine
+
+import (
+	"testing"
+
+	"github.com/marcel/pawn"
+	"github.com/stretchr/testify/suite"
+)
+
+type TranspositionTestSuite struct {
+	suite.Suite
+	table *TranspositionTable
+	stats TableStats
+}
+
+func TestTranspositionTestSuite(t *testing.T) {
+	suite.Run(t, new(TranspositionTestSuite))
+}
+
+func (s *TranspositionTestSuite) SetupTest() {
+	s.table = NewTranspositionTable(1)
+	s.stats = TableStats{}
+}
+
+func (s *TranspositionTestSuite) TestSize() {
+	s.Len(s.table.entries, 1<<16)
+	s.Len(NewTranspositionTable(3).entries, 1<<17, "rounded down to a power of two")
+}
+
+func (s *TranspositionTestSuite) TestPacking() {
+	for _, data := range []tableData{
+		{move: pawn.EncodedMove(0xc123), score: -Mate + 3, depth: 12, bound: upperBound, age: 200},
+		{score: Mate, depth: 255, bound: exactBound},
+		{move: 1, score: 0, depth: 1, bound: lowerBound, age: 1},
+	} {
+		s.Equal(data, unpack(data.pack()))
+	}
+}
+
+func (s *TranspositionTestSuite) TestStoreAndProbe() {
+	s.table.newSearch()
+	s.table.store(42, tableData{move: 7, score: 15, depth: 3, bound: exactBound}, &s.stats)
+
+	data, found := s.table.probe(42, &s.stats)
+	s.True(found)
+	s.Equal(tableData{move: 7, score: 15, depth: 3, bound: exactBound, age: 1}, data)
+
+	_, found = s.table.probe(43, &s.stats)
+	s.False(found)
+
+	// A result without a best move keeps the last one found
+	s.table.store(42, tableData{score: -20, depth: 4, bound: upperBound}, &s.stats)
+	data, _ = s.table.probe(42, &s.stats)
+	s.Equal(pawn.EncodedMove(7), data.move)
+	s.Equal(-20, data.score)
+
+	s.Equal(TableStats{Probes: 3, Hits: 2, Stores: 2}, s.stats)
+	s.InDelta(2.0/3, s.stats.HitRate(), 0.001)
+
+	s.table.record(s.stats)
+	s.table.record(s.stats)
+	s.Equal(TableStats{Probes: 6, Hits: 4, Stores: 4}, s.table.Stats())
+
+	s.table.Clear()
+	s.Equal(TableStats{}, s.table.Stats())
+	_, found = s.table.probe(42, &s.stats)
+	s.False(found)
+}
+
+func (s *TranspositionTestSuite) TestReplacement() {
+	clusters := s.table.mask + 1
+	deep, shallow, other := uint64(5), 5+clusters, 5+2*clusters
+
+	s.table.newSearch()
+	s.table.store(deep, tableData{depth: 8, bound: exactBound}, &s.stats)
+	s.table.store(shallow, tableData{depth: 2, bound: exactBound}, &s.stats)
+	s.table.store(other, tableData{depth: 3, bound: exactBound}, &s.stats)
+
+	_, found := s.table.probe(deep, &s.stats)
+	s.True(found, "the deepest is kept")
+	_, found = s.table.probe(shallow, &s.stats)
+	s.False(found, "the second entry is always replaced")
+	_, found = s.table.probe(other, &s.stats)
+	s.True(found)
+	s.Equal(uint64(1), s.stats.Replaced)
+
+	// Entries from an earlier search make way however deep
+	s.table.newSearch()
+	s.table.store(shallow, tableData{depth: 1, bound: exactBound}, &s.stats)
+	_, found = s.table.probe(deep, &s.stats)
+	s.False(found)
+}
+
+func (s *TranspositionTestSuite) TestMateScores() {
+	// Mate in 3 plies from a position 5 plies into the search
+	stored := scoreToTable(Mate-8, 5)
+	s.Equal(Mate-3, stored)
+	s.Equal(Mate-10, scoreFromTable(stored, 7), "reached 7 plies in, the mate is 10 from the root")
+	s.Equal(-Mate+4, scoreFromTable(scoreToTable(-Mate+4, 2), 2))
+	s.Equal(150, scoreFromTable(scoreToTable(150, 9), 3))
+}
+
+func (s *TranspositionTestSuite) TestSearch() {
+	engine := New()
+	engine.Table = s.table
+	board, _ := pawn.ParseFEN("r1bqkb1r/pppp1ppp/2n2n2/4p3/2B1P3/5N2/PPPP1PPP/RNBQK2R w KQkq - 4 4")
+
+	first := engine.Search(board, Limits{Depth: 4})
+	s.True(s.table.Stats().Hits > 0, "transpositions are found within a search")
+	s.True(s.table.Hashfull() > 0)
+
+	second := engine.Search(board, Limits{Depth: 4})
+	s.Equal(first.Move, second.Move)
+	s.Equal(first.Score, second.Score)
+	s.True(second.Nodes < first.Nodes, "the second search starts from the first's results")
+	s.Len(second.PV, 4)
+}
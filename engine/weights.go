@@ -0,0 +1,151 @@
+package engine
+
+import "github.com/marcel/pawn"
+
+// Hand set weights, the piece-square tables after Tomasz Michniewski's
+// Simplified Evaluation Function with endgame tables added for the pawns,
+// rooks and king
+var DefaultWeights = Weights{
+	Material: [pawn.King + 1]Phased{
+		pawn.Pawn:   {100, 120},
+		pawn.Knight: {320, 300},
+		pawn.Bishop: {330, 320},
+		pawn.Rook:   {500, 540},
+		pawn.Queen:  {900, 950},
+	},
+
+	PieceSquares: [pawn.King + 1][64]Phased{
+		pawn.Pawn:   phasedTable(pawnMiddlegame, pawnEndgame),
+		pawn.Knight: phasedTable(knightTable, knightTable),
+		pawn.Bishop: phasedTable(bishopTable, bishopTable),
+		pawn.Rook:   phasedTable(rookMiddlegame, rookEndgame),
+		pawn.Queen:  phasedTable(queenTable, queenTable),
+		pawn.King:   phasedTable(kingMiddlegame, kingEndgame),
+	},
+
+	Mobility: [pawn.King + 1]Phased{
+		pawn.Knight: {4, 4},
+		pawn.Bishop: {5, 5},
+		pawn.Rook:   {2, 4},
+		pawn.Queen:  {1, 2},
+	},
+
+	DoubledPawn:  Phased{-10, -20},
+	IsolatedPawn: Phased{-10, -15},
+	PassedPawn:   [8]Phased{{0, 0}, {5, 10}, {10, 15}, {15, 25}, {25, 45}, {40, 75}, {60, 120}, {0, 0}},
+
+	BishopPair:       Phased{30, 50},
+	RookOpenFile:     Phased{25, 10},
+	RookSemiOpenFile: Phased{10, 5},
+
+	KingShield: Phased{10, 0},
+	KingAttack: Phased{-8, 0},
+}
+
+func phasedTable(middlegame, endgame [64]int) [64]Phased {
+	table := [64]Phased{}
+	for square := range table {
+		table[square] = Phased{middlegame[square], endgame[square]}
+	}
+
+	return table
+}
+
+var pawnMiddlegame = [64]int{
+	0, 0, 0, 0, 0, 0, 0, 0,
+	50, 50, 50, 50, 50, 50, 50, 50,
+	10, 10, 20, 30, 30, 20, 10, 10,
+	5, 5, 10, 25, 25, 10, 5, 5,
+	0, 0, 0, 20, 20, 0, 0, 0,
+	5, -5, -10, 0, 0, -10, -5, 5,
+	5, 10, 10, -20, -20, 10, 10, 5,
+	0, 0, 0, 0, 0, 0, 0, 0,
+}
+
+var pawnEndgame = [64]int{
+	0, 0, 0, 0, 0, 0, 0, 0,
+	80, 80, 80, 80, 80, 80, 80, 80,
+	50, 50, 50, 50, 50, 50, 50, 50,
+	30, 30, 30, 30, 30, 30, 30, 30,
+	20, 20, 20, 20, 20, 20, 20, 20,
+	10, 10, 10, 10, 10, 10, 10, 10,
+	0, 0, 0, 0, 0, 0, 0, 0,
+	0, 0, 0, 0, 0, 0, 0, 0,
+}
+
+var knightTable = [64]int{
+	-50, -40, -30, -30, -30, -30, -40, -50,
+	-40, -20, 0, 0, 0, 0, -20, -40,
+	-30, 0, 10, 15, 15, 10, 0, -30,
+	-30, 5, 15, 20, 20, 15, 5, -30,
+	-30, 0, 15, 20, 20, 15, 0, -30,
+	-30, 5, 10, 15, 15, 10, 5, -30,
+	-40, -20, 0, 5, 5, 0, -20, -40,
+	-50, -40, -30, -30, -30, -30, -40, -50,
+}
+
+var bishopTable = [64]int{
+	-20, -10, -10, -10, -10, -10, -10, -20,
+	-10, 0, 0, 0, 0, 0, 0, -10,
+	-10, 0, 5, 10, 10, 5, 0, -10,
+	-10, 5, 5, 10, 10, 5, 5, -10,
+	-10, 0, 10, 10, 10, 10, 0, -10,
+	-10, 10, 10, 10, 10, 10, 10, -10,
+	-10, 5, 0, 0, 0, 0, 5, -10,
+	-20, -10, -10, -10, -10, -10, -10, -20,
+}
+
+var rookMiddlegame = [64]int{
+	0, 0, 0, 0, 0, 0, 0, 0,
+	5, 10, 10, 10, 10, 10, 10, 5,
+	-5, 0, 0, 0, 0, 0, 0, -5,
+	-5, 0, 0, 0, 0, 0, 0, -5,
+	-5, 0, 0, 0, 0, 0, 0, -5,
+	-5, 0, 0, 0, 0, 0, 0, -5,
+	-5, 0, 0, 0, 0, 0, 0, -5,
+	0, 0, 0, 5, 5, 0, 0, 0,
+}
+
+var rookEndgame = [64]int{
+	0, 0, 0, 0, 0, 0, 0, 0,
+	10, 10, 10, 10, 10, 10, 10, 10,
+	0, 0, 0, 0, 0, 0, 0, 0,
+	0, 0, 0, 0, 0, 0, 0, 0,
+	0, 0, 0, 0, 0, 0, 0, 0,
+	0, 0, 0, 0, 0, 0, 0, 0,
+	0, 0, 0, 0, 0, 0, 0, 0,
+	0, 0, 0, 0, 0, 0, 0, 0,
+}
+
+var queenTable = [64]int{
+	-20, -10, -10, -5, -5, -10, -10, -20,
+	-10, 0, 0, 0, 0, 0, 0, -10,
+	-10, 0, 5, 5, 5, 5, 0, -10,
+	-5, 0, 5, 5, 5, 5, 0, -5,
+	0, 0, 5, 5, 5, 5, 0, -5,
+	-10, 5, 5, 5, 5, 5, 0, -10,
+	-10, 0, 5, 0, 0, 0, 0, -10,
+	-20, -10, -10, -5, -5, -10, -10, -20,
+}
+
+var kingMiddlegame = [64]int{
+	-30, -40, -40, -50, -50, -40, -40, -30,
+	-30, -40, -40, -50, -50, -40, -40, -30,
+	-30, -40, -40, -50, -50, -40, -40, -30,
+	-30, -40, -40, -50, -50, -40, -40, -30,
+	-20, -30, -30, -40, -40, -30, -30, -20,
+	-10, -20, -20, -20, -20, -20, -20, -10,
+	20, 20, 0, 0, 0, 0, 20, 20,
+	20, 30, 10, 0, 0, 10, 30, 20,
+}
+
+var kingEndgame = [64]int{
+	-50, -40, -30, -20, -20, -30, -40, -50,
+	-30, -20, -10, 0, 0, -10, -20, -30,
+	-30, -10, 20, 30, 30, 20, -10, -30,
+	-30, -10, 30, 40, 40, 30, -10, -30,
+	-30, -10, 30, 40, 40, 30, -10, -30,
+	-30, -10, 20, 30, 30, 20, -10, -30,
+	-30, -30, 0, 0, 0, 0, -30, -30,
+	-50, -30, -30, -30, -30, -30, -30, -50,
+}
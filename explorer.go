@@ -0,0 +1,170 @@
+package pawn
+
+import (
+	"sort"
+	"strconv"
+)
+
+// How often a move was played from a position and how those games ended
+type MoveStats struct {
+	Move
+	SAN       AlgebraicNotation // Filled in by Explorer.Moves for the position queried
+	Games     int
+	WhiteWins int
+	Draws     int
+	BlackWins int
+	eloTotal  int
+	eloGames  int // Games where the player making the move had a rating
+}
+
+// Average rating of the players who made the move, or zero if none were rated
+func (m MoveStats) AverageElo() int {
+	if m.eloGames == 0 {
+		return 0
+	}
+
+	return m.eloTotal / m.eloGames
+}
+
+// The percentage of decided and drawn games won by White, drawn and won by
+// Black. Games without a result are left out.
+func (m MoveStats) Percentages() (white, draw, black float64) {
+	finished := m.WhiteWins + m.Draws + m.BlackWins
+	if finished == 0 {
+		return 0, 0, 0
+	}
+
+	percentage := func(games int) float64 {
+		return 100 * float64(games) / float64(finished)
+	}
+
+	return percentage(m.WhiteWins), percentage(m.Draws), percentage(m.BlackWins)
+}
+
+// White's score in finished games from 0 to 1, counting draws as half
+func (m MoveStats) Score() float64 {
+	finished := m.WhiteWins + m.Draws + m.BlackWins
+	if finished == 0 {
+		return 0
+	}
+
+	return (float64(m.WhiteWins) + float64(m.Draws)/2) / float64(finished)
+}
+
+// An opening tree built from a collection of games. Moves are stored by the
+// position they were played from so transpositions share statistics.
+type Explorer struct {
+	MaxPlies  int // Moves after this many plies aren't added
+	Games     int
+	positions map[uint64]map[Move]*MoveStats
+}
+
+const DefaultExplorerPlies = 30
+
+func NewExplorer(maxPlies int) *Explorer {
+	return &Explorer{MaxPlies: maxPlies, positions: map[uint64]map[Move]*MoveStats{}}
+}
+
+// Builds an Explorer from every game in pgns, skipping the moves of a game
+// from its first illegal one
+func ExplorerFromPGNs(pgns []PGN) *Explorer {
+	explorer := NewExplorer(DefaultExplorerPlies)
+
+	for _, pgn := range pgns {
+		explorer.Add(pgn)
+	}
+
+	return explorer
+}
+
+// Adds the opening moves of a game to the tree. Moves up to the first
+// illegal one are kept and its error returned.
+func (e *Explorer) Add(pgn PGN) error {
+	board := NewBoard()
+	whiteElo, whiteRated := rating(pgn.Tags["WhiteElo"])
+	blackElo, blackRated := rating(pgn.Tags["BlackElo"])
+	ply := 0
+
+	e.Games++
+
+	for _, an := range pgn.Turns() {
+		if ply >= e.MaxPlies {
+			break
+		}
+
+		if an == "" {
+			continue
+		}
+
+		move, err := board.ResolveAlgebraic(an)
+		if err != nil {
+			return err
+		}
+
+		moves, seen := e.positions[board.Hash()]
+		if !seen {
+			moves = map[Move]*MoveStats{}
+			e.positions[board.Hash()] = moves
+		}
+
+		stats, played := moves[move]
+		if !played {
+			stats = &MoveStats{Move: move}
+			moves[move] = stats
+		}
+
+		stats.Games++
+		switch pgn.Outcome {
+		case WhiteWin:
+			stats.WhiteWins++
+		case BlackWin:
+			stats.BlackWins++
+		case Draw:
+			stats.Draws++
+		}
+
+		elo, rated := whiteElo, whiteRated
+		if board.SideToMove() == Black {
+			elo, rated = blackElo, blackRated
+		}
+		stats.addElo(elo, rated)
+
+		board.MakeMove(move)
+		ply++
+	}
+
+	return nil
+}
+
+func (m *MoveStats) addElo(elo int, rated bool) {
+	if rated {
+		m.eloTotal += elo
+		m.eloGames++
+	}
+}
+
+func rating(tag string) (int, bool) {
+	elo, err := strconv.Atoi(tag)
+	return elo, err == nil && elo > 0
+}
+
+// The moves played from the board's position, most played first
+func (e *Explorer) Moves(board *Board) []MoveStats {
+	moves := []MoveStats{}
+
+	for _, stats := range e.positions[board.Hash()] {
+		move := *stats
+		move.SAN = board.Algebraic(move.Move)
+		moves = append(moves, move)
+	}
+
+	sort.Slice(moves, func(i, j int) bool {
+		if moves[i].Games != moves[j].Games {
+			return moves[i].Games > moves[j].Games
+		}
+
+		return moves[i].SAN < moves[j].SAN
+	})
+
+	return moves
+}
@@ -0,0 +1,79 @@
+package pawn
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExplorer(t *testing.T) {
+	pgns := NewPGNParserFromReader(strings.NewReader(win + draw + finalMoveByWhite + multipleEntries)).ParseAll()
+	explorer := ExplorerFromPGNs(pgns)
+
+	assert.Equal(t, 5, explorer.Games)
+
+	board := NewBoard()
+	moves := explorer.Moves(board)
+	assert.Equal(t, 2, len(moves))
+
+	assert.Equal(t, AlgebraicNotation("e4"), moves[0].SAN)
+	assert.Equal(t, 3, moves[0].Games)
+	assert.Equal(t, (2870+2693+2840)/3, moves[0].AverageElo())
+	white, draw, black := moves[0].Percentages()
+	assert.InDelta(t, 100.0/3, white, 0.001)
+	assert.InDelta(t, 200.0/3, draw, 0.001)
+	assert.Equal(t, 0.0, black)
+
+	assert.Equal(t, AlgebraicNotation("d4"), moves[1].SAN)
+	assert.Equal(t, 2, moves[1].Games)
+	assert.Equal(t, 1, moves[1].WhiteWins)
+	assert.Equal(t, 1, moves[1].BlackWins)
+	assert.Equal(t, (2775+2840)/2, moves[1].AverageElo())
+	assert.Equal(t, 0.5, moves[1].Score())
+
+	board.MoveFromAlgebraic("e4")
+	moves = explorer.Moves(board)
+	assert.Equal(t, []AlgebraicNotation{"c5", "d6", "e5"}, sans(moves))
+	assert.Equal(t, 2775, moves[0].AverageElo())
+}
+
+func TestExplorerTranspositions(t *testing.T) {
+	explorer := NewExplorer(DefaultExplorerPlies)
+	explorer.Add(ParsePGN("[Result \"1-0\"]\n\n1.e4 e6 2.d4 d5 1-0"))
+	explorer.Add(ParsePGN("[Result \"0-1\"]\n\n1.d4 e6 2.e4 c5 0-1"))
+
+	board := NewBoard()
+	for _, an := range []AlgebraicNotation{"e4", "e6", "d4"} {
+		board.MoveFromAlgebraic(an)
+	}
+
+	assert.Equal(t, []AlgebraicNotation{"c5", "d5"}, sans(explorer.Moves(board)))
+}
+
+func TestExplorerLimits(t *testing.T) {
+	explorer := NewExplorer(2)
+	assert.Nil(t, explorer.Add(ParsePGN(win)))
+
+	board := NewBoard()
+	board.MoveFromAlgebraic("d4")
+	board.MoveFromAlgebraic("Nf6")
+	assert.Empty(t, explorer.Moves(board))
+
+	explorer = NewExplorer(DefaultExplorerPlies)
+	assert.Equal(t, ErrorIllegalMove, explorer.Add(ParsePGN(movetextWithRAV)))
+	assert.Equal(t, 0, explorer.Moves(NewBoard())[0].AverageElo()-2693)
+
+	var unplayed MoveStats
+	assert.Equal(t, 0.0, unplayed.Score())
+	assert.Equal(t, 0, unplayed.AverageElo())
+}
+
+func sans(moves []MoveStats) []AlgebraicNotation {
+	sans := []AlgebraicNotation{}
+	for _, move := range moves {
+		sans = append(sans, move.SAN)
+	}
+
+	return sans
+}
@@ -0,0 +1,232 @@
+package pawn
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+var ErrorInvalidFEN = errors.New("pawn: invalid FEN")
+
+const InitialFEN = "rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR w KQkq - 0 1"
+
+// Forsyth-Edwards Notation letters for each piece, upper case for White
+var fenLetters = map[Piece]byte{
+	{White, Pawn}: 'P', {White, Knight}: 'N', {White, Bishop}: 'B',
+	{White, Rook}: 'R', {White, Queen}: 'Q', {White, King}: 'K',
+	{Black, Pawn}: 'p', {Black, Knight}: 'n', {Black, Bishop}: 'b',
+	{Black, Rook}: 'r', {Black, Queen}: 'q', {Black, King}: 'k',
+}
+
+var fenCastling = [...]struct {
+	letter byte
+	rights castlingRights
+	king   Position
+	rook   Position
+}{
+	{'K', whiteKingSide, E1, H1},
+	{'Q', whiteQueenSide, E1, A1},
+	{'k', blackKingSide, E8, H8},
+	{'q', blackQueenSide, E8, A8},
+}
+
+// Sets up a Board from a position in Forsyth-Edwards Notation. The halfmove
+// clock and move number may be left off. Castling rights are dropped when
+// the king or rook has left its square so equal positions hash the same.
+func ParseFEN(fen string) (*Board, error) {
+	fields := strings.Fields(fen)
+	if len(fields) < 4 || len(fields) > 6 {
+		return nil, fmt.Errorf("%w: expected 4 to 6 fields in \"%s\"", ErrorInvalidFEN, fen)
+	}
+
+	board := &Board{Squares: make([]*Square, len(allPositions)), moveMutex: &sync.Mutex{}}
+	for index, position := range allPositions {
+		board.Squares[index] = &Square{Position: position}
+	}
+
+	if err := board.placePieces(fields[0]); err != nil {
+		return nil, err
+	}
+
+	black := false
+	switch fields[1] {
+	case "w":
+	case "b":
+		black = true
+	default:
+		return nil, fmt.Errorf("%w: side to move \"%s\"", ErrorInvalidFEN, fields[1])
+	}
+
+	if fields[2] != "-" {
+		for _, letter := range []byte(fields[2]) {
+			found := false
+			for _, castle := range fenCastling {
+				if castle.letter != letter {
+					continue
+				}
+
+				found = true
+				king := board.SquareAtPosition(castle.king).Piece
+				rook := board.SquareAtPosition(castle.rook).Piece
+				if king.Material == King && rook == (Piece{king.Color, Rook}) {
+					board.castling |= castle.rights
+				}
+			}
+
+			if !found {
+				return nil, fmt.Errorf("%w: castling rights \"%s\"", ErrorInvalidFEN, fields[2])
+			}
+		}
+	}
+
+	if fields[3] != "-" {
+		position, err := positionFromAN(fields[3])
+		if err != nil || (black && position.Rank != 3) || (!black && position.Rank != 6) {
+			return nil, fmt.Errorf("%w: en passant square \"%s\"", ErrorInvalidFEN, fields[3])
+		}
+		board.enPassant = position
+	}
+
+	moveNumber := 1
+	for i, field := range fields[4:] {
+		number, err := strconv.Atoi(field)
+		if err != nil || number < 0 {
+			return nil, fmt.Errorf("%w: move counter \"%s\"", ErrorInvalidFEN, field)
+		}
+
+		if i == 0 {
+			board.halfmoveClock = number
+		} else if number > 0 {
+			moveNumber = number
+		}
+	}
+
+	board.turnNumber = 2 * (moveNumber - 1)
+	if black {
+		board.turnNumber++
+	}
+
+	board.hash = board.computeHash()
+
+	return board, nil
+}
+
+func (b *Board) placePieces(placement string) error {
+	rows := strings.Split(placement, "/")
+	if len(rows) != len(allRanks) {
+		return fmt.Errorf("%w: expected 8 ranks in \"%s\"", ErrorInvalidFEN, placement)
+	}
+
+	kings := map[Color]int{}
+
+	for row, letters := range rows {
+		rank := Rank(len(allRanks) - row)
+		file := 0
+
+		for _, letter := range []byte(letters) {
+			if letter >= '1' && letter <= '8' {
+				file += int(letter - '0')
+				continue
+			}
+
+			piece, found := pieceFromFENLetter(letter)
+			if !found || file >= len(allFiles) {
+				return fmt.Errorf("%w: rank %d \"%s\"", ErrorInvalidFEN, rank, letters)
+			}
+
+			if piece.Material == Pawn && (rank == 1 || rank == 8) {
+				return fmt.Errorf("%w: pawn on rank %d", ErrorInvalidFEN, rank)
+			}
+
+			if piece.Material == King {
+				kings[piece.Color]++
+			}
+
+			b.SquareAtPosition(Position{allFiles[file], rank}).Piece = piece
+			file++
+		}
+
+		if file != len(allFiles) {
+			return fmt.Errorf("%w: rank %d \"%s\" doesn't have 8 squares", ErrorInvalidFEN, rank, letters)
+		}
+	}
+
+	if kings[White] != 1 || kings[Black] != 1 {
+		return fmt.Errorf("%w: each side needs exactly one king", ErrorInvalidFEN)
+	}
+
+	return nil
+}
+
+func pieceFromFENLetter(letter byte) (Piece, bool) {
+	for piece, pieceLetter := range fenLetters {
+		if pieceLetter == letter {
+			return piece, true
+		}
+	}
+
+	return NoPiece, false
+}
+
+func positionFromAN(an string) (Position, error) {
+	if len(an) != 2 || an[0] < 'a' || an[0] > 'h' || an[1] < '1' || an[1] > '8' {
+		return Position{}, ErrorInvalidPosition
+	}
+
+	return Position{File(an[:1]), rankFromByte(an[1])}, nil
+}
+
+// The board's position in Forsyth-Edwards Notation
+func (b Board) FEN() string {
+	var fen strings.Builder
+
+	for row, squares := range b.Rows() {
+		if row > 0 {
+			fen.WriteByte('/')
+		}
+
+		empty := 0
+		for _, square := range squares {
+			if square.Piece == NoPiece {
+				empty++
+				continue
+			}
+
+			if empty > 0 {
+				fen.WriteString(strconv.Itoa(empty))
+				empty = 0
+			}
+			fen.WriteByte(fenLetters[square.Piece])
+		}
+
+		if empty > 0 {
+			fen.WriteString(strconv.Itoa(empty))
+		}
+	}
+
+	side := "w"
+	if b.SideToMove() == Black {
+		side = "b"
+	}
+
+	castling := ""
+	for _, castle := range fenCastling {
+		if b.castling&castle.rights != 0 {
+			castling += string(castle.letter)
+		}
+	}
+	if castling == "" {
+		castling = "-"
+	}
+
+	enPassant := "-"
+	if b.enPassant != (Position{}) {
+		enPassant = b.enPassant.AN()
+	}
+
+	fmt.Fprintf(&fen, " %s %s %s %d %d", side, castling, enPassant, b.halfmoveClock, b.turnNumber/2+1)
+
+	return fen.String()
+}
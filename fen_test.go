@@ -0,0 +1,60 @@
+package pawn
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFEN(t *testing.T) {
+	board := NewBoard()
+	assert.Equal(t, InitialFEN, board.FEN())
+
+	for _, an := range []AlgebraicNotation{"e4", "c5", "Nf3"} {
+		board.MoveFromAlgebraic(an)
+	}
+	assert.Equal(t, "rnbqkbnr/pp1ppppp/8/2p5/4P3/5N2/PPPP1PPP/RNBQKB1R b KQkq - 1 2", board.FEN())
+}
+
+func TestParseFEN(t *testing.T) {
+	board, err := ParseFEN(InitialFEN)
+	assert.Nil(t, err)
+	assert.Equal(t, NewBoard().Hash(), board.Hash())
+	assert.Equal(t, 20, len(board.LegalMoves()))
+
+	played := NewBoard()
+	for _, an := range []AlgebraicNotation{"e4", "d5", "e5", "f5"} {
+		played.MoveFromAlgebraic(an)
+	}
+
+	board, err = ParseFEN(played.FEN())
+	assert.Nil(t, err)
+	assert.Equal(t, played.Hash(), board.Hash())
+	assert.Equal(t, played.FEN(), board.FEN())
+
+	_, err = board.MoveFromAlgebraic("exf6")
+	assert.Nil(t, err, "en passant square is kept")
+
+	board, err = ParseFEN("4k3/8/8/8/8/8/8/R3K2R b KQkq -")
+	assert.Nil(t, err)
+	assert.Equal(t, Black, board.SideToMove())
+	assert.Equal(t, "4k3/8/8/8/8/8/8/R3K2R b KQ - 0 1", board.FEN(), "black has no rooks to castle with")
+}
+
+func TestParseFENErrors(t *testing.T) {
+	for _, fen := range []string{
+		"",
+		"rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP w KQkq - 0 1",
+		"rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR x KQkq - 0 1",
+		"rnbqkbnr/pppppppp/9/8/8/8/PPPPPPPP/RNBQKBNR w KQkq - 0 1",
+		"rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNX w KQkq - 0 1",
+		"rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQQBNR w KQkq - 0 1",
+		"rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR w KQkx - 0 1",
+		"rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR w KQkq e4 0 1",
+		"rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR w KQkq - x 1",
+	} {
+		_, err := ParseFEN(fen)
+		assert.True(t, errors.Is(err, ErrorInvalidFEN), fen)
+	}
+}
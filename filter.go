@@ -0,0 +1,155 @@
+package pawn
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Criteria a game must meet, as pgn-extract selects games. Zero valued
+// fields match every game. Names, events and the like match
+// case-insensitively anywhere in the tag.
+type GameFilter struct {
+	Player       string // Either player
+	White, Black string
+	Event        string
+	Result       Outcome
+
+	// Both players are rated within the range; unrated games don't match a
+	// range
+	MinElo, MaxElo int
+
+	// Inclusive bounds on the date as YYYY, YYYY.MM or YYYY.MM.DD. Games whose
+	// date is partly unknown match if they might fall within the bounds.
+	From, To string
+
+	// ECO code, a prefix of one such as B or B3, or an inclusive range such
+	// as B20-B99
+	ECO string
+
+	MinPlies, MaxPlies int
+
+	// Material left on the board when the game ends. Matching on it replays
+	// the game; games with illegal moves don't match.
+	Material *MaterialSignature
+}
+
+func (f GameFilter) Match(pgn PGN) bool {
+	return f.matchesTags(pgn) && f.matchesPlies(pgn) && f.matchesMaterial(pgn)
+}
+
+func (f GameFilter) matchesTags(pgn PGN) bool {
+	contains := func(tag, value string) bool {
+		return strings.Contains(strings.ToLower(pgn.Tags[tag]), strings.ToLower(value))
+	}
+
+	switch {
+	case f.Player != "" && !contains("White", f.Player) && !contains("Black", f.Player):
+		return false
+	case f.White != "" && !contains("White", f.White):
+		return false
+	case f.Black != "" && !contains("Black", f.Black):
+		return false
+	case f.Event != "" && !contains("Event", f.Event):
+		return false
+	case f.Result != "" && pgn.Outcome != f.Result:
+		return false
+	case f.ECO != "" && !ecoInRange(pgn.Tags["ECO"], f.ECO):
+		return false
+	}
+
+	return f.matchesElo(pgn) && f.matchesDate(pgn.Tags["Date"])
+}
+
+func (f GameFilter) matchesElo(pgn PGN) bool {
+	if f.MinElo == 0 && f.MaxElo == 0 {
+		return true
+	}
+
+	for _, tag := range []string{"WhiteElo", "BlackElo"} {
+		elo, err := strconv.Atoi(pgn.Tags[tag])
+		if err != nil || elo < f.MinElo || (f.MaxElo != 0 && elo > f.MaxElo) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// A game's date as the earliest and latest days it might be
+func dateRange(date string) (string, string) {
+	parts := strings.Split(date, ".")
+	earliest, latest := make([]string, 3), make([]string, 3)
+
+	for i, unknown := range []string{"0000", "00", "00"} {
+		if i >= len(parts) || unknownTag(parts[i]) {
+			earliest[i] = unknown
+			latest[i] = strings.Repeat("9", len(unknown))
+		} else {
+			earliest[i], latest[i] = parts[i], parts[i]
+		}
+	}
+
+	return strings.Join(earliest, "."), strings.Join(latest, ".")
+}
+
+func (f GameFilter) matchesDate(date string) bool {
+	earliest, latest := dateRange(date)
+
+	if f.From != "" {
+		if from, _ := dateRange(f.From); latest < from {
+			return false
+		}
+	}
+
+	if f.To != "" {
+		if _, to := dateRange(f.To); earliest > to {
+			return false
+		}
+	}
+
+	return true
+}
+
+func ecoInRange(eco, codes string) bool {
+	if eco == "" {
+		return false
+	}
+
+	if first, last, isRange := strings.Cut(codes, "-"); isRange {
+		return eco >= first && eco <= last
+	}
+
+	return strings.HasPrefix(eco, codes)
+}
+
+// Plies in the movetext, counted without replaying it
+func (p PGN) PlyCount() int {
+	plies := 0
+	for _, an := range p.Turns() {
+		if an != "" {
+			plies++
+		}
+	}
+
+	return plies
+}
+
+func (f GameFilter) matchesPlies(pgn PGN) bool {
+	if f.MinPlies == 0 && f.MaxPlies == 0 {
+		return true
+	}
+
+	plies := pgn.PlyCount()
+
+	return plies >= f.MinPlies && (f.MaxPlies == 0 || plies <= f.MaxPlies)
+}
+
+func (f GameFilter) matchesMaterial(pgn PGN) bool {
+	if f.Material == nil {
+		return true
+	}
+
+	game, err := pgn.Game()
+
+	return err == nil && game.Board.MaterialSignature() == *f.Material
+}
@@ -0,0 +1,76 @@
+package pawn
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type FilterTestSuite struct {
+	suite.Suite
+}
+
+func TestFilterTestSuite(t *testing.T) {
+	suite.Run(t, new(FilterTestSuite))
+}
+
+func (s *FilterTestSuite) TestTags() {
+	pgn := ParsePGN(win)
+
+	s.True(GameFilter{}.Match(pgn))
+	s.True(GameFilter{Player: "carlsen"}.Match(pgn))
+	s.True(GameFilter{White: "Anand", Black: "Carlsen"}.Match(pgn))
+	s.False(GameFilter{White: "Carlsen"}.Match(pgn))
+	s.True(GameFilter{Event: "wch", Result: BlackWin}.Match(pgn))
+	s.False(GameFilter{Result: WhiteWin}.Match(pgn))
+}
+
+func (s *FilterTestSuite) TestElo() {
+	pgn := ParsePGN(win)
+
+	s.True(GameFilter{MinElo: 2775}.Match(pgn))
+	s.False(GameFilter{MinElo: 2800}.Match(pgn))
+	s.True(GameFilter{MinElo: 2700, MaxElo: 2870}.Match(pgn))
+	s.False(GameFilter{MaxElo: 2800}.Match(pgn))
+
+	delete(pgn.Tags, "WhiteElo")
+	s.False(GameFilter{MinElo: 2000}.Match(pgn), "unrated")
+}
+
+func (s *FilterTestSuite) TestDates() {
+	pgn := ParsePGN(win)
+
+	s.True(GameFilter{From: "2013", To: "2013"}.Match(pgn))
+	s.True(GameFilter{From: "2013.11.21", To: "2013.11.21"}.Match(pgn))
+	s.False(GameFilter{From: "2013.11.22"}.Match(pgn))
+	s.False(GameFilter{To: "2013.10"}.Match(pgn))
+
+	pgn.Tags["Date"] = "2013.??.??"
+	s.True(GameFilter{From: "2013.11.22"}.Match(pgn), "might be later")
+	s.False(GameFilter{From: "2014"}.Match(pgn))
+}
+
+func (s *FilterTestSuite) TestECO() {
+	pgn := ParsePGN(win)
+
+	s.True(GameFilter{ECO: "E25"}.Match(pgn))
+	s.True(GameFilter{ECO: "E2"}.Match(pgn))
+	s.True(GameFilter{ECO: "E20-E59"}.Match(pgn))
+	s.False(GameFilter{ECO: "B20-B99"}.Match(pgn))
+}
+
+func (s *FilterTestSuite) TestPliesAndMaterial() {
+	pgn := ParsePGN(win)
+	s.Equal(56, pgn.PlyCount())
+
+	s.True(GameFilter{MinPlies: 56, MaxPlies: 56}.Match(pgn))
+	s.False(GameFilter{MaxPlies: 40}.Match(pgn))
+
+	signature, err := ParseMaterialSignature("KQQRBPPPPPPvKQRBBPPPPPP")
+	s.Require().Nil(err)
+	game, _ := pgn.Game()
+	final := game.Board.MaterialSignature()
+
+	s.True(GameFilter{Material: &final}.Match(pgn))
+	s.False(GameFilter{Material: &signature}.Match(pgn))
+}
@@ -0,0 +1,129 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/marcel/pawn"
+	"github.com/marcel/pawn/engine"
+)
+
+// Searches a position with pawn's own engine, printing each iteration's
+// score and principal variation
+func analyze(args []string) int {
+	var limits engine.Limits
+
+	flags := flag.NewFlagSet("analyze", flag.ExitOnError)
+	fen := flags.String("fen", pawn.InitialFEN, "the position to analyze in `FEN`")
+	flags.IntVar(&limits.Depth, "depth", 0, "search `plies` deep")
+	flags.DurationVar(&limits.MoveTime, "movetime", 0, "search for `duration`, e.g. 10s")
+	threads := flags.Int("threads", 1, "search with `n` goroutines")
+	flags.IntVar(&limits.MultiPV, "multipv", 1, "show the best `n` lines, each starting with a different move")
+	evaluate := flags.Bool("eval", false, "print the static evaluation term by term instead of searching")
+	flags.Usage = func() {
+		fmt.Fprintln(os.Stderr, "usage: pawn analyze [-fen FEN] [-depth plies] [-movetime duration] [-threads n] [-multipv n] [-eval]")
+		flags.PrintDefaults()
+	}
+	flags.Parse(args)
+
+	if flags.NArg() != 0 {
+		flags.Usage()
+		return 2
+	}
+
+	board, err := pawn.ParseFEN(*fen)
+	if err != nil {
+		return fail(err)
+	}
+
+	if *evaluate {
+		printTerms(engine.DefaultWeights.Terms(board))
+		return 0
+	}
+
+	if limits.Depth == 0 && limits.MoveTime == 0 {
+		limits.MoveTime = 10 * time.Second
+	}
+
+	search := engine.New()
+	search.Threads = *threads
+	search.Progress = func(result engine.Result) {
+		for index, line := range result.Lines {
+			if index == 0 {
+				fmt.Printf("%2d  %6s  %9d nodes  %s\n", result.Depth, formatScore(line.Score), result.Nodes, variation(board, line.PV))
+			} else {
+				fmt.Printf("    %6s  %15s  %s\n", formatScore(line.Score), "", variation(board, line.PV))
+			}
+		}
+	}
+
+	result := search.Search(board, limits)
+	if result.Move == (pawn.Move{}) {
+		fmt.Fprintln(os.Stderr, "no legal moves")
+		return 1
+	}
+
+	fmt.Printf("best move %s\n", board.Algebraic(result.Move))
+	fmt.Printf("%d nodes in %s, %.0f%% of table probes hit\n", result.Nodes, result.Time.Round(time.Millisecond), 100*search.Table.Stats().HitRate())
+
+	return 0
+}
+
+func printTerms(terms engine.Terms) {
+	for _, term := range []struct {
+		name  string
+		score int
+	}{
+		{"material", terms.Material},
+		{"piece-squares", terms.PieceSquares},
+		{"mobility", terms.Mobility},
+		{"pawn structure", terms.PawnStructure},
+		{"pieces", terms.Pieces},
+		{"king safety", terms.KingSafety},
+		{"total", terms.Total()},
+	} {
+		fmt.Printf("%-15s %6s\n", term.name, formatScore(term.score))
+	}
+}
+
+// Pawns to the side to move, e.g. +0.35, or the moves to mate, e.g. #3 or
+// #-2 when being mated
+func formatScore(score int) string {
+	if engine.IsMateScore(score) {
+		return "#" + strconv.Itoa(engine.MateIn(score))
+	}
+
+	return fmt.Sprintf("%+.2f", float64(score)/100)
+}
+
+// Moves in SAN numbered from the board's position, e.g. 12... Nf6 13. e5
+func variation(board *pawn.Board, moves []pawn.Move) string {
+	fields := strings.Fields(board.FEN())
+	number, _ := strconv.Atoi(fields[len(fields)-1])
+
+	text := []string{}
+	for ply, move := range moves {
+		switch {
+		case board.SideToMove() == pawn.White:
+			text = append(text, strconv.Itoa(number)+".")
+		case ply == 0:
+			text = append(text, strconv.Itoa(number)+"...")
+		}
+		if board.SideToMove() == pawn.Black {
+			number++
+		}
+
+		text = append(text, string(board.Algebraic(move)))
+		board.MakeMove(move)
+	}
+
+	for range moves {
+		board.UnmakeMove()
+	}
+
+	return strings.Join(text, " ")
+}
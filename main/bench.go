@@ -0,0 +1,76 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/marcel/pawn"
+	"github.com/marcel/pawn/engine"
+)
+
+// Positions from every stage of the game, searched by bench
+var benchPositions = []string{
+	pawn.InitialFEN,
+	"r3k2r/p1ppqpb1/bn2pnp1/3PN3/1p2P3/2N2Q1p/PPPBBPPP/R3K2R w KQkq - 0 1",
+	"r1bq1rk1/pp2bppp/2n1pn2/3p4/2PP4/2N1PN2/PP3PPP/R1BQKB1R w KQ - 0 8",
+	"2rq1rk1/pb1nbppp/1p2pn2/2pp4/2PP4/1PN1PN2/PB2BPPP/2RQ1RK1 w - - 0 12",
+	"r4rk1/1pp1qppp/p1np1n2/2b1p1B1/2B1P1b1/P1NP1N2/1PP1QPPP/R4RK1 w - - 0 10",
+	"8/2p5/3p4/KP5r/1R3p1k/8/4P1P1/8 w - - 0 1",
+	"6k1/5p2/6p1/8/7p/8/6PP/6K1 b - - 0 1",
+	"8/8/1p1r1k2/p1pPN1p1/P3KnP1/1P6/8/3R4 b - - 0 1",
+}
+
+// Searches a fixed set of positions to a fixed depth, printing the nodes and
+// time each took, to measure the engine's speed and what its heuristics are
+// worth
+func bench(args []string) int {
+	flags := flag.NewFlagSet("bench", flag.ExitOnError)
+	depth := flags.Int("depth", 6, "search each position `plies` deep")
+	threads := flags.Int("threads", 1, "search with `n` goroutines")
+	off := flags.String("off", "", "turn off the heuristics in a comma separated `list`, of "+strings.Join(engine.OptionNames, ", "))
+	flags.Usage = func() {
+		fmt.Fprintln(os.Stderr, "usage: pawn bench [-depth plies] [-threads n] [-off list]")
+		flags.PrintDefaults()
+	}
+	flags.Parse(args)
+
+	if flags.NArg() != 0 || *depth < 1 {
+		flags.Usage()
+		return 2
+	}
+
+	search := engine.New()
+	search.Threads = *threads
+	for _, name := range strings.Split(*off, ",") {
+		if name = strings.TrimSpace(name); name == "" {
+			continue
+		}
+
+		if err := search.Options.Set(name, false); err != nil {
+			return fail(err)
+		}
+	}
+
+	var nodes uint64
+	var elapsed time.Duration
+	for index, fen := range benchPositions {
+		board, err := pawn.ParseFEN(fen)
+		if err != nil {
+			return fail(err)
+		}
+
+		search.Table.Clear()
+		result := search.Search(board, engine.Limits{Depth: *depth})
+		nodes, elapsed = nodes+result.Nodes, elapsed+result.Time
+
+		fmt.Printf("%2d  %-7s %6s  %10d nodes  %8s\n", index+1, board.Algebraic(result.Move), formatScore(result.Score),
+			result.Nodes, result.Time.Round(time.Millisecond))
+	}
+
+	fmt.Printf("%d nodes in %s, %.0f nodes/s\n", nodes, elapsed.Round(time.Millisecond), float64(nodes)/elapsed.Seconds())
+
+	return 0
+}
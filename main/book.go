@@ -0,0 +1,57 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/marcel/pawn"
+)
+
+// Builds a Polyglot opening book from a PGN file
+func book(args []string) int {
+	flags := flag.NewFlagSet("book", flag.ExitOnError)
+	output := flags.String("o", "book.bin", "`file` to write the book to")
+	minGames := flags.Int("min-games", 1, "leave out moves played in fewer games")
+	plies := flags.Int("plies", pawn.DefaultExplorerPlies, "only add moves up to this ply, 0 for all")
+	winners := flags.Bool("winners", false, "only add moves by the side that won")
+	flags.Usage = func() {
+		fmt.Fprintln(os.Stderr, "usage: pawn book [-o book.bin] [-min-games n] [-plies n] [-winners] file.pgn[.gz]")
+		flags.PrintDefaults()
+	}
+	flags.Parse(args)
+
+	if flags.NArg() != 1 {
+		flags.Usage()
+		return 2
+	}
+
+	pgns, err := parsePGNFile(flags.Arg(0))
+	if err != nil {
+		return fail(err)
+	}
+
+	book := pawn.BuildPolyglotBook(pgns, pawn.BookOptions{
+		MinGames:    *minGames,
+		MaxPlies:    *plies,
+		WinnersOnly: *winners,
+	})
+
+	file, err := os.Create(*output)
+	if err != nil {
+		return fail(err)
+	}
+
+	if err := book.Write(file); err != nil {
+		file.Close()
+		return fail(err)
+	}
+
+	if err := file.Close(); err != nil {
+		return fail(err)
+	}
+
+	fmt.Fprintf(os.Stderr, "%d entries from %d games written to %s\n", len(book.Entries), len(pgns), *output)
+
+	return 0
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"strings"
+
+	"github.com/marcel/pawn"
+)
+
+// The games the replayer shows, from a PGN file or a pawn database whose
+// games are only decoded once selected
+type gameCollection interface {
+	Len() int
+	Tags(game int) pawn.Tags
+	PGN(game int) (pawn.PGN, error)
+}
+
+type pgnCollection []pawn.PGN
+
+func (c pgnCollection) Len() int {
+	return len(c)
+}
+
+func (c pgnCollection) Tags(game int) pawn.Tags {
+	return c[game].Tags
+}
+
+func (c pgnCollection) PGN(game int) (pawn.PGN, error) {
+	return c[game], nil
+}
+
+const databaseExtension = ".pawndb"
+
+func openGames(path string) (gameCollection, error) {
+	if strings.HasSuffix(path, databaseExtension) {
+		return pawn.OpenDatabase(path)
+	}
+
+	pgns, err := parsePGNFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return pgnCollection(pgns), nil
+}
+
+// Every game in the collection, skipping any that can't be read
+func allPGNs(games gameCollection) []pawn.PGN {
+	if pgns, parsed := games.(pgnCollection); parsed {
+		return pgns
+	}
+
+	pgns := make([]pawn.PGN, 0, games.Len())
+	for game := 0; game < games.Len(); game++ {
+		if pgn, err := games.PGN(game); err == nil {
+			pgns = append(pgns, pgn)
+		}
+	}
+
+	return pgns
+}
@@ -0,0 +1,35 @@
+package main
+
+import (
+	"fmt"
+	"os"
+)
+
+// Subcommands run instead of the replayer, e.g. `pawn validate games.pgn`.
+// Each returns the process's exit status.
+var commands = map[string]func(args []string) int{
+	"validate":   validate,
+	"export":     export,
+	"book":       book,
+	"search":     search,
+	"query":      query,
+	"convert":    convert,
+	"dedupe":     dedupe,
+	"filter":     filter,
+	"split":      split,
+	"merge":      merge,
+	"stats":      stats,
+	"ratings":    ratings,
+	"crosstable": crosstable,
+	"tournament": tournament,
+	"analyze":    analyze,
+	"perft":      perft,
+	"bench":      bench,
+	"uci":        uciEngine,
+	"xboard":     xboardEngine,
+}
+
+func fail(err error) int {
+	fmt.Fprintln(os.Stderr, err)
+	return 2
+}
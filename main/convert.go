@@ -0,0 +1,47 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/marcel/pawn"
+)
+
+// Converts a PGN file to a pawn database the replayer opens without parsing
+func convert(args []string) int {
+	flags := flag.NewFlagSet("convert", flag.ExitOnError)
+	flags.Usage = func() {
+		fmt.Fprintln(os.Stderr, "usage: pawn convert file.pgn[.gz] file"+databaseExtension)
+	}
+	flags.Parse(args)
+
+	if flags.NArg() != 2 {
+		flags.Usage()
+		return 2
+	}
+
+	pgns, err := parsePGNFile(flags.Arg(0))
+	if err != nil {
+		return fail(err)
+	}
+
+	file, err := os.Create(flags.Arg(1))
+	if err != nil {
+		return fail(err)
+	}
+
+	if err := pawn.WriteDatabase(file, pgns); err != nil {
+		file.Close()
+		os.Remove(flags.Arg(1))
+		return fail(err)
+	}
+
+	if err := file.Close(); err != nil {
+		return fail(err)
+	}
+
+	fmt.Fprintf(os.Stderr, "%d games written to %s\n", len(pgns), flags.Arg(1))
+
+	return 0
+}
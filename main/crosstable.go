@@ -0,0 +1,81 @@
+package main
+
+import (
+	"encoding/csv"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/marcel/pawn"
+)
+
+var tiebreaksByName = map[string]pawn.Tiebreak{
+	"de":   pawn.DirectEncounter,
+	"sb":   pawn.SonnebornBerger,
+	"buch": pawn.Buchholz,
+	"wins": pawn.Wins,
+}
+
+// Writes the crosstable of an event's games to stdout as text, HTML or CSV
+func crosstable(args []string) int {
+	var criteria pawn.GameFilter
+
+	flags := flag.NewFlagSet("crosstable", flag.ExitOnError)
+	flags.StringVar(&criteria.Event, "event", "", "only games of the event whose name contains `name`")
+	format := flags.String("format", "text", "write `text`, html or csv")
+	tiebreaks := flags.String("tiebreaks", "", "break ties by a comma separated `list` of de, sb, buch and wins")
+	flags.Usage = func() {
+		fmt.Fprintln(os.Stderr, "usage: pawn crosstable [-event name] [-format text|html|csv] [-tiebreaks list] file.pgn[.gz]")
+		flags.PrintDefaults()
+	}
+	flags.Parse(args)
+
+	if flags.NArg() != 1 || (*format != "text" && *format != "html" && *format != "csv") {
+		flags.Usage()
+		return 2
+	}
+
+	order := []pawn.Tiebreak{}
+	if *tiebreaks != "" {
+		for _, name := range strings.Split(*tiebreaks, ",") {
+			tiebreak, known := tiebreaksByName[strings.ToLower(strings.TrimSpace(name))]
+			if !known {
+				flags.Usage()
+				return 2
+			}
+			order = append(order, tiebreak)
+		}
+	}
+
+	pgns := []pawn.PGN{}
+	err := eachPGN(flags.Arg(0), func(pgn pawn.PGN) {
+		if criteria.Match(pgn) {
+			pgns = append(pgns, pgn)
+		}
+	})
+	if err != nil {
+		return fail(err)
+	}
+
+	table := pawn.NewCrosstable(pgns, order...)
+	if len(table.Players) == 0 {
+		fmt.Fprintln(os.Stderr, "no finished games")
+		return 1
+	}
+
+	switch *format {
+	case "html":
+		fmt.Print(table.HTML())
+	case "csv":
+		out := csv.NewWriter(os.Stdout)
+		out.WriteAll(table.Records())
+		if err := out.Error(); err != nil {
+			return fail(err)
+		}
+	default:
+		fmt.Print(table)
+	}
+
+	return 0
+}
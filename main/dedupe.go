@@ -0,0 +1,47 @@
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/marcel/pawn"
+)
+
+// Writes the games of one or more PGN files to stdout with copies of the same
+// game merged into one
+func dedupe(args []string) int {
+	flags := flag.NewFlagSet("dedupe", flag.ExitOnError)
+	flags.Usage = func() {
+		fmt.Fprintln(os.Stderr, "usage: pawn dedupe file.pgn[.gz] ...")
+	}
+	flags.Parse(args)
+
+	if flags.NArg() == 0 {
+		flags.Usage()
+		return 2
+	}
+
+	pgns := []pawn.PGN{}
+	for _, path := range flags.Args() {
+		parsed, err := parsePGNFile(path)
+		if err != nil {
+			return fail(err)
+		}
+		pgns = append(pgns, parsed...)
+	}
+
+	deduped, removed := pawn.Dedupe(pgns)
+
+	out := bufio.NewWriter(os.Stdout)
+	defer out.Flush()
+
+	for _, pgn := range deduped {
+		fmt.Fprintln(out, pgn)
+	}
+
+	fmt.Fprintf(os.Stderr, "%d games read, %d duplicates merged\n", len(pgns), removed)
+
+	return 0
+}
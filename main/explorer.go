@@ -0,0 +1,76 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/jroimartin/gocui"
+	"github.com/marcel/pawn"
+)
+
+// Shows how often each move was played from the current position across all
+// the loaded games and how those games scored
+type ExplorerPanel struct {
+	explorer *pawn.Explorer
+}
+
+var explorerPanel = new(ExplorerPanel)
+
+const explorerRows = 12
+
+func (ep ExplorerPanel) Layout(g *gocui.Gui) error {
+	maxX, _ := g.Size()
+	boardViewName := fmt.Sprintf("board-%d-%d", gameMenu.currentGame, gamePlayer.currentTurn)
+	_, _, boardX1, _, err := g.ViewPosition(boardViewName)
+	if err != nil {
+		return nil
+	}
+
+	dimensions := viewDimensions{
+		x0: boardX1 + 7,
+		y0: 8,
+		x1: maxX - 1,
+		y1: 8 + explorerRows + 3,
+	}
+
+	// Too narrow to show alongside the board
+	if dimensions.x1-dimensions.x0 < 34 {
+		return nil
+	}
+
+	name := fmt.Sprintf("explorer-%d-%d-%t", gameMenu.currentGame, gamePlayer.currentTurn, ep.explorer != nil)
+	initializeView(g, name, dimensions,
+		func(v *gocui.View) {
+			v.Title = "Explorer"
+
+			if ep.explorer == nil {
+				fmt.Fprintln(v, "Building opening tree...")
+				return
+			}
+
+			moves := ep.explorer.Moves(gamePlayer.position())
+			if len(moves) == 0 {
+				fmt.Fprintln(v, "No games reached this position")
+				return
+			}
+
+			fmt.Fprintf(v, "%-8s %5s %13s %5s\n", "Move", "Games", "+ / = / -", "Elo")
+			for index, move := range moves {
+				if index == explorerRows {
+					break
+				}
+
+				white, draw, black := move.Percentages()
+				elo := "-"
+				if move.AverageElo() > 0 {
+					elo = fmt.Sprint(move.AverageElo())
+				}
+
+				fmt.Fprintf(v, "%-8s %5d %3.0f / %2.0f / %2.0f %5s\n", move.SAN, move.Games, white, draw, black, elo)
+			}
+		},
+	)
+
+	g.SetViewOnTop(name)
+
+	return nil
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/marcel/pawn"
+)
+
+// Writes every game in a PGN file to stdout as export format PGN
+func export(args []string) int {
+	flags := flag.NewFlagSet("export", flag.ExitOnError)
+	eco := flags.Bool("eco", false, "fill in missing ECO, Opening and Variation tags")
+	flags.Usage = func() {
+		fmt.Fprintln(os.Stderr, "usage: pawn export [-eco] file.pgn[.gz]")
+		flags.PrintDefaults()
+	}
+	flags.Parse(args)
+
+	if flags.NArg() != 1 {
+		flags.Usage()
+		return 2
+	}
+
+	pgns, err := parsePGNFile(flags.Arg(0))
+	if err != nil {
+		return fail(err)
+	}
+
+	out := bufio.NewWriter(os.Stdout)
+	defer out.Flush()
+
+	for _, pgn := range pgns {
+		if *eco {
+			classify(pgn)
+		}
+
+		fmt.Fprintln(out, pgn)
+	}
+
+	return 0
+}
+
+// Fills in the opening tags of pgn from the moves up to its first illegal
+// one, if any
+func classify(pgn pawn.PGN) {
+	game, _ := pgn.Game()
+
+	if opening, found := pawn.ClassifyOpening(game); found {
+		opening.FillTags(pgn.Tags)
+	}
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/marcel/pawn"
+)
+
+// Writes the games of one or more PGN files that meet every criterion given
+// to stdout
+func filter(args []string) int {
+	var criteria pawn.GameFilter
+	var result, material string
+
+	flags := flag.NewFlagSet("filter", flag.ExitOnError)
+	flags.StringVar(&criteria.Player, "player", "", "either player's name contains `name`")
+	flags.StringVar(&criteria.White, "white", "", "White's name contains `name`")
+	flags.StringVar(&criteria.Black, "black", "", "Black's name contains `name`")
+	flags.IntVar(&criteria.MinElo, "min-elo", 0, "both players rated at least `elo`")
+	flags.IntVar(&criteria.MaxElo, "max-elo", 0, "both players rated at most `elo`")
+	flags.StringVar(&criteria.From, "from", "", "played on or after `date` (YYYY[.MM[.DD]])")
+	flags.StringVar(&criteria.To, "to", "", "played on or before `date` (YYYY[.MM[.DD]])")
+	flags.StringVar(&criteria.Event, "event", "", "event name contains `name`")
+	flags.StringVar(&result, "result", "", "ended `1-0`, 0-1, 1/2-1/2 or *")
+	flags.StringVar(&criteria.ECO, "eco", "", "ECO `code`, prefix or range such as B20-B99")
+	flags.IntVar(&criteria.MinPlies, "min-plies", 0, "at least `n` plies long")
+	flags.IntVar(&criteria.MaxPlies, "max-plies", 0, "at most `n` plies long")
+	flags.StringVar(&material, "material", "", "ends with `signature` such as KRPvKR")
+	flags.Usage = func() {
+		fmt.Fprintln(os.Stderr, "usage: pawn filter [flags] file.pgn[.gz] ...")
+		flags.PrintDefaults()
+	}
+	flags.Parse(args)
+
+	if flags.NArg() == 0 {
+		flags.Usage()
+		return 2
+	}
+
+	criteria.Result = pawn.Outcome(result)
+
+	if material != "" {
+		signature, err := pawn.ParseMaterialSignature(material)
+		if err != nil {
+			return fail(err)
+		}
+		criteria.Material = &signature
+	}
+
+	out := bufio.NewWriter(os.Stdout)
+	defer out.Flush()
+
+	read, matched := 0, 0
+	for _, path := range flags.Args() {
+		err := eachPGN(path, func(pgn pawn.PGN) {
+			read++
+
+			if criteria.Match(pgn) {
+				matched++
+				fmt.Fprintln(out, pgn)
+			}
+		})
+
+		if err != nil {
+			return fail(err)
+		}
+	}
+
+	fmt.Fprintf(os.Stderr, "%d of %d games matched\n", matched, read)
+
+	return 0
+}
@@ -0,0 +1,44 @@
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/marcel/pawn"
+)
+
+// Writes the games of several PGN files to stdout as one export format
+// collection
+func merge(args []string) int {
+	flags := flag.NewFlagSet("merge", flag.ExitOnError)
+	flags.Usage = func() {
+		fmt.Fprintln(os.Stderr, "usage: pawn merge file.pgn[.gz] ...")
+	}
+	flags.Parse(args)
+
+	if flags.NArg() == 0 {
+		flags.Usage()
+		return 2
+	}
+
+	out := bufio.NewWriter(os.Stdout)
+	defer out.Flush()
+
+	games := 0
+	for _, path := range flags.Args() {
+		err := eachPGN(path, func(pgn pawn.PGN) {
+			games++
+			fmt.Fprintln(out, pgn)
+		})
+
+		if err != nil {
+			return fail(err)
+		}
+	}
+
+	fmt.Fprintf(os.Stderr, "%d games from %d files\n", games, flags.NArg())
+
+	return 0
+}
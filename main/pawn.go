@@ -21,7 +21,10 @@ var initialized bool
 
 func (gp *GamePlayer) loadCurrentSelection(gameMenu *GameMenu) bool {
 	if gameMenu.currentGame != gameMenu.currentSelection || !initialized {
-		selectedPGN := gameMenu.pgns[gameMenu.currentSelection]
+		selectedPGN, err := gameMenu.games.PGN(gameMenu.currentSelection)
+		if err != nil {
+			selectedPGN = pawn.PGN{Tags: gameMenu.games.Tags(gameMenu.currentSelection)}
+		}
 		gp.InitWithPGN(&selectedPGN)
 		gameMenu.currentGame = gameMenu.currentSelection
 
@@ -31,28 +34,57 @@ func (gp *GamePlayer) loadCurrentSelection(gameMenu *GameMenu) bool {
 	return false
 }
 
-func initializeGamePlayer() {
-	pgnFile := os.Args[1]
+// Parses every game in a PGN file, which may be gzipped
+func parsePGNFile(pgnFile string) ([]pawn.PGN, error) {
+	pgns := []pawn.PGN{}
+
+	err := eachPGN(pgnFile, func(pgn pawn.PGN) {
+		pgns = append(pgns, pgn)
+	})
 
+	return pgns, err
+}
+
+// Calls each with every game in a PGN file as it's parsed, without holding
+// the collection in memory
+func eachPGN(pgnFile string, each func(pawn.PGN)) error {
 	file, err := os.Open(pgnFile)
 
 	if err != nil {
-		log.Fatal(err)
+		return err
 	}
 	defer file.Close()
 
 	var pgnReader io.Reader
 
 	if strings.HasSuffix(pgnFile, ".gz") {
-		pgnReader, err = gzip.NewReader(file)
+		gzipReader, err := gzip.NewReader(file)
 		if err != nil {
-			log.Fatal(err)
+			return err
 		}
+		defer gzipReader.Close()
+
+		pgnReader = gzipReader
 	} else {
 		pgnReader = file
 	}
 
-	gameMenu.pgns = pawn.NewPGNParserFromReader(pgnReader).ParseAll()
+	parser := pawn.NewPGNParserFromReader(pgnReader)
+	for pgn, more := parser.Next(); more; pgn, more = parser.Next() {
+		each(pgn)
+	}
+
+	return nil
+}
+
+func initializeGamePlayer() {
+	games, err := openGames(os.Args[1])
+
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	gameMenu.games = games
 
 	gamePlayer.loadCurrentSelection(gameMenu)
 
@@ -60,6 +92,12 @@ func initializeGamePlayer() {
 }
 
 func main() {
+	if len(os.Args) > 1 {
+		if command, isCommand := commands[os.Args[1]]; isCommand {
+			os.Exit(command(os.Args[2:]))
+		}
+	}
+
 	initializeGamePlayer()
 
 	g, _ := gocui.NewGui(gocui.Output256)
@@ -73,7 +111,8 @@ func main() {
 		"←     Previous Move",
 	}}
 
-	g.SetManager(gameMenu, commandHelp, gamePlayer)
+	g.SetManager(gameMenu, commandHelp, gamePlayer, explorerPanel, playersPanel)
+	buildPanels(g, gameMenu.games)
 
 	initKeybindings(g)
 
@@ -82,6 +121,22 @@ func main() {
 	}
 }
 
+// Builds the opening tree and player statistics off the main loop since
+// replaying a large collection takes a few seconds
+func buildPanels(g *gocui.Gui, games gameCollection) {
+	go func() {
+		pgns := allPGNs(games)
+		explorer := pawn.ExplorerFromPGNs(pgns)
+		players := playerStatsByName(pgns)
+
+		g.Update(func(g *gocui.Gui) error {
+			explorerPanel.explorer = explorer
+			playersPanel.players = players
+			return nil
+		})
+	}()
+}
+
 type CommandHelp struct {
 	commands []string
 }
@@ -171,7 +226,7 @@ func initKeybindings(g *gocui.Gui) error {
 }
 
 type GameMenu struct {
-	pgns             []pawn.PGN
+	games            gameCollection
 	currentGame      int
 	currentSelection int
 }
@@ -185,8 +240,8 @@ func (gm GameMenu) Layout(g *gocui.Gui) error {
 		v.Highlight = true
 		v.SelBgColor = gocui.ColorGreen
 		v.SelFgColor = gocui.ColorBlack
-		for index, pgn := range gameMenu.pgns {
-			matchup := pgn.MatchUp()
+		for index := 0; index < gameMenu.games.Len(); index++ {
+			matchup := pawn.PGN{Tags: gameMenu.games.Tags(index)}.MatchUp()
 			fmt.Fprintf(v, "%3d. %s\n", index+1, matchup)
 		}
 	}
@@ -309,6 +364,19 @@ func (gp GamePlayer) Layout(g *gocui.Gui) error {
 	return nil
 }
 
+// A board showing the position after the current turn
+func (gp *GamePlayer) position() *pawn.Board {
+	board := pawn.NewBoard()
+
+	for _, an := range gp.pgn.Turns()[:gp.currentTurn] {
+		if _, err := board.MoveFromAlgebraic(an); err != nil {
+			break
+		}
+	}
+
+	return board
+}
+
 func (gp *GamePlayer) playNextMove() {
 	if gp.currentTurn < len(gp.pgn.Turns())-1 {
 		gp.currentTurn++
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/marcel/pawn"
+)
+
+// Counts the positions reachable in a number of plies, which checks move
+// generation against published counts and measures its speed
+func perft(args []string) int {
+	flags := flag.NewFlagSet("perft", flag.ExitOnError)
+	fen := flags.String("fen", pawn.InitialFEN, "count from the position in `FEN`")
+	divide := flags.Bool("divide", false, "print the count after each move too")
+	flags.Usage = func() {
+		fmt.Fprintln(os.Stderr, "usage: pawn perft [-fen FEN] [-divide] depth")
+		flags.PrintDefaults()
+	}
+	flags.Parse(args)
+
+	if flags.NArg() != 1 {
+		flags.Usage()
+		return 2
+	}
+
+	depth, err := strconv.Atoi(flags.Arg(0))
+	if err != nil || depth < 1 {
+		flags.Usage()
+		return 2
+	}
+
+	board, err := pawn.ParseFEN(*fen)
+	if err != nil {
+		return fail(err)
+	}
+
+	start := time.Now()
+
+	nodes := 0
+	if *divide {
+		for _, move := range board.LegalMoves() {
+			board.MakeMove(move)
+			count := board.Perft(depth - 1)
+			board.UnmakeMove()
+
+			fmt.Printf("%s: %d\n", board.EncodeMove(move), count)
+			nodes += count
+		}
+		fmt.Println()
+	} else {
+		nodes = board.Perft(depth)
+	}
+
+	elapsed := time.Since(start)
+	fmt.Printf("%d nodes in %s, %.0f nodes/s\n", nodes, elapsed.Round(time.Millisecond), float64(nodes)/elapsed.Seconds())
+
+	return 0
+}
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/jroimartin/gocui"
+	"github.com/marcel/pawn"
+)
+
+// Shows how the current game's players have done across the loaded games
+type PlayersPanel struct {
+	players map[string]pawn.PlayerStats
+}
+
+var playersPanel = new(PlayersPanel)
+
+func playerStatsByName(pgns []pawn.PGN) map[string]pawn.PlayerStats {
+	players := map[string]pawn.PlayerStats{}
+	for _, stats := range pawn.AllPlayerStatistics(pgns) {
+		players[stats.Name] = stats
+	}
+
+	return players
+}
+
+func (pp PlayersPanel) Layout(g *gocui.Gui) error {
+	maxX, _ := g.Size()
+	boardViewName := fmt.Sprintf("board-%d-%d", gameMenu.currentGame, gamePlayer.currentTurn)
+	_, _, boardX1, _, err := g.ViewPosition(boardViewName)
+	if err != nil {
+		return nil
+	}
+
+	y0 := 8 + explorerRows + 4
+	dimensions := viewDimensions{
+		x0: boardX1 + 7,
+		y0: y0,
+		x1: maxX - 1,
+		y1: y0 + 10,
+	}
+
+	// Too narrow to show alongside the board
+	if dimensions.x1-dimensions.x0 < 34 {
+		return nil
+	}
+
+	name := fmt.Sprintf("players-%d-%t", gameMenu.currentGame, pp.players != nil)
+	initializeView(g, name, dimensions,
+		func(v *gocui.View) {
+			v.Title = "Players"
+
+			if pp.players == nil {
+				fmt.Fprintln(v, "Gathering statistics...")
+				return
+			}
+
+			white := pp.players[gamePlayer.pgn.Tags["White"]]
+			black := pp.players[gamePlayer.pgn.Tags["Black"]]
+
+			row := func(label string, stat func(pawn.PlayerStats) string) {
+				fmt.Fprintf(v, "%-10s %11.11s %11.11s\n", label, stat(white), stat(black))
+			}
+			score := func(score pawn.Score) string {
+				return fmt.Sprintf("%d %3.0f%%", score.Games, score.Percentage())
+			}
+
+			row("", func(stats pawn.PlayerStats) string { return stats.Name })
+			row("Games", func(stats pawn.PlayerStats) string { return score(stats.Score) })
+			row("As White", func(stats pawn.PlayerStats) string { return score(stats.White) })
+			row("As Black", func(stats pawn.PlayerStats) string { return score(stats.Black) })
+			row("Perf.", func(stats pawn.PlayerStats) string {
+				if stats.Performance == 0 {
+					return "-"
+				}
+				return fmt.Sprint(stats.Performance)
+			})
+			row("Avg moves", func(stats pawn.PlayerStats) string { return fmt.Sprintf("%.0f", stats.AverageMoves) })
+			row("Win run", func(stats pawn.PlayerStats) string { return fmt.Sprint(stats.Streaks.Winning) })
+			row("Unbeaten", func(stats pawn.PlayerStats) string { return fmt.Sprint(stats.Streaks.Unbeaten) })
+			row("Loss run", func(stats pawn.PlayerStats) string { return fmt.Sprint(stats.Streaks.Losing) })
+		},
+	)
+
+	g.SetViewOnTop(name)
+
+	return nil
+}
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"runtime"
+
+	"github.com/marcel/pawn"
+)
+
+type queryReport struct {
+	Query   string       `json:"query"`
+	Games   int          `json:"games"`
+	Matches []queryMatch `json:"matches"`
+}
+
+type queryMatch struct {
+	File string `json:"file"`
+	searchMatch
+}
+
+// Writes a JSON report to stdout of the games in PGN files matching a
+// pattern query. Exits 1 if no game does.
+func query(args []string) int {
+	flags := flag.NewFlagSet("query", flag.ExitOnError)
+	workers := flags.Int("workers", runtime.NumCPU(), "search this many games at once")
+	flags.Usage = func() {
+		fmt.Fprintln(os.Stderr, "usage: pawn query [-workers n] 'N@f5 and castled black short' file.pgn[.gz] ...")
+		flags.PrintDefaults()
+	}
+	flags.Parse(args)
+
+	if flags.NArg() < 2 {
+		flags.Usage()
+		return 2
+	}
+
+	compiled, err := pawn.CompileQuery(flags.Arg(0))
+	if err != nil {
+		return fail(err)
+	}
+
+	// The file of each game and the index of that file's first game, which
+	// may be named more than once
+	pgns, files, firstGames := []pawn.PGN{}, []string{}, []int{}
+
+	for _, file := range flags.Args()[1:] {
+		parsed, err := parsePGNFile(file)
+		if err != nil {
+			return fail(err)
+		}
+
+		first := len(pgns)
+		pgns = append(pgns, parsed...)
+		for range parsed {
+			files = append(files, file)
+			firstGames = append(firstGames, first)
+		}
+	}
+
+	report := queryReport{Query: compiled.Source, Games: len(pgns), Matches: []queryMatch{}}
+
+	for _, match := range compiled.Search(pgns, *workers) {
+		pgn, file := pgns[match.Game], files[match.Game]
+		report.Matches = append(report.Matches, queryMatch{file, searchMatch{
+			Game:  match.Game - firstGames[match.Game] + 1,
+			Ply:   match.Ply,
+			Event: pgn.Tags["Event"],
+			Round: pgn.Tags["Round"],
+			White: pgn.Tags["White"],
+			Black: pgn.Tags["Black"],
+		}})
+	}
+
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(report); err != nil {
+		return fail(err)
+	}
+
+	if len(report.Matches) == 0 {
+		return 1
+	}
+
+	return 0
+}
@@ -0,0 +1,136 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+
+	"github.com/marcel/pawn"
+)
+
+var ratingSystems = map[string]pawn.RatingSystem{
+	"elo":     pawn.Elo,
+	"glicko2": pawn.Glicko2,
+}
+
+// Rates the players of one or more PGN files from their results, writing a
+// leaderboard and optionally each player's rating history to stdout as JSON
+// or CSV
+func ratings(args []string) int {
+	var options pawn.RatingOptions
+
+	flags := flag.NewFlagSet("ratings", flag.ExitOnError)
+	system := flags.String("system", "elo", "rate by `elo` or glicko2")
+	flags.Float64Var(&options.K, "k", pawn.DefaultKFactor, "Elo K-`factor`")
+	flags.Float64Var(&options.Initial, "initial", pawn.DefaultInitialRating, "`rating` of new players")
+	flags.Float64Var(&options.Tau, "tau", pawn.DefaultTau, "Glicko-2 volatility `constraint`")
+	history := flags.Bool("history", false, "include each player's rating after every game or Glicko-2 period")
+	format := flags.String("format", "json", "write `json` or csv")
+	flags.Usage = func() {
+		fmt.Fprintln(os.Stderr, "usage: pawn ratings [-system elo|glicko2] [-k factor] [-initial rating] [-history] [-format json|csv] file.pgn[.gz] ...")
+		flags.PrintDefaults()
+	}
+	flags.Parse(args)
+
+	ratingSystem, known := ratingSystems[*system]
+	// A K, initial rating or tau of zero would be taken as asking for the
+	// default
+	if flags.NArg() == 0 || !known || (*format != "json" && *format != "csv") ||
+		options.K <= 0 || options.Initial <= 0 || options.Tau <= 0 {
+		flags.Usage()
+		return 2
+	}
+	options.System = ratingSystem
+
+	pgns := []pawn.PGN{}
+	for _, path := range flags.Args() {
+		parsed, err := parsePGNFile(path)
+		if err != nil {
+			return fail(err)
+		}
+		pgns = append(pgns, parsed...)
+	}
+
+	leaderboard := pawn.ComputeRatings(pgns, options).Leaderboard()
+	if !*history {
+		for index := range leaderboard {
+			leaderboard[index].History = nil
+		}
+	}
+
+	var err error
+	if *format == "json" {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		err = encoder.Encode(leaderboard)
+	} else if *history {
+		err = writeRatingHistoryCSV(os.Stdout, leaderboard)
+	} else {
+		err = writeLeaderboardCSV(os.Stdout, leaderboard)
+	}
+
+	if err != nil {
+		return fail(err)
+	}
+
+	return 0
+}
+
+func formatRating(rating float64) string {
+	return strconv.FormatFloat(rating, 'f', 1, 64)
+}
+
+// Blank under Elo, which has no deviation
+func formatDeviation(deviation float64) string {
+	if deviation == 0 {
+		return ""
+	}
+
+	return formatRating(deviation)
+}
+
+func writeLeaderboardCSV(w io.Writer, leaderboard []pawn.PlayerRating) error {
+	out := csv.NewWriter(w)
+	out.Write([]string{"rank", "player", "rating", "deviation", "games"})
+
+	for index, player := range leaderboard {
+		out.Write([]string{
+			strconv.Itoa(index + 1),
+			player.Name,
+			formatRating(player.Rating),
+			formatDeviation(player.Deviation),
+			strconv.Itoa(player.Games),
+		})
+	}
+
+	out.Flush()
+
+	return out.Error()
+}
+
+// A row per player per rating change, players in leaderboard order. Games are
+// counted from 1 in the order the files were given.
+func writeRatingHistoryCSV(w io.Writer, leaderboard []pawn.PlayerRating) error {
+	out := csv.NewWriter(w)
+	out.Write([]string{"player", "date", "game", "rating", "deviation"})
+
+	for _, player := range leaderboard {
+		for _, point := range player.History {
+			out.Write([]string{
+				player.Name,
+				point.Date,
+				strconv.Itoa(point.Game + 1),
+				formatRating(point.Rating),
+				formatDeviation(point.Deviation),
+			})
+		}
+	}
+
+	out.Flush()
+
+	return out.Error()
+}
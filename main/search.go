@@ -0,0 +1,103 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/marcel/pawn"
+)
+
+type searchReport struct {
+	File    string        `json:"file"`
+	Games   int           `json:"games"`
+	Matches []searchMatch `json:"matches"`
+}
+
+type searchMatch struct {
+	Game  int    `json:"game"` // Counted from 1 in file order
+	Ply   int    `json:"ply"`  // Moves played to reach the position
+	Event string `json:"event,omitempty"`
+	Round string `json:"round,omitempty"`
+	White string `json:"white,omitempty"`
+	Black string `json:"black,omitempty"`
+}
+
+// Writes a JSON report to stdout of the games in a PGN file that reached a
+// position. Exits 1 if no game did.
+func search(args []string) int {
+	flags := flag.NewFlagSet("search", flag.ExitOnError)
+	fen := flags.String("fen", "", "match this exact `position`")
+	material := flags.String("material", "", "match positions with this `signature`, e.g. KRPPvKR")
+	pawns := flags.String("pawns", "", "match positions with the pawns of this `FEN`")
+	flags.Usage = func() {
+		fmt.Fprintln(os.Stderr, "usage: pawn search [-fen FEN] [-material KRPvKR] [-pawns FEN] file.pgn[.gz]")
+		flags.PrintDefaults()
+	}
+	flags.Parse(args)
+
+	if flags.NArg() != 1 || (*fen == "" && *material == "" && *pawns == "") {
+		flags.Usage()
+		return 2
+	}
+
+	query := pawn.PositionQuery{}
+
+	if *fen != "" {
+		board, err := pawn.ParseFEN(*fen)
+		if err != nil {
+			return fail(err)
+		}
+		query.Position = board
+	}
+
+	if *material != "" {
+		signature, err := pawn.ParseMaterialSignature(*material)
+		if err != nil {
+			return fail(err)
+		}
+		query.Material = &signature
+	}
+
+	if *pawns != "" {
+		board, err := pawn.ParseFEN(*pawns)
+		if err != nil {
+			return fail(err)
+		}
+		structure := board.PawnStructure()
+		query.Pawns = &structure
+	}
+
+	pgns, err := parsePGNFile(flags.Arg(0))
+	if err != nil {
+		return fail(err)
+	}
+
+	index := pawn.PositionIndexFromPGNs(pgns)
+	report := searchReport{File: flags.Arg(0), Games: len(pgns), Matches: []searchMatch{}}
+
+	for _, match := range index.Search(query) {
+		pgn := index.PGNs[match.Game]
+		report.Matches = append(report.Matches, searchMatch{
+			Game:  match.Game + 1,
+			Ply:   match.Ply,
+			Event: pgn.Tags["Event"],
+			Round: pgn.Tags["Round"],
+			White: pgn.Tags["White"],
+			Black: pgn.Tags["Black"],
+		})
+	}
+
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(report); err != nil {
+		return fail(err)
+	}
+
+	if len(report.Matches) == 0 {
+		return 1
+	}
+
+	return 0
+}
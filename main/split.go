@@ -0,0 +1,190 @@
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode"
+
+	"github.com/marcel/pawn"
+)
+
+// The files games are split into, named for an event, player or year. Games
+// are written to files by the tag value(s) a splitter returns.
+var splitters = map[string]func(pawn.PGN) []string{
+	"event": func(pgn pawn.PGN) []string {
+		return []string{pgn.Tags["Event"]}
+	},
+	"player": func(pgn pawn.PGN) []string {
+		return []string{pgn.Tags["White"], pgn.Tags["Black"]}
+	},
+	"year": func(pgn pawn.PGN) []string {
+		year, _, _ := strings.Cut(pgn.Tags["Date"], ".")
+		return []string{year}
+	},
+}
+
+// Writes the games of one or more PGN files to a file per event, player or
+// year in a directory. A player's file holds the games they played with
+// either color.
+func split(args []string) int {
+	flags := flag.NewFlagSet("split", flag.ExitOnError)
+	by := flags.String("by", "event", "split by `event`, player or year")
+	dir := flags.String("dir", ".", "write files to `directory`, replacing any of the same name")
+	flags.Usage = func() {
+		fmt.Fprintln(os.Stderr, "usage: pawn split [-by event|player|year] [-dir directory] file.pgn[.gz] ...")
+		flags.PrintDefaults()
+	}
+	flags.Parse(args)
+
+	splitter, known := splitters[*by]
+	if flags.NArg() == 0 || !known {
+		flags.Usage()
+		return 2
+	}
+
+	if err := os.MkdirAll(*dir, 0755); err != nil {
+		return fail(err)
+	}
+
+	files := newSplitFiles(*dir)
+
+	var writeErr error
+	games := 0
+	for _, path := range flags.Args() {
+		err := eachPGN(path, func(pgn pawn.PGN) {
+			games++
+
+			for _, name := range splitFileNames(splitter(pgn)) {
+				if err := files.write(name, pgn); err != nil && writeErr == nil {
+					writeErr = err
+				}
+			}
+		})
+
+		if err == nil {
+			err = writeErr
+		}
+
+		if err != nil {
+			files.close()
+			return fail(err)
+		}
+	}
+
+	if err := files.close(); err != nil {
+		return fail(err)
+	}
+
+	fmt.Fprintf(os.Stderr, "%d games split into %d files in %s\n", games, len(files.created), *dir)
+
+	return 0
+}
+
+// File names for tag values, unknown values being put together in
+// unknown.pgn. Values naming the same file are only given once.
+func splitFileNames(values []string) []string {
+	names := []string{}
+
+	for _, value := range values {
+		name := strings.Trim(strings.Map(func(r rune) rune {
+			if unicode.IsLetter(r) || unicode.IsDigit(r) || r == '-' || r == '.' {
+				return r
+			}
+			return '_'
+		}, strings.TrimSpace(strings.Trim(value, "?"))), "._")
+
+		if name == "" {
+			name = "unknown"
+		}
+		name += ".pgn"
+
+		if !containsString(names, name) {
+			names = append(names, name)
+		}
+	}
+
+	return names
+}
+
+func containsString(strs []string, str string) bool {
+	for _, other := range strs {
+		if other == str {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Splitting by player can make more files than may be open at once so once
+// maxOpenSplitFiles are open they're all closed, to be reopened for
+// appending as more of their games come along
+const maxOpenSplitFiles = 128
+
+type splitFiles struct {
+	dir     string
+	open    map[string]*splitFile
+	created map[string]bool
+}
+
+type splitFile struct {
+	file   *os.File
+	writer *bufio.Writer
+}
+
+func newSplitFiles(dir string) *splitFiles {
+	return &splitFiles{dir: dir, open: map[string]*splitFile{}, created: map[string]bool{}}
+}
+
+func (s *splitFiles) write(name string, pgn pawn.PGN) error {
+	file, open := s.open[name]
+
+	if !open {
+		if len(s.open) >= maxOpenSplitFiles {
+			if err := s.close(); err != nil {
+				return err
+			}
+		}
+
+		flags := os.O_WRONLY | os.O_CREATE | os.O_TRUNC
+		if s.created[name] {
+			flags = os.O_WRONLY | os.O_APPEND
+		}
+
+		opened, err := os.OpenFile(filepath.Join(s.dir, name), flags, 0644)
+		if err != nil {
+			return err
+		}
+
+		file = &splitFile{file: opened, writer: bufio.NewWriter(opened)}
+		s.open[name] = file
+		s.created[name] = true
+	}
+
+	_, err := fmt.Fprintln(file.writer, pgn)
+
+	return err
+}
+
+// Closes the open files, returning the first error flushing or closing them
+func (s *splitFiles) close() error {
+	var first error
+
+	for name, file := range s.open {
+		err := file.writer.Flush()
+		if closeErr := file.file.Close(); err == nil {
+			err = closeErr
+		}
+
+		if err != nil && first == nil {
+			first = err
+		}
+		delete(s.open, name)
+	}
+
+	return first
+}
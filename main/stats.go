@@ -0,0 +1,153 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+
+	"github.com/marcel/pawn"
+)
+
+// Breakdowns of a player's score that can be written as CSV
+var statsBreakdowns = map[string]func(pawn.PlayerStats) map[string]*pawn.Score{
+	"eco":          func(stats pawn.PlayerStats) map[string]*pawn.Score { return stats.Openings },
+	"opponent":     func(stats pawn.PlayerStats) map[string]*pawn.Score { return stats.Opponents },
+	"time-control": func(stats pawn.PlayerStats) map[string]*pawn.Score { return stats.TimeControls },
+}
+
+// Writes statistics for one player, or every player, in one or more PGN
+// files to stdout as JSON or CSV
+func stats(args []string) int {
+	flags := flag.NewFlagSet("stats", flag.ExitOnError)
+	player := flags.String("player", "", "report on the player with `name` rather than everyone")
+	format := flags.String("format", "json", "write `json` or csv")
+	by := flags.String("by", "", "as CSV, break scores down by `eco`, opponent or time-control")
+	flags.Usage = func() {
+		fmt.Fprintln(os.Stderr, "usage: pawn stats [-player name] [-format json|csv] [-by eco|opponent|time-control] file.pgn[.gz] ...")
+		flags.PrintDefaults()
+	}
+	flags.Parse(args)
+
+	breakdown, knownBreakdown := statsBreakdowns[*by]
+	if flags.NArg() == 0 || (*format != "json" && *format != "csv") || (*by != "" && !knownBreakdown) {
+		flags.Usage()
+		return 2
+	}
+
+	pgns := []pawn.PGN{}
+	for _, path := range flags.Args() {
+		parsed, err := parsePGNFile(path)
+		if err != nil {
+			return fail(err)
+		}
+		pgns = append(pgns, parsed...)
+	}
+
+	var players []pawn.PlayerStats
+	if *player != "" {
+		players = []pawn.PlayerStats{pawn.PlayerStatistics(pgns, *player)}
+	} else {
+		players = pawn.AllPlayerStatistics(pgns)
+	}
+
+	var err error
+	switch {
+	case *format == "json":
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		err = encoder.Encode(players)
+	case *by != "":
+		err = writeBreakdownCSV(os.Stdout, players, *by, breakdown)
+	default:
+		err = writeStatsCSV(os.Stdout, players)
+	}
+
+	if err != nil {
+		return fail(err)
+	}
+
+	return 0
+}
+
+func percentage(score pawn.Score) string {
+	return strconv.FormatFloat(score.Percentage(), 'f', 1, 64)
+}
+
+// A row per player summarising their record
+func writeStatsCSV(w io.Writer, players []pawn.PlayerStats) error {
+	out := csv.NewWriter(w)
+	out.Write([]string{
+		"player", "games", "wins", "draws", "losses", "percentage",
+		"white_games", "white_percentage", "black_games", "black_percentage",
+		"performance", "average_moves",
+		"winning_streak", "unbeaten_streak", "losing_streak", "winless_streak",
+	})
+
+	for _, player := range players {
+		out.Write([]string{
+			player.Name,
+			strconv.Itoa(player.Score.Games),
+			strconv.Itoa(player.Score.Wins),
+			strconv.Itoa(player.Score.Draws),
+			strconv.Itoa(player.Score.Losses),
+			percentage(player.Score),
+			strconv.Itoa(player.White.Games),
+			percentage(player.White),
+			strconv.Itoa(player.Black.Games),
+			percentage(player.Black),
+			strconv.Itoa(player.Performance),
+			strconv.FormatFloat(player.AverageMoves, 'f', 1, 64),
+			strconv.Itoa(player.Streaks.Winning),
+			strconv.Itoa(player.Streaks.Unbeaten),
+			strconv.Itoa(player.Streaks.Losing),
+			strconv.Itoa(player.Streaks.Winless),
+		})
+	}
+
+	out.Flush()
+
+	return out.Error()
+}
+
+// A row per player and ECO code, opponent or time control, most games first
+func writeBreakdownCSV(w io.Writer, players []pawn.PlayerStats, by string, breakdown func(pawn.PlayerStats) map[string]*pawn.Score) error {
+	out := csv.NewWriter(w)
+	out.Write([]string{"player", by, "games", "wins", "draws", "losses", "percentage"})
+
+	for _, player := range players {
+		scores := breakdown(player)
+
+		keys := []string{}
+		for key := range scores {
+			keys = append(keys, key)
+		}
+		sort.Slice(keys, func(i, j int) bool {
+			if scores[keys[i]].Games != scores[keys[j]].Games {
+				return scores[keys[i]].Games > scores[keys[j]].Games
+			}
+			return keys[i] < keys[j]
+		})
+
+		for _, key := range keys {
+			score := scores[key]
+			out.Write([]string{
+				player.Name,
+				key,
+				strconv.Itoa(score.Games),
+				strconv.Itoa(score.Wins),
+				strconv.Itoa(score.Draws),
+				strconv.Itoa(score.Losses),
+				percentage(*score),
+			})
+		}
+	}
+
+	out.Flush()
+
+	return out.Error()
+}
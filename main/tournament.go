@@ -0,0 +1,263 @@
+package main
+
+import (
+	"bufio"
+	"encoding/csv"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/marcel/pawn"
+)
+
+const tournamentUsage = `usage: pawn tournament new [-format swiss|round-robin] [-rounds n] [-name name] tournament.json player[=rating] ...
+       pawn tournament pair tournament.json
+       pawn tournament result tournament.json round board 1-0|0-1|1/2-1/2
+       pawn tournament import tournament.json games.pgn[.gz]
+       pawn tournament standings [-format text|html|csv] tournament.json
+       pawn tournament pgn tournament.json`
+
+var tournamentCommands = map[string]func(args []string) int{
+	"new":       newTournament,
+	"pair":      pairTournament,
+	"result":    recordTournamentResult,
+	"import":    importTournamentGames,
+	"standings": tournamentStandings,
+	"pgn":       tournamentPGN,
+}
+
+// Runs a Swiss or round robin tournament kept in a JSON file: pairing each
+// round, recording results and reporting standings
+func tournament(args []string) int {
+	if len(args) == 0 || tournamentCommands[args[0]] == nil {
+		fmt.Fprintln(os.Stderr, tournamentUsage)
+		return 2
+	}
+
+	return tournamentCommands[args[0]](args[1:])
+}
+
+func tournamentFlags(name string) *flag.FlagSet {
+	flags := flag.NewFlagSet("tournament "+name, flag.ExitOnError)
+	flags.Usage = func() {
+		fmt.Fprintln(os.Stderr, tournamentUsage)
+		flags.PrintDefaults()
+	}
+
+	return flags
+}
+
+func newTournament(args []string) int {
+	flags := tournamentFlags("new")
+	format := flags.String("format", "swiss", "pair as a `swiss` or round-robin")
+	rounds := flags.Int("rounds", 0, "`n` rounds of a Swiss or cycles of a round robin (default 5 or 1)")
+	name := flags.String("name", "", "the event's `name` (default the file's)")
+	flags.Parse(args)
+
+	if flags.NArg() < 3 {
+		flags.Usage()
+		return 2
+	}
+
+	path := flags.Arg(0)
+	if _, err := os.Stat(path); err == nil {
+		return fail(fmt.Errorf("%s already exists", path))
+	}
+
+	players := []pawn.TournamentPlayer{}
+	for _, arg := range flags.Args()[1:] {
+		player := pawn.TournamentPlayer{Name: arg}
+
+		if name, rating, rated := strings.Cut(arg, "="); rated {
+			parsed, err := strconv.Atoi(rating)
+			if err != nil {
+				flags.Usage()
+				return 2
+			}
+			player = pawn.TournamentPlayer{Name: strings.TrimSpace(name), Rating: parsed}
+		}
+
+		players = append(players, player)
+	}
+
+	if *rounds == 0 {
+		*rounds = 5
+		if pawn.TournamentFormat(*format) == pawn.RoundRobin {
+			*rounds = 1
+		}
+	}
+
+	if *name == "" {
+		*name = strings.TrimSuffix(path, ".json")
+	}
+
+	created, err := pawn.NewTournament(*name, pawn.TournamentFormat(*format), players, *rounds)
+	if err != nil {
+		return fail(err)
+	}
+
+	if err := created.Save(path); err != nil {
+		return fail(err)
+	}
+
+	fmt.Printf("%s: %d players, %d rounds\n", created.Name, len(created.Players), created.Rounds)
+
+	return 0
+}
+
+func loadTournament(flags *flag.FlagSet, args []string, operands int) (*pawn.Tournament, bool) {
+	flags.Parse(args)
+
+	if flags.NArg() != operands {
+		flags.Usage()
+		return nil, false
+	}
+
+	loaded, err := pawn.LoadTournament(flags.Arg(0))
+	if err != nil {
+		fail(err)
+		return nil, false
+	}
+
+	return loaded, true
+}
+
+// Pairs the next round and lists its boards
+func pairTournament(args []string) int {
+	flags := tournamentFlags("pair")
+	current, ok := loadTournament(flags, args, 1)
+	if !ok {
+		return 2
+	}
+
+	pairings, err := current.PairNextRound()
+	if errors.Is(err, pawn.ErrorTournamentOver) {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	if err != nil {
+		return fail(err)
+	}
+
+	if err := current.Save(flags.Arg(0)); err != nil {
+		return fail(err)
+	}
+
+	fmt.Printf("Round %d\n", len(current.Played))
+	for board, pairing := range pairings {
+		if pairing.IsBye() {
+			fmt.Printf("%3d  %s  bye\n", board+1, current.Players[pairing.White].Name)
+			continue
+		}
+
+		fmt.Printf("%3d  %s - %s\n", board+1, current.Players[pairing.White].Name, current.Players[pairing.Black].Name)
+	}
+
+	return 0
+}
+
+func recordTournamentResult(args []string) int {
+	flags := tournamentFlags("result")
+	current, ok := loadTournament(flags, args, 4)
+	if !ok {
+		return 2
+	}
+
+	round, roundErr := strconv.Atoi(flags.Arg(1))
+	board, boardErr := strconv.Atoi(flags.Arg(2))
+	if roundErr != nil || boardErr != nil {
+		flags.Usage()
+		return 2
+	}
+
+	if err := current.RecordResult(round, board, pawn.Outcome(flags.Arg(3))); err != nil {
+		return fail(err)
+	}
+
+	if err := current.Save(flags.Arg(0)); err != nil {
+		return fail(err)
+	}
+
+	return 0
+}
+
+// Records the results and moves of played games, matched to their pairings
+// by Round, White and Black tags
+func importTournamentGames(args []string) int {
+	flags := tournamentFlags("import")
+	current, ok := loadTournament(flags, args, 2)
+	if !ok {
+		return 2
+	}
+
+	imported, skipped := 0, 0
+	err := eachPGN(flags.Arg(1), func(pgn pawn.PGN) {
+		if err := current.RecordGame(pgn); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			skipped++
+			return
+		}
+		imported++
+	})
+	if err != nil {
+		return fail(err)
+	}
+
+	if err := current.Save(flags.Arg(0)); err != nil {
+		return fail(err)
+	}
+
+	fmt.Fprintf(os.Stderr, "%d games imported, %d skipped\n", imported, skipped)
+
+	if skipped > 0 {
+		return 1
+	}
+	return 0
+}
+
+func tournamentStandings(args []string) int {
+	flags := tournamentFlags("standings")
+	format := flags.String("format", "text", "write `text`, html or csv")
+	current, ok := loadTournament(flags, args, 1)
+	if !ok || (*format != "text" && *format != "html" && *format != "csv") {
+		return 2
+	}
+
+	table := current.Crosstable()
+
+	switch *format {
+	case "html":
+		fmt.Print(table.HTML())
+	case "csv":
+		out := csv.NewWriter(os.Stdout)
+		out.WriteAll(table.Records())
+		if err := out.Error(); err != nil {
+			return fail(err)
+		}
+	default:
+		fmt.Print(table)
+	}
+
+	return 0
+}
+
+// Writes the games played so far as PGN
+func tournamentPGN(args []string) int {
+	flags := tournamentFlags("pgn")
+	current, ok := loadTournament(flags, args, 1)
+	if !ok {
+		return 2
+	}
+
+	out := bufio.NewWriter(os.Stdout)
+	defer out.Flush()
+
+	for _, pgn := range current.PGNs() {
+		fmt.Fprintln(out, pgn)
+	}
+
+	return 0
+}
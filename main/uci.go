@@ -0,0 +1,30 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/marcel/pawn/uci"
+)
+
+// Runs pawn's engine over the Universal Chess Interface on stdin and stdout,
+// for chess GUIs and match runners
+func uciEngine(args []string) int {
+	flags := flag.NewFlagSet("uci", flag.ExitOnError)
+	flags.Usage = func() {
+		fmt.Fprintln(os.Stderr, "usage: pawn uci")
+	}
+	flags.Parse(args)
+
+	if flags.NArg() != 0 {
+		flags.Usage()
+		return 2
+	}
+
+	if err := uci.NewServer(os.Stdin, os.Stdout).Run(); err != nil {
+		return fail(err)
+	}
+
+	return 0
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/marcel/pawn"
+)
+
+type validationReport struct {
+	File    string       `json:"file"`
+	Games   int          `json:"games"`
+	Invalid int          `json:"invalid"`
+	Reports []gameReport `json:"reports"`
+}
+
+type gameReport struct {
+	Game   int          `json:"game"` // Counted from 1 in file order
+	Event  string       `json:"event,omitempty"`
+	Round  string       `json:"round,omitempty"`
+	White  string       `json:"white,omitempty"`
+	Black  string       `json:"black,omitempty"`
+	Issues []pawn.Issue `json:"issues"`
+}
+
+// Replays every game in a PGN file writing a JSON report of the games with
+// issues to stdout. Exits 1 if any game has issues.
+func validate(args []string) int {
+	flags := flag.NewFlagSet("validate", flag.ExitOnError)
+	flags.Usage = func() {
+		fmt.Fprintln(os.Stderr, "usage: pawn validate file.pgn[.gz]")
+	}
+	flags.Parse(args)
+
+	if flags.NArg() != 1 {
+		flags.Usage()
+		return 2
+	}
+
+	pgns, err := parsePGNFile(flags.Arg(0))
+	if err != nil {
+		return fail(err)
+	}
+
+	report := validationReport{File: flags.Arg(0), Games: len(pgns), Reports: []gameReport{}}
+
+	for index, pgn := range pgns {
+		if issues := pawn.Validate(pgn); len(issues) > 0 {
+			report.Reports = append(report.Reports, gameReport{
+				Game:   index + 1,
+				Event:  pgn.Tags["Event"],
+				Round:  pgn.Tags["Round"],
+				White:  pgn.Tags["White"],
+				Black:  pgn.Tags["Black"],
+				Issues: issues,
+			})
+		}
+	}
+	report.Invalid = len(report.Reports)
+
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(report); err != nil {
+		return fail(err)
+	}
+
+	if report.Invalid > 0 {
+		return 1
+	}
+
+	return 0
+}
@@ -0,0 +1,30 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/marcel/pawn/xboard"
+)
+
+// Runs pawn's engine over the XBoard protocol on stdin and stdout, for
+// XBoard, WinBoard and other GUIs speaking CECP
+func xboardEngine(args []string) int {
+	flags := flag.NewFlagSet("xboard", flag.ExitOnError)
+	flags.Usage = func() {
+		fmt.Fprintln(os.Stderr, "usage: pawn xboard")
+	}
+	flags.Parse(args)
+
+	if flags.NArg() != 0 {
+		flags.Usage()
+		return 2
+	}
+
+	if err := xboard.NewServer(os.Stdin, os.Stdout).Run(); err != nil {
+		return fail(err)
+	}
+
+	return 0
+}
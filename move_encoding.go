@@ -0,0 +1,198 @@
+package pawn
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// A move packed into 16 bits: the destination square in bits 0-5, the origin
+// in bits 6-11, the promotion piece in bits 12-13 and the kind of move in
+// bits 14-15. Squares are numbered a1, b1 ... h8 from 0 to 63. The zero value
+// is no move.
+type EncodedMove uint16
+
+const (
+	normalMove EncodedMove = iota << 14
+	promotionMove
+	enPassantMove
+	castlingMove
+)
+
+const (
+	encodedSquareMask = 0x3f
+	encodedKindMask   = 3 << 14
+)
+
+var encodedPromotions = [...]Material{Knight, Bishop, Rook, Queen}
+
+func squareNumber(position Position) int {
+	return 8*(int(position.Rank)-1) + position.File.index()
+}
+
+func positionFromSquareNumber(number int) Position {
+	return Position{allFiles[number%8], allRanks[number/8]}
+}
+
+// Packs a move played from the board's position
+func (b Board) EncodeMove(move Move) EncodedMove {
+	encoded := EncodedMove(squareNumber(move.From)<<6 | squareNumber(move.To))
+
+	switch {
+	case move.Promotion != 0:
+		for index, material := range encodedPromotions {
+			if material == move.Promotion {
+				encoded |= promotionMove | EncodedMove(index)<<12
+			}
+		}
+	case move.Material == King && move.From.File == E && (move.To.File == G || move.To.File == C):
+		encoded |= castlingMove
+	case move.Material == Pawn && move.To == b.enPassant && move.From.File != move.To.File:
+		encoded |= enPassantMove
+	}
+
+	return encoded
+}
+
+// Unpacks a move to be played from the board's position. Only checks that
+// the side to move has a piece on the origin square; use IsLegal to check
+// a move from an untrusted source.
+func (b Board) DecodeMove(encoded EncodedMove) (Move, error) {
+	from, to := encoded.From(), encoded.To()
+	piece := b.SquareAtPosition(from).Piece
+
+	if encoded == 0 || piece.Color != b.SideToMove() {
+		return Move{}, fmt.Errorf("%w: %s", ErrorIllegalMove, encoded)
+	}
+
+	move := Move{
+		Piece:     piece,
+		From:      from,
+		To:        to,
+		Takes:     b.SquareAtPosition(to).Piece != NoPiece || encoded&encodedKindMask == enPassantMove,
+		Promotion: encoded.Promotion(),
+	}
+
+	return move, nil
+}
+
+func (m EncodedMove) From() Position {
+	return positionFromSquareNumber(int(m>>6) & encodedSquareMask)
+}
+
+func (m EncodedMove) To() Position {
+	return positionFromSquareNumber(int(m) & encodedSquareMask)
+}
+
+// Finds the legal move written in long algebraic notation as UCI uses, e.g.
+// e2e4, e1g1 to castle or e7e8q to promote
+func (b *Board) ResolveLongAlgebraic(text string) (Move, error) {
+	for _, move := range b.LegalMoves() {
+		if b.EncodeMove(move).String() == text {
+			return move, nil
+		}
+	}
+
+	return Move{}, fmt.Errorf("%w: %s", ErrorIllegalMove, text)
+}
+
+// The piece a pawn promotes to or zero if the move isn't a promotion
+func (m EncodedMove) Promotion() Material {
+	if m&encodedKindMask != promotionMove {
+		return 0
+	}
+
+	return encodedPromotions[m>>12&3]
+}
+
+// In long algebraic notation as UCI uses, e.g. e2e4 or e7e8q
+func (m EncodedMove) String() string {
+	if m == 0 {
+		return "0000"
+	}
+
+	str := m.From().AN() + m.To().AN()
+	if promotion := m.Promotion(); promotion != 0 {
+		str += strings.ToLower(promotion.AN())
+	}
+
+	return str
+}
+
+// Encodes a game played from the initial position as one byte per move, the
+// move's index among the legal moves of the position it was played from
+// sorted by sortedLegalMoves. Fails on the first illegal move.
+func EncodeMoves(moves []Move) ([]byte, error) {
+	encoded := make([]byte, 0, len(moves))
+	board := NewBoard()
+
+	for ply, move := range moves {
+		index := legalMoveIndex(board, move)
+		if index < 0 {
+			return nil, fmt.Errorf("%w: %s at ply %d", ErrorIllegalMove, board.EncodeMove(move), ply+1)
+		}
+
+		encoded = append(encoded, byte(index))
+		board.MakeMove(move)
+	}
+
+	return encoded, nil
+}
+
+// Decodes moves encoded by EncodeMoves
+func DecodeMoves(encoded []byte) ([]Move, error) {
+	moves, _, err := decodeMoves(encoded)
+
+	return moves, err
+}
+
+// Decodes moves, also returning the board they were played on
+func decodeMoves(encoded []byte) ([]Move, *Board, error) {
+	moves := make([]Move, 0, len(encoded))
+	board := NewBoard()
+
+	for ply, index := range encoded {
+		legal := sortedLegalMoves(board)
+		if int(index) >= len(legal) {
+			return nil, nil, fmt.Errorf("%w: index %d at ply %d", ErrorIllegalMove, index, ply+1)
+		}
+
+		board.MakeMove(legal[index])
+		moves = append(moves, legal[index])
+	}
+
+	return moves, board, nil
+}
+
+// The legal moves of the position in a fixed order, by origin square,
+// destination square and promotion, so indexes into it don't depend on how
+// moves happen to be generated
+func sortedLegalMoves(board *Board) []Move {
+	moves := board.LegalMoves()
+
+	sort.Slice(moves, func(i, j int) bool {
+		if from, other := moves[i].From.index(), moves[j].From.index(); from != other {
+			return from < other
+		}
+
+		if to, other := moves[i].To.index(), moves[j].To.index(); to != other {
+			return to < other
+		}
+
+		return moves[i].Promotion < moves[j].Promotion
+	})
+
+	return moves
+}
+
+// No position has more than 218 legal moves so the index fits in a byte.
+// Returns -1 for a move that isn't legal.
+func legalMoveIndex(board *Board, move Move) int {
+	for index, legal := range sortedLegalMoves(board) {
+		if legal.From == move.From && legal.To == move.To && legal.Promotion == move.Promotion {
+			return index
+		}
+	}
+
+	return -1
+}
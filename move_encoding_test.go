@@ -0,0 +1,126 @@
+package pawn
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type MoveEncodingTestSuite struct {
+	suite.Suite
+}
+
+func TestMoveEncodingTestSuite(t *testing.T) {
+	suite.Run(t, new(MoveEncodingTestSuite))
+}
+
+func (s *MoveEncodingTestSuite) TestEncodeMove() {
+	board := NewBoard()
+	move, _ := board.ResolveAlgebraic("e4")
+	encoded := board.EncodeMove(move)
+
+	s.Equal(EncodedMove(12<<6|28), encoded)
+	s.Equal(E2, encoded.From())
+	s.Equal(E4, encoded.To())
+	s.Equal("e2e4", encoded.String())
+	s.Equal("0000", EncodedMove(0).String())
+}
+
+func (s *MoveEncodingTestSuite) TestResolveLongAlgebraic() {
+	board, _ := ParseFEN("r3k2r/pPp2ppp/8/3pP3/8/8/P1PP1PPP/R3K2R w KQkq d6 0 1")
+
+	for text, expected := range map[string]Move{
+		"e1g1":  {Piece{White, King}, E1, G1, false, 0},
+		"e5d6":  {Piece{White, Pawn}, E5, D6, true, 0},
+		"b7a8n": {Piece{White, Pawn}, B7, A8, true, Knight},
+	} {
+		move, err := board.ResolveLongAlgebraic(text)
+		s.Nil(err)
+		s.Equal(expected, move)
+	}
+
+	for _, text := range []string{"e1e3", "b7b8", "e2e4", ""} {
+		_, err := board.ResolveLongAlgebraic(text)
+		s.True(errors.Is(err, ErrorIllegalMove), text)
+	}
+}
+
+// Every legal move along a game with castling, en passant and promotions
+// survives a round trip
+func (s *MoveEncodingTestSuite) TestRoundTrip() {
+	board, err := ParseFEN("r3k2r/pPp2ppp/8/3pP3/8/8/P1PP1PPP/R3K2R w KQkq d6 0 1")
+	s.Require().Nil(err)
+
+	kinds := map[EncodedMove]int{}
+	for _, move := range board.LegalMoves() {
+		encoded := board.EncodeMove(move)
+		kinds[encoded&encodedKindMask]++
+
+		decoded, err := board.DecodeMove(encoded)
+		s.Nil(err)
+		s.Equal(move, decoded, encoded.String())
+	}
+
+	s.Equal(2, kinds[castlingMove])
+	s.Equal(1, kinds[enPassantMove])
+	s.Equal(8, kinds[promotionMove], "b8 and bxa8 each four ways")
+}
+
+func (s *MoveEncodingTestSuite) TestPromotion() {
+	board, _ := ParseFEN("8/4P3/8/8/8/8/k7/4K3 w - - 0 1")
+	move, _ := board.ResolveAlgebraic("e8=N")
+
+	encoded := board.EncodeMove(move)
+	s.Equal(Knight, encoded.Promotion())
+	s.Equal("e7e8n", encoded.String())
+}
+
+func (s *MoveEncodingTestSuite) TestDecodeMoveErrors() {
+	board := NewBoard()
+
+	_, err := board.DecodeMove(0)
+	s.True(errors.Is(err, ErrorIllegalMove))
+
+	// e7e5 with White to move
+	_, err = board.DecodeMove(EncodedMove(52<<6 | 36))
+	s.True(errors.Is(err, ErrorIllegalMove))
+}
+
+func (s *MoveEncodingTestSuite) TestEncodeMoves() {
+	game, err := ParsePGN(win).Game()
+	s.Require().Nil(err)
+
+	encoded, err := EncodeMoves(game.Moves)
+	s.Nil(err)
+	s.Equal(len(game.Moves), len(encoded))
+
+	decoded, err := DecodeMoves(encoded)
+	s.Nil(err)
+	s.Equal(game.Moves, decoded)
+
+	// 1.a3 is the first of White's moves in square order
+	s.Equal([]byte{0}, mustEncode(s, []AlgebraicNotation{"a3"}))
+
+	_, err = DecodeMoves([]byte{20})
+	s.True(errors.Is(err, ErrorIllegalMove))
+
+	_, err = EncodeMoves([]Move{{Piece{White, King}, E1, E2, false, 0}})
+	s.True(errors.Is(err, ErrorIllegalMove))
+}
+
+func mustEncode(s *MoveEncodingTestSuite, moves []AlgebraicNotation) []byte {
+	board := NewBoard()
+	played := []Move{}
+
+	for _, an := range moves {
+		move, err := board.MoveFromAlgebraic(an)
+		s.Require().Nil(err)
+		played = append(played, move)
+	}
+
+	encoded, err := EncodeMoves(played)
+	s.Require().Nil(err)
+
+	return encoded
+}
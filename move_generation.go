@@ -0,0 +1,711 @@
+package pawn
+
+import (
+	"errors"
+	"strconv"
+)
+
+var (
+	ErrorIllegalMove      = errors.New("pawn: illegal move")
+	ErrorAmbiguousMove    = errors.New("pawn: ambiguous move")
+	ErrorInvalidNotation  = errors.New("pawn: invalid algebraic notation")
+	ErrorNoMoveToTakeBack = errors.New("pawn: no move to take back")
+)
+
+// Squares are addressed by their index into Board.Squares, i.e. file major
+// from a1, a2 ... h7, h8
+func (p Position) index() int {
+	return p.File.index()*len(allRanks) + int(p.Rank) - 1
+}
+
+func positionAt(index int) Position {
+	return Position{allFiles[index/len(allRanks)], allRanks[index%len(allRanks)]}
+}
+
+// Precomputed destinations from every square so move generation doesn't have
+// to rebuild Paths for every piece on every turn
+var (
+	rays        [64][8][]int // Indexed by Direction, nearest square first
+	knightJumps [64][]int
+	kingSteps   [64][]int
+)
+
+var orthogonals = [...]Direction{Up, Down, Left, Right}
+var diagonals = [...]Direction{UpLeftDiagonal, UpRightDiagonal, DownLeftDiagonal, DownRightDiagonal}
+
+func init() {
+	for _, file := range allFiles {
+		for _, rank := range allRanks {
+			position := Position{file, rank}
+			index := position.index()
+
+			for direction := Up; direction <= DownRightDiagonal; direction++ {
+				rays[index][direction] = indices(position.Path(direction))
+			}
+
+			// Path(Left) runs from the a file towards position
+			left := rays[index][Left]
+			for lhs, rhs := 0, len(left)-1; lhs < rhs; lhs, rhs = lhs+1, rhs-1 {
+				left[lhs], left[rhs] = left[rhs], left[lhs]
+			}
+
+			knight := Square{position, Piece{White, Knight}}
+			for _, path := range knight.possiblePaths() {
+				knightJumps[index] = append(knightJumps[index], path[0].index())
+			}
+
+			king := Square{position, Piece{White, King}}
+			for _, path := range king.possiblePaths() {
+				kingSteps[index] = append(kingSteps[index], path[0].index())
+			}
+		}
+	}
+}
+
+func indices(path Path) []int {
+	indices := make([]int, len(path))
+
+	for i, position := range path {
+		indices[i] = position.index()
+	}
+
+	return indices
+}
+
+type castlingRights uint8
+
+const (
+	whiteKingSide castlingRights = 1 << iota
+	whiteQueenSide
+	blackKingSide
+	blackQueenSide
+)
+
+const allCastlingRights = whiteKingSide | whiteQueenSide | blackKingSide | blackQueenSide
+
+// Castling rights lost when a piece moves from or to each of these squares
+var castlingRightsLost = map[Position]castlingRights{
+	E1: whiteKingSide | whiteQueenSide,
+	H1: whiteKingSide,
+	A1: whiteQueenSide,
+	E8: blackKingSide | blackQueenSide,
+	H8: blackKingSide,
+	A8: blackQueenSide,
+}
+
+var promotions = [...]Material{Queen, Rook, Bishop, Knight}
+
+func (c Color) Opponent() Color {
+	if c == White {
+		return Black
+	}
+
+	return White
+}
+
+func (c Color) forward() Direction {
+	if c == White {
+		return Up
+	}
+
+	return Down
+}
+
+func (c Color) pawnCaptureDirections() [2]Direction {
+	if c == White {
+		return [2]Direction{UpLeftDiagonal, UpRightDiagonal}
+	}
+
+	return [2]Direction{DownLeftDiagonal, DownRightDiagonal}
+}
+
+func (c Color) homeRank() Rank {
+	if c == White {
+		return 1
+	}
+
+	return 8
+}
+
+func (c Color) pawnRank() Rank {
+	if c == White {
+		return 2
+	}
+
+	return 7
+}
+
+// Everything needed to take a move back
+type undo struct {
+	null          bool // A pass made with MakeNullMove
+	move          Move
+	captured      Piece
+	capturedAt    int
+	castling      castlingRights
+	enPassant     Position
+	halfmoveClock int
+	hash          uint64
+}
+
+func (b Board) SideToMove() Color {
+	return b.turnToMove()
+}
+
+// Reports whether the square at position is attacked by any piece of color
+func (b Board) IsAttacked(position Position, by Color) bool {
+	return b.attacked(position.index(), by)
+}
+
+func (b Board) attacked(target int, by Color) bool {
+	pawn := Piece{by, Pawn}
+	for _, direction := range by.Opponent().pawnCaptureDirections() {
+		if ray := rays[target][direction]; len(ray) > 0 && b.Squares[ray[0]].Piece == pawn {
+			return true
+		}
+	}
+
+	knight := Piece{by, Knight}
+	for _, index := range knightJumps[target] {
+		if b.Squares[index].Piece == knight {
+			return true
+		}
+	}
+
+	king := Piece{by, King}
+	for _, index := range kingSteps[target] {
+		if b.Squares[index].Piece == king {
+			return true
+		}
+	}
+
+	return b.slidingAttack(target, by, orthogonals[:], Rook) ||
+		b.slidingAttack(target, by, diagonals[:], Bishop)
+}
+
+func (b Board) slidingAttack(target int, by Color, directions []Direction, slider Material) bool {
+	for _, direction := range directions {
+		for _, index := range rays[target][direction] {
+			piece := b.Squares[index].Piece
+			if piece == NoPiece {
+				continue
+			}
+
+			if piece.Color == by && (piece.Material == slider || piece.Material == Queen) {
+				return true
+			}
+
+			break
+		}
+	}
+
+	return false
+}
+
+func (b Board) kingIndex(color Color) int {
+	king := Piece{color, King}
+	for index, square := range b.Squares {
+		if square.Piece == king {
+			return index
+		}
+	}
+
+	return -1
+}
+
+// Reports whether the side to move is in check
+func (b Board) InCheck() bool {
+	color := b.turnToMove()
+	if king := b.kingIndex(color); king != -1 {
+		return b.attacked(king, color.Opponent())
+	}
+
+	return false
+}
+
+func (b *Board) IsCheckmate() bool {
+	return b.InCheck() && len(b.LegalMoves()) == 0
+}
+
+func (b *Board) IsStalemate() bool {
+	return !b.InCheck() && len(b.LegalMoves()) == 0
+}
+
+// Moves since the last capture or pawn advance, the game being drawn at 100
+// under the fifty move rule
+func (b Board) HalfmoveClock() int {
+	return b.halfmoveClock
+}
+
+// Reports whether the position has occurred before with the same side to
+// move, looking back no further than the last capture or pawn advance
+func (b Board) IsRepetition() bool {
+	for back := 2; back <= b.halfmoveClock && back <= len(b.history); back += 2 {
+		if b.history[len(b.history)-back].hash == b.hash {
+			return true
+		}
+	}
+
+	return false
+}
+
+// The number of ways play can go on for depth plies, which is known for
+// many positions and so checks move generation
+func (b *Board) Perft(depth int) int {
+	if depth == 0 {
+		return 1
+	}
+
+	moves := b.LegalMoves()
+	if depth == 1 {
+		return len(moves)
+	}
+
+	nodes := 0
+	for _, move := range moves {
+		b.MakeMove(move)
+		nodes += b.Perft(depth - 1)
+		b.UnmakeMove()
+	}
+
+	return nodes
+}
+
+// All moves available to the side to move that don't leave its own king in
+// check
+func (b *Board) LegalMoves() []Move {
+	color := b.turnToMove()
+	pseudoLegalMoves := b.pseudoLegalMoves()
+	legalMoves := make([]Move, 0, len(pseudoLegalMoves))
+
+	for _, move := range pseudoLegalMoves {
+		b.MakeMove(move)
+		if king := b.kingIndex(color); king == -1 || !b.attacked(king, color.Opponent()) {
+			legalMoves = append(legalMoves, move)
+		}
+		b.UnmakeMove()
+	}
+
+	return legalMoves
+}
+
+// Reports whether move is legal for the side to move
+func (b *Board) IsLegal(move Move) bool {
+	for _, legalMove := range b.LegalMoves() {
+		if legalMove == move {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Moves that follow each piece's movement rules but may leave the king in check
+func (b Board) pseudoLegalMoves() []Move {
+	color := b.turnToMove()
+	moves := make([]Move, 0, 48)
+
+	for from, square := range b.Squares {
+		if square.Piece == NoPiece || square.Color != color {
+			continue
+		}
+
+		switch square.Material {
+		case Pawn:
+			moves = b.appendPawnMoves(moves, from, square.Piece)
+		case Knight:
+			moves = b.appendSteps(moves, from, square.Piece, knightJumps[from])
+		case King:
+			moves = b.appendSteps(moves, from, square.Piece, kingSteps[from])
+			moves = b.appendCastles(moves, square.Piece)
+		case Rook:
+			moves = b.appendSlides(moves, from, square.Piece, orthogonals[:])
+		case Bishop:
+			moves = b.appendSlides(moves, from, square.Piece, diagonals[:])
+		case Queen:
+			moves = b.appendSlides(moves, from, square.Piece, orthogonals[:])
+			moves = b.appendSlides(moves, from, square.Piece, diagonals[:])
+		}
+	}
+
+	return moves
+}
+
+func (b Board) appendSteps(moves []Move, from int, piece Piece, destinations []int) []Move {
+	for _, to := range destinations {
+		target := b.Squares[to].Piece
+		if target == NoPiece || target.Color != piece.Color {
+			moves = append(moves, Move{Piece: piece, From: positionAt(from), To: positionAt(to), Takes: target != NoPiece})
+		}
+	}
+
+	return moves
+}
+
+func (b Board) appendSlides(moves []Move, from int, piece Piece, directions []Direction) []Move {
+	for _, direction := range directions {
+		for _, to := range rays[from][direction] {
+			target := b.Squares[to].Piece
+			if target != NoPiece && target.Color == piece.Color {
+				break
+			}
+
+			moves = append(moves, Move{Piece: piece, From: positionAt(from), To: positionAt(to), Takes: target != NoPiece})
+
+			if target != NoPiece {
+				break
+			}
+		}
+	}
+
+	return moves
+}
+
+func (b Board) appendPawnMoves(moves []Move, from int, piece Piece) []Move {
+	origin := positionAt(from)
+	forward := rays[from][piece.Color.forward()]
+
+	if len(forward) > 0 && b.Squares[forward[0]].Piece == NoPiece {
+		moves = appendPawnMove(moves, Move{Piece: piece, From: origin, To: positionAt(forward[0])})
+
+		if origin.Rank == piece.Color.pawnRank() && b.Squares[forward[1]].Piece == NoPiece {
+			moves = append(moves, Move{Piece: piece, From: origin, To: positionAt(forward[1])})
+		}
+	}
+
+	for _, direction := range piece.Color.pawnCaptureDirections() {
+		ray := rays[from][direction]
+		if len(ray) == 0 {
+			continue
+		}
+
+		to := positionAt(ray[0])
+		target := b.Squares[ray[0]].Piece
+		if (target != NoPiece && target.Color != piece.Color) || to == b.enPassant {
+			moves = appendPawnMove(moves, Move{Piece: piece, From: origin, To: to, Takes: true})
+		}
+	}
+
+	return moves
+}
+
+// Expands a pawn move reaching the last rank into each possible promotion
+func appendPawnMove(moves []Move, move Move) []Move {
+	if move.To.Rank != move.Color.Opponent().homeRank() {
+		return append(moves, move)
+	}
+
+	for _, material := range promotions {
+		move.Promotion = material
+		moves = append(moves, move)
+	}
+
+	return moves
+}
+
+func (b Board) appendCastles(moves []Move, king Piece) []Move {
+	rank := king.Color.homeRank()
+	kingFrom := Position{E, rank}
+
+	kingSide, queenSide := whiteKingSide, whiteQueenSide
+	if king.Color == Black {
+		kingSide, queenSide = blackKingSide, blackQueenSide
+	}
+
+	if b.castling&(kingSide|queenSide) == 0 || b.SquareAtPosition(kingFrom).Piece != king {
+		return moves
+	}
+
+	opponent := king.Color.Opponent()
+	if b.attacked(kingFrom.index(), opponent) {
+		return moves
+	}
+
+	castle := func(right castlingRights, rookFile File, empty []File, passing File, kingTo File) {
+		if b.castling&right == 0 || b.SquareAtPosition(Position{rookFile, rank}).Piece != (Piece{king.Color, Rook}) {
+			return
+		}
+
+		for _, file := range empty {
+			if b.SquareAtPosition(Position{file, rank}).Piece != NoPiece {
+				return
+			}
+		}
+
+		if b.attacked(Position{passing, rank}.index(), opponent) {
+			return
+		}
+
+		moves = append(moves, Move{Piece: king, From: kingFrom, To: Position{kingTo, rank}})
+	}
+
+	castle(kingSide, H, []File{F, G}, F, G)
+	castle(queenSide, A, []File{B, C, D}, D, C)
+
+	return moves
+}
+
+// Plays move without checking that it is legal, recording what is needed to
+// take it back with UnmakeMove
+func (b *Board) MakeMove(move Move) {
+	from, to := move.From.index(), move.To.index()
+	piece := b.Squares[from].Piece
+
+	state := undo{
+		move:          move,
+		capturedAt:    to,
+		castling:      b.castling,
+		enPassant:     b.enPassant,
+		halfmoveClock: b.halfmoveClock,
+		hash:          b.hash,
+	}
+
+	if piece.Material == Pawn && move.To == b.enPassant {
+		state.capturedAt = Position{move.To.File, move.From.Rank}.index()
+	}
+
+	// Before any piece moves, since whether the en passant square is hashed
+	// depends on where the pawns are
+	b.setEnPassant(Position{})
+
+	state.captured = b.Squares[state.capturedAt].Piece
+	b.setPiece(state.capturedAt, NoPiece)
+
+	b.setPiece(from, NoPiece)
+	if move.Promotion != 0 {
+		piece.Material = move.Promotion
+	}
+	b.setPiece(to, piece)
+
+	if piece.Material == King && move.From.File == E && (move.To.File == G || move.To.File == C) {
+		rookFrom, rookTo := Position{H, move.From.Rank}, Position{F, move.From.Rank}
+		if move.To.File == C {
+			rookFrom, rookTo = Position{A, move.From.Rank}, Position{D, move.From.Rank}
+		}
+
+		b.setPiece(rookFrom.index(), NoPiece)
+		b.setPiece(rookTo.index(), Piece{piece.Color, Rook})
+	}
+
+	b.setCastling(b.castling &^ (castlingRightsLost[move.From] | castlingRightsLost[move.To]))
+
+	if piece.Material == Pawn || state.captured != NoPiece {
+		b.halfmoveClock = 0
+	} else {
+		b.halfmoveClock++
+	}
+
+	b.history = append(b.history, state)
+	b.incrementTurnNumber()
+	b.hash ^= sideToMoveKey
+
+	if piece.Material == Pawn && move.From.File == move.To.File &&
+		(move.To.Rank == move.From.Rank+2 || move.From.Rank == move.To.Rank+2) {
+		b.setEnPassant(Position{move.From.File, (move.From.Rank + move.To.Rank) / 2})
+	}
+}
+
+// Passes the move to the other side, which the rules don't allow but a
+// search can use to see how much a move is worth to the side to move.
+// UnmakeMove takes it back.
+func (b *Board) MakeNullMove() {
+	b.history = append(b.history, undo{
+		null:          true,
+		castling:      b.castling,
+		enPassant:     b.enPassant,
+		halfmoveClock: b.halfmoveClock,
+		hash:          b.hash,
+	})
+
+	b.setEnPassant(Position{})
+
+	// Positions before the pass don't repeat after it
+	b.halfmoveClock = 0
+
+	b.incrementTurnNumber()
+	b.hash ^= sideToMoveKey
+}
+
+// Takes back the last move played with MakeMove or MakeNullMove
+func (b *Board) UnmakeMove() error {
+	if len(b.history) == 0 {
+		return ErrorNoMoveToTakeBack
+	}
+
+	state := b.history[len(b.history)-1]
+	b.history = b.history[:len(b.history)-1]
+	move := state.move
+
+	if state.null {
+		b.enPassant, b.halfmoveClock, b.hash = state.enPassant, state.halfmoveClock, state.hash
+		b.turnNumber--
+
+		return nil
+	}
+
+	piece := b.Squares[move.To.index()].Piece
+	if move.Promotion != 0 {
+		piece.Material = Pawn
+	}
+
+	b.Squares[move.To.index()].Piece = NoPiece
+	b.Squares[move.From.index()].Piece = piece
+	if state.captured != NoPiece {
+		b.Squares[state.capturedAt].Piece = state.captured
+	}
+
+	if piece.Material == King && move.From.File == E && (move.To.File == G || move.To.File == C) {
+		rookFrom, rookTo := Position{H, move.From.Rank}, Position{F, move.From.Rank}
+		if move.To.File == C {
+			rookFrom, rookTo = Position{A, move.From.Rank}, Position{D, move.From.Rank}
+		}
+
+		b.Squares[rookTo.index()].Piece = NoPiece
+		b.Squares[rookFrom.index()].Piece = Piece{piece.Color, Rook}
+	}
+
+	b.castling = state.castling
+	b.enPassant = state.enPassant
+	b.halfmoveClock = state.halfmoveClock
+	b.hash = state.hash
+	b.turnNumber--
+
+	return nil
+}
+
+// Plays move if it is legal for the side to move
+func (b *Board) Play(move Move) error {
+	b.moveMutex.Lock()
+	defer b.moveMutex.Unlock()
+
+	if !b.IsLegal(move) {
+		return ErrorIllegalMove
+	}
+
+	b.MakeMove(move)
+
+	return nil
+}
+
+// Finds the legal move described by an without playing it
+func (b *Board) ResolveAlgebraic(an AlgebraicNotation) (Move, error) {
+	an = an.stripAnnotations()
+	color := b.turnToMove()
+
+	if an.IsCastle() {
+		kingTo := Position{G, color.homeRank()}
+		if an.IsCastleQueenSide() {
+			kingTo = Position{C, color.homeRank()}
+		}
+
+		for _, move := range b.LegalMoves() {
+			if move.Material == King && move.From == (Position{E, color.homeRank()}) && move.To == kingTo {
+				return move, nil
+			}
+		}
+
+		return Move{}, ErrorIllegalMove
+	}
+
+	if len(an) < 2 || an.destinationPosition() == "" {
+		return Move{}, ErrorInvalidNotation
+	}
+
+	material := an.Material()
+	destination := an.DestinationPosition()
+	originFile, originRank := an.OriginFile(), an.OriginRank()
+
+	var promotion Material
+	if an.IsPromotion() {
+		promotion = an.PromotedTo()
+		if promotion == 0 {
+			return Move{}, ErrorIllegalMove
+		}
+	}
+
+	candidates := []Move{}
+	for _, move := range b.LegalMoves() {
+		switch {
+		case move.Material != material, move.To != destination, move.Promotion != promotion:
+		case originFile != NilFile && move.From.File != originFile:
+		case originRank != NilRank && move.From.Rank != originRank:
+		default:
+			candidates = append(candidates, move)
+		}
+	}
+
+	switch len(candidates) {
+	case 0:
+		return Move{}, ErrorIllegalMove
+	case 1:
+		return candidates[0], nil
+	default:
+		return Move{}, ErrorAmbiguousMove
+	}
+}
+
+// The Standard Algebraic Notation for a legal move, including check and
+// checkmate suffixes
+func (b *Board) Algebraic(move Move) AlgebraicNotation {
+	var an string
+
+	switch {
+	case move.Material == King && move.From.File == E && move.To.File == G && move.From.Rank == move.To.Rank:
+		an = "O-O"
+	case move.Material == King && move.From.File == E && move.To.File == C && move.From.Rank == move.To.Rank:
+		an = "O-O-O"
+	case move.Material == Pawn:
+		if move.Takes {
+			an = string(move.From.File) + "x"
+		}
+		an += move.To.AN()
+		if move.Promotion != 0 {
+			an += "=" + move.Promotion.AN()
+		}
+	default:
+		an = move.Material.AN() + b.disambiguation(move)
+		if move.Takes {
+			an += "x"
+		}
+		an += move.To.AN()
+	}
+
+	b.MakeMove(move)
+	if b.InCheck() {
+		if len(b.LegalMoves()) == 0 {
+			an += "#"
+		} else {
+			an += "+"
+		}
+	}
+	b.UnmakeMove()
+
+	return AlgebraicNotation(an)
+}
+
+// The origin file, rank or both needed to tell move apart from other legal
+// moves of the same material to the same square. The file takes precedence
+// over the rank.
+func (b *Board) disambiguation(move Move) string {
+	ambiguous, sameFile, sameRank := false, false, false
+
+	for _, other := range b.LegalMoves() {
+		if other.Piece != move.Piece || other.To != move.To || other.From == move.From {
+			continue
+		}
+
+		ambiguous = true
+		sameFile = sameFile || other.From.File == move.From.File
+		sameRank = sameRank || other.From.Rank == move.From.Rank
+	}
+
+	switch {
+	case !ambiguous:
+		return ""
+	case !sameFile:
+		return string(move.From.File)
+	case !sameRank:
+		return strconv.Itoa(int(move.From.Rank))
+	default:
+		return move.From.AN()
+	}
+}
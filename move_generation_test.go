@@ -0,0 +1,209 @@
+package pawn
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type MoveGenerationTestSuite struct {
+	suite.Suite
+	board *Board
+}
+
+func TestMoveGenerationTestSuite(t *testing.T) {
+	suite.Run(t, new(MoveGenerationTestSuite))
+}
+
+func (s *MoveGenerationTestSuite) SetupTest() {
+	s.board = NewBoard()
+}
+
+func (s *MoveGenerationTestSuite) play(moves ...AlgebraicNotation) {
+	for _, an := range moves {
+		_, err := s.board.MoveFromAlgebraic(an)
+		s.Require().Nil(err, string(an))
+	}
+}
+
+func (s *MoveGenerationTestSuite) TestPerft() {
+	for depth, expected := range []int{1, 20, 400, 8902} {
+		s.Equal(expected, s.board.Perft(depth))
+	}
+
+	// Unmaking every move leaves the board as it started
+	s.Equal(NewBoard().Squares, s.board.Squares)
+	s.Equal(0, s.board.turnNumber)
+}
+
+func (s *MoveGenerationTestSuite) TestNullMove() {
+	s.play("e4")
+	fen, hash := s.board.FEN(), s.board.Hash()
+
+	s.board.MakeNullMove()
+	s.Equal(White, s.board.SideToMove())
+	s.Equal("rnbqkbnr/pppppppp/8/8/4P3/8/PPPP1PPP/RNBQKBNR w KQkq - 0 2", s.board.FEN(), "the en passant square lapses")
+	s.Equal(s.board.computeHash(), s.board.Hash())
+
+	s.play("d4")
+	s.board.UnmakeMove()
+	s.board.UnmakeMove()
+	s.Equal(fen, s.board.FEN())
+	s.Equal(hash, s.board.Hash())
+}
+
+func (s *MoveGenerationTestSuite) TestEnPassant() {
+	s.play("e4", "a6", "e5", "d5")
+
+	move, err := s.board.MoveFromAlgebraic("exd6")
+	s.Nil(err)
+	s.Equal(Move{Piece{White, Pawn}, E5, D6, true, 0}, move)
+	s.Equal(NoPiece, s.board.SquareAtPosition(D5).Piece)
+
+	s.board.UnmakeMove()
+	s.Equal(Piece{Black, Pawn}, s.board.SquareAtPosition(D5).Piece)
+	s.Equal(Piece{White, Pawn}, s.board.SquareAtPosition(E5).Piece)
+
+	// The right to capture en passant lapses after one move
+	s.play("a3", "a5")
+	_, err = s.board.MoveFromAlgebraic("exd6")
+	s.Equal(ErrorIllegalMove, err)
+}
+
+func (s *MoveGenerationTestSuite) TestCastling() {
+	s.play("e4", "e5", "Nf3", "Nc6", "Bc4", "Bc5")
+	s.True(s.board.IsLegal(Move{Piece: Piece{White, King}, From: E1, To: G1}))
+
+	s.play("Ke2", "Nf6", "Ke1", "d6")
+
+	// Moving the king forfeits castling even after it returns
+	_, err := s.board.MoveFromAlgebraic("O-O")
+	s.Equal(ErrorIllegalMove, err)
+
+	s.play("d3", "Bg4", "Nc3", "Qd7", "Be3", "O-O-O")
+	s.Equal(Piece{Black, King}, s.board.SquareAtPosition(C8).Piece)
+	s.Equal(Piece{Black, Rook}, s.board.SquareAtPosition(D8).Piece)
+}
+
+func (s *MoveGenerationTestSuite) TestCastlingThroughCheck() {
+	s.play("e4", "e5", "Nf3", "Nc6", "Bc4", "Bc5", "d3", "Nf6", "Bg5", "d6", "Nbd2", "Bxf2+")
+	s.True(s.board.InCheck())
+
+	_, err := s.board.MoveFromAlgebraic("O-O")
+	s.Equal(ErrorIllegalMove, err)
+
+	s.play("Kxf2", "h6", "Rf1", "Bg4", "Kg1", "Qe7", "Bh4", "O-O")
+}
+
+func (s *MoveGenerationTestSuite) TestPromotion() {
+	s.play("h4", "g5", "hxg5", "Nf6", "gxf6", "Rg8", "fxe7", "Rh8")
+
+	moves := []Move{}
+	for _, move := range s.board.LegalMoves() {
+		if move.From == E7 {
+			moves = append(moves, move)
+		}
+	}
+	s.Equal(8, len(moves))
+
+	// The piece to promote to is missing
+	_, err := s.board.ResolveAlgebraic("exf8=")
+	s.Equal(ErrorIllegalMove, err)
+
+	move, err := s.board.MoveFromAlgebraic("exf8=N")
+	s.Nil(err)
+	s.Equal(Move{Piece{White, Pawn}, E7, F8, true, Knight}, move)
+	s.Equal(Piece{White, Knight}, s.board.SquareAtPosition(F8).Piece)
+
+	s.board.UnmakeMove()
+	s.Equal(Piece{White, Pawn}, s.board.SquareAtPosition(E7).Piece)
+	s.Equal(Piece{Black, Bishop}, s.board.SquareAtPosition(F8).Piece)
+}
+
+func (s *MoveGenerationTestSuite) TestPinnedPiece() {
+	s.play("d4", "e6", "Nc3", "Bb4")
+
+	_, err := s.board.MoveFromAlgebraic("Nd5")
+	s.Equal(ErrorIllegalMove, err)
+
+	s.board = NewBoard()
+	s.play("d4", "e5", "c3", "Bb4")
+
+	_, err = s.board.MoveFromAlgebraic("c4")
+	s.Equal(ErrorIllegalMove, err)
+
+	// Capturing the pinning piece along the pin is allowed
+	s.play("cxb4")
+}
+
+func (s *MoveGenerationTestSuite) TestAmbiguousMove() {
+	s.play("Nf3", "d5", "Na3", "e5", "Nb5", "e4")
+
+	_, err := s.board.MoveFromAlgebraic("Nd4")
+	s.Equal(ErrorAmbiguousMove, err)
+
+	move, err := s.board.MoveFromAlgebraic("Nfd4")
+	s.Nil(err)
+	s.Equal(F3, move.From)
+}
+
+func (s *MoveGenerationTestSuite) TestAlgebraic() {
+	s.play("Nf3", "d5", "Na3", "e5", "Nb5", "e4")
+
+	expectations := map[Move]AlgebraicNotation{
+		Move{Piece{White, Knight}, F3, D4, false, 0}: "Nfd4",
+		Move{Piece{White, Knight}, B5, D4, false, 0}: "Nbd4",
+		Move{Piece{White, Knight}, B5, C7, true, 0}:  "Nxc7+",
+		Move{Piece{White, Pawn}, E2, E3, false, 0}:   "e3",
+		Move{Piece{White, Knight}, F3, E5, false, 0}: "Ne5",
+	}
+
+	for move, expected := range expectations {
+		s.Equal(expected, s.board.Algebraic(move))
+	}
+
+	s.board = NewBoard()
+	s.play("f3", "e5", "g4")
+	s.Equal(AlgebraicNotation("Qh4#"), s.board.Algebraic(Move{Piece: Piece{Black, Queen}, From: D8, To: H4}))
+
+	s.play("Qh4")
+	s.True(s.board.IsCheckmate())
+	s.False(s.board.IsStalemate())
+}
+
+func (s *MoveGenerationTestSuite) TestStalemate() {
+	// Sam Loyd's ten move stalemate
+	s.play("e3", "a5", "Qh5", "Ra6", "Qxa5", "h5", "h4", "Rah6", "Qxc7", "f6",
+		"Qxd7+", "Kf7", "Qxb7", "Qd3", "Qxb8", "Qh7", "Qxc8", "Kg6", "Qe6")
+
+	s.True(s.board.IsStalemate())
+	s.False(s.board.InCheck())
+}
+
+func (s *MoveGenerationTestSuite) TestRepetition() {
+	s.play("Nf3", "Nf6", "Ng1")
+	s.False(s.board.IsRepetition())
+	s.Equal(3, s.board.HalfmoveClock())
+
+	s.play("Ng8")
+	s.True(s.board.IsRepetition())
+
+	s.play("e4", "e5", "Nf3", "Nf6", "Ng1", "Ng8")
+	s.True(s.board.IsRepetition())
+	s.Equal(4, s.board.HalfmoveClock())
+
+	s.board.UnmakeMove()
+	s.False(s.board.IsRepetition(), "the pawn moves can't be undone")
+}
+
+func (s *MoveGenerationTestSuite) TestUnmakeWithoutMoves() {
+	s.Equal(ErrorNoMoveToTakeBack, s.board.UnmakeMove())
+}
+
+func (s *MoveGenerationTestSuite) TestPlay() {
+	s.Equal(ErrorIllegalMove, s.board.Play(Move{Piece: Piece{White, Pawn}, From: E2, To: E5}))
+	s.Nil(s.board.Play(Move{Piece: Piece{White, Pawn}, From: E2, To: E4}))
+	s.Equal(Black, s.board.SideToMove())
+	s.True(s.board.IsAttacked(D5, White))
+	s.False(s.board.IsAttacked(E5, White))
+}
@@ -0,0 +1,297 @@
+package pawn
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Rounds in one cycle of a round robin; with an odd number of players each
+// sits one round out
+func bergerRounds(players int) int {
+	if players%2 == 1 {
+		return players
+	}
+
+	return players - 1
+}
+
+// Pairs a round robin round, counted from 0, from the Berger tables FIDE
+// publishes. Players are numbered from 1 in the tables; with an odd number a
+// dummy player n makes it even and whoever it's paired with sits out. Later
+// cycles repeat the first with colors reversed.
+func bergerRound(players, round int) []Pairing {
+	n := players + players%2
+	cycle, round := round/(n-1), round%(n-1)
+
+	// Player n meets a, who has White in odd numbered rounds. The other boards
+	// pair a+k with a-k, counting around 1 to n-1.
+	a := 1 + round*(n/2)%(n-1)
+	around := func(number int) int {
+		return (number-1+(n-1))%(n-1) + 1
+	}
+
+	boards := [][2]int{{a, n}}
+	if round%2 == 1 {
+		boards[0] = [2]int{n, a}
+	}
+	for k := 1; k < n/2; k++ {
+		boards = append(boards, [2]int{around(a + k), around(a - k)})
+	}
+
+	pairings := []Pairing{}
+	for _, board := range boards {
+		white, black := board[0]-1, board[1]-1
+		if white >= players || black >= players {
+			continue
+		}
+
+		if cycle%2 == 1 {
+			white, black = black, white
+		}
+		pairings = append(pairings, Pairing{White: white, Black: black})
+	}
+
+	return pairings
+}
+
+// A player's state for Swiss pairing
+type swissPlayer struct {
+	index     int // Into the tournament's Players, its seeding order
+	points    float64
+	opponents map[int]bool
+	colors    []Color // Of the games they've played, in order
+	hadBye    bool
+}
+
+// How much a player wants White, negative for Black. Three is absolute: a
+// player never has a color three times running nor three more games with one
+// color than the other. Two is strong, to even their colors up, and one is
+// mild, to alternate.
+func (p swissPlayer) colorPreference() int {
+	if len(p.colors) == 0 {
+		return 0
+	}
+
+	balance := 0
+	for _, color := range p.colors {
+		if color == White {
+			balance++
+		} else {
+			balance--
+		}
+	}
+
+	last := p.colors[len(p.colors)-1]
+	repeated := len(p.colors) >= 2 && p.colors[len(p.colors)-2] == last
+
+	switch {
+	case balance <= -2 || (repeated && last == Black):
+		return 3
+	case balance >= 2 || (repeated && last == White):
+		return -3
+	case balance == -1:
+		return 2
+	case balance == 1:
+		return -2
+	case last == Black:
+		return 1
+	default:
+		return -1
+	}
+}
+
+func (t *Tournament) swissPlayers() []*swissPlayer {
+	points := t.points()
+	players := make([]*swissPlayer, len(t.Players))
+
+	for index := range players {
+		players[index] = &swissPlayer{index: index, points: points[index], opponents: map[int]bool{}}
+	}
+
+	for _, pairings := range t.Played {
+		for _, pairing := range pairings {
+			if pairing.IsBye() {
+				players[pairing.White].hadBye = true
+				continue
+			}
+
+			white, black := players[pairing.White], players[pairing.Black]
+			white.opponents[black.index], black.opponents[white.index] = true, true
+			white.colors = append(white.colors, White)
+			black.colors = append(black.colors, Black)
+		}
+	}
+
+	return players
+}
+
+// Pairs a Swiss round along the lines of FIDE's Dutch system. Players are
+// ranked by points then seed and paired score group by score group, the top
+// half of each against the bottom half. Pairings that would repeat a game or
+// give two players with absolute color preferences the same color are
+// avoided by trying the bottom half in other orders and then by floating
+// players down to the next group. With an odd number of players the lowest
+// ranked who hasn't had a bye gets one.
+func (t *Tournament) pairSwiss() ([]Pairing, error) {
+	players := t.swissPlayers()
+
+	ranked := append([]*swissPlayer{}, players...)
+	sort.SliceStable(ranked, func(i, j int) bool {
+		return ranked[i].points > ranked[j].points
+	})
+
+	byes := []*swissPlayer{nil}
+	if len(ranked)%2 == 1 {
+		byes = []*swissPlayer{}
+		for i := len(ranked) - 1; i >= 0; i-- {
+			if !ranked[i].hadBye {
+				byes = append(byes, ranked[i])
+			}
+		}
+	}
+
+	for _, bye := range byes {
+		remaining := []*swissPlayer{}
+		for _, player := range ranked {
+			if player != bye {
+				remaining = append(remaining, player)
+			}
+		}
+
+		pairs, paired := pairBrackets(remaining, nil)
+		if !paired {
+			continue
+		}
+
+		pairings := []Pairing{}
+		for board, pair := range pairs {
+			pairings = append(pairings, allocateColors(pair[0], pair[1], board))
+		}
+		if bye != nil {
+			pairings = append(pairings, Pairing{White: bye.index, Black: Bye, Result: WhiteWin})
+		}
+
+		return pairings, nil
+	}
+
+	return nil, fmt.Errorf("%w: round %d", ErrorNoPairing, len(t.Played)+1)
+}
+
+func compatible(a, b *swissPlayer) bool {
+	if a.opponents[b.index] {
+		return false
+	}
+
+	aPreference, bPreference := a.colorPreference(), b.colorPreference()
+
+	return !(aPreference == 3 && bPreference == 3) && !(aPreference == -3 && bPreference == -3)
+}
+
+// Pairs the highest score group, with any players floated down from above
+// first, then the rest, backtracking until every player is paired
+func pairBrackets(players []*swissPlayer, floaters []*swissPlayer) ([][2]*swissPlayer, bool) {
+	if len(players) == 0 && len(floaters) == 0 {
+		return nil, true
+	}
+
+	end := 0
+	for end < len(players) && players[end].points == players[0].points {
+		end++
+	}
+
+	bracket := append(append([]*swissPlayer{}, floaters...), players[:end]...)
+	rest := players[end:]
+
+	// Pair as many as possible, fewer only if the rest can't then be paired
+	for pairs := len(bracket) / 2; pairs >= 0; pairs-- {
+		if len(rest) == 0 && pairs*2 != len(bracket) {
+			break
+		}
+
+		var found [][2]*swissPlayer
+		matched := pairHalves(bracket[:pairs], bracket[pairs:], nil, func(bracketPairs [][2]*swissPlayer, unpaired []*swissPlayer) bool {
+			restPairs, paired := pairBrackets(rest, unpaired)
+			if paired {
+				found = append(bracketPairs, restPairs...)
+			}
+			return paired
+		})
+
+		if matched {
+			return found, true
+		}
+	}
+
+	return nil, false
+}
+
+// Pairs each of top with a distinct player of bottom, trying bottom's players
+// in order and backtracking, until accept takes the pairs and those of
+// bottom left over
+func pairHalves(top, bottom []*swissPlayer, pairs [][2]*swissPlayer, accept func([][2]*swissPlayer, []*swissPlayer) bool) bool {
+	if len(top) == 0 {
+		return accept(pairs, bottom)
+	}
+
+	for i, candidate := range bottom {
+		if !compatible(top[0], candidate) {
+			continue
+		}
+
+		others := append(append([]*swissPlayer{}, bottom[:i]...), bottom[i+1:]...)
+		if pairHalves(top[1:], others, append(pairs, [2]*swissPlayer{top[0], candidate}), accept) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Gives both players their preferred colors if they differ, otherwise the
+// stronger preference wins or, if they're as strong, the higher ranked
+// player's. Without any preference the higher ranked player alternates
+// colors board by board.
+func allocateColors(higher, lower *swissPlayer, board int) Pairing {
+	white := func(first, second *swissPlayer) Pairing {
+		return Pairing{White: first.index, Black: second.index}
+	}
+
+	higherPreference, lowerPreference := higher.colorPreference(), lower.colorPreference()
+
+	switch {
+	case higherPreference == 0 && lowerPreference == 0:
+		if board%2 == 0 {
+			return white(higher, lower)
+		}
+		return white(lower, higher)
+	case higherPreference*lowerPreference < 0, lowerPreference == 0:
+		if higherPreference > 0 {
+			return white(higher, lower)
+		}
+		return white(lower, higher)
+	case higherPreference == 0:
+		if lowerPreference > 0 {
+			return white(lower, higher)
+		}
+		return white(higher, lower)
+	}
+
+	// Both want the same color
+	wants := higherPreference > 0
+	if abs(lowerPreference) > abs(higherPreference) {
+		wants = lowerPreference < 0
+	}
+
+	if wants {
+		return white(higher, lower)
+	}
+	return white(lower, higher)
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+
+	return n
+}
@@ -1,9 +1,11 @@
 package pawn
 
 import (
+	"errors"
 	"fmt"
 	"io"
 	"os"
+	"sort"
 	"strconv"
 	"strings"
 	"text/scanner"
@@ -15,15 +17,39 @@ const (
 	WhiteWin Outcome = "1-0"
 	BlackWin         = "0-1"
 	Draw             = "1/2-1/2"
+	Ongoing          = "*" // Game in progress, abandoned or result unknown
 )
 
+var ErrorResultMismatch = errors.New("pawn: result tag does not match game termination")
+
 type Tags map[string]string
 
+// The Seven Tag Roster in the order the PGN spec exports it
+var sevenTagRoster = []string{"Event", "Site", "Date", "Round", "White", "Black", "Result"}
+
+// Tags in export order: the Seven Tag Roster followed by any others
+// alphabetically
 func (t Tags) String() string {
 	str := ""
+	seen := map[string]bool{}
+
+	for _, tag := range sevenTagRoster {
+		if value, present := t[tag]; present {
+			str += fmt.Sprintf("[%s \"%s\"]\n", tag, value)
+			seen[tag] = true
+		}
+	}
+
+	others := []string{}
+	for tag := range t {
+		if !seen[tag] {
+			others = append(others, tag)
+		}
+	}
+	sort.Strings(others)
 
-	for tag, value := range t {
-		str += fmt.Sprintf("[%s \"%s\"]\n", tag, value)
+	for _, tag := range others {
+		str += fmt.Sprintf("[%s \"%s\"]\n", tag, t[tag])
 	}
 
 	return str
@@ -35,6 +61,21 @@ type PGN struct {
 	Outcome
 }
 
+// Cross-checks the game termination marker against the Result tag. Games
+// without a Result tag are not checked.
+func (p PGN) VerifyResult() error {
+	result, hasResult := p.Tags["Result"]
+	if !hasResult {
+		return nil
+	}
+
+	if Outcome(result) != p.Outcome {
+		return fmt.Errorf("%w: [Result \"%s\"] but movetext ends with \"%s\"", ErrorResultMismatch, result, p.Outcome)
+	}
+
+	return nil
+}
+
 func (p PGN) MatchUp() string {
 	return fmt.Sprintf("%s vs %s", p.playerPlaying(White).lastName, p.playerPlaying(Black).lastName)
 }
@@ -66,7 +107,8 @@ func (p PGN) playerPlaying(color Color) playerName {
 }
 
 type Movetext struct {
-	Moves []*MovetextMove
+	Moves   []*MovetextMove
+	Comment string // Comment before the first move
 }
 
 func (m Movetext) Turns() []AlgebraicNotation {
@@ -79,9 +121,11 @@ func (m Movetext) Turns() []AlgebraicNotation {
 }
 
 type MovetextMove struct {
-	Number    uint8
-	WhiteMove AlgebraicNotation
-	BlackMove AlgebraicNotation
+	Number       uint8
+	WhiteMove    AlgebraicNotation
+	BlackMove    AlgebraicNotation
+	WhiteComment string // Text of the {comments} following each move
+	BlackComment string
 }
 
 func (p PGN) updateLastMove(update func(*MovetextMove)) {
@@ -90,12 +134,61 @@ func (p PGN) updateLastMove(update func(*MovetextMove)) {
 	update(lastMove)
 }
 
+// Replays the movetext on a new Board returning the Game it describes. On an
+// illegal move the Game holds the moves up to it.
+func (p PGN) Game() (Game, error) {
+	board := NewBoard()
+	moves := []Move{}
+
+	for _, an := range p.Turns() {
+		if an == "" {
+			continue
+		}
+
+		move, err := board.MoveFromAlgebraic(an)
+		if err != nil {
+			return Game{Board: *board, Moves: moves}, fmt.Errorf("%w: %s", err, an)
+		}
+
+		moves = append(moves, move)
+	}
+
+	return Game{Board: *board, Moves: moves}, nil
+}
+
+// Movetext lines are wrapped at this many characters when exported
+const movetextLineLength = 80
+
 func (p PGN) String() string {
-	return fmt.Sprintf("%s\n%s %s\n", p.Tags, p.Movetext, p.Outcome)
+	lines := []string{}
+	line := ""
+
+	tokens := strings.Fields(p.Movetext.String())
+	if p.Outcome != "" {
+		tokens = append(tokens, string(p.Outcome))
+	}
+
+	for _, token := range tokens {
+		switch {
+		case line == "":
+			line = token
+		case len(line)+1+len(token) > movetextLineLength:
+			lines = append(lines, line)
+			line = token
+		default:
+			line += " " + token
+		}
+	}
+	lines = append(lines, line)
+
+	return fmt.Sprintf("%s\n%s\n", p.Tags, strings.Join(lines, "\n"))
 }
 
 func (m Movetext) String() string {
 	moves := []string{}
+	if m.Comment != "" {
+		moves = append(moves, fmt.Sprintf("{%s}", m.Comment))
+	}
 
 	for _, move := range m.Moves {
 		moves = append(moves, move.String())
@@ -105,7 +198,25 @@ func (m Movetext) String() string {
 }
 
 func (m MovetextMove) String() string {
-	return fmt.Sprintf("%d.%s %s", m.Number, m.WhiteMove, m.BlackMove)
+	str := fmt.Sprintf("%d.%s", m.Number, m.WhiteMove)
+	if m.WhiteComment != "" {
+		str += fmt.Sprintf(" {%s}", m.WhiteComment)
+	}
+
+	if m.BlackMove != "" {
+		// Black's move needs its number repeated once a comment intervenes
+		if m.WhiteComment != "" {
+			str += fmt.Sprintf(" %d...%s", m.Number, m.BlackMove)
+		} else {
+			str += " " + string(m.BlackMove)
+		}
+	}
+
+	if m.BlackComment != "" {
+		str += fmt.Sprintf(" {%s}", m.BlackComment)
+	}
+
+	return str
 }
 
 func NewPGN() PGN {
@@ -149,19 +260,31 @@ func (p *PGNParser) parse() PGN {
 }
 
 func (p *PGNParser) hasNext() bool {
-	for p.sc.Peek() == ' ' || p.sc.Peek() == '\n' {
+	for peek := p.sc.Peek(); peek == ' ' || peek == '\t' || peek == '\n' || peek == '\r'; peek = p.sc.Peek() {
 		p.sc.Next()
 	}
 
 	return p.sc.Peek() != scanner.EOF
 }
 
+// Parses the next game from the reader so collections too large to hold in
+// memory can be streamed. Returns false once no games are left.
+func (p *PGNParser) Next() (PGN, bool) {
+	if !p.hasNext() {
+		return PGN{}, false
+	}
+
+	pgn := p.parse()
+	p.pgn = NewPGN()
+
+	return pgn, true
+}
+
 func (p *PGNParser) ParseAll() []PGN {
 	pgns := []PGN{}
 
-	for p.hasNext() {
-		pgns = append(pgns, p.parse())
-		p.pgn = NewPGN()
+	for pgn, more := p.Next(); more; pgn, more = p.Next() {
+		pgns = append(pgns, pgn)
 	}
 
 	return pgns
@@ -186,17 +309,18 @@ func (p *PGNParser) parseTags() {
 
 	for scan != scanner.EOF {
 		switch scan {
-		case '[', ']', '\n', '\r':
+		case ']', ' ', '\t', '\n', '\r':
 			scan = p.sc.Next()
-		case '1': // First move; all tags have been read
-			return
-		default:
+		case '[':
+			p.sc.Next()
 			p.sc.Scan()
 			tag := p.sc.TokenText()
 			p.sc.Scan()
 			value := p.sc.TokenText()
 
 			p.pgn.Tags[tag] = strings.Trim(value, "\"")
+		default: // Movetext begins; all tags have been read
+			return
 		}
 		scan = p.sc.Peek()
 	}
@@ -218,19 +342,24 @@ func (p *PGNParser) parseMoves() {
 	for scan != scanner.EOF {
 		switch scan {
 		case '{':
-			// Scan past comments
-			p.scanUntilPast('}', &scan)
+			p.sc.Next()
+			p.addComment(p.scanComment())
+			scan = p.sc.Peek()
 		case '(':
 			// Scan past RAVs
 			p.scanUntilPast(')', &scan)
-		case '#', '.', '+', '!', '?', '\n', '\r':
+		case '#', '.', '+', '!', '?', ' ', '\t', '\n', '\r':
 			scan = p.sc.Next()
 			scan = p.sc.Peek()
+		case '[':
+			// No game termination marker; the next game's tags have begun
+			return
 		default:
 			p.sc.Scan()
 
 			if p.sc.TokenText() == "{" {
-				scan = '{'
+				p.addComment(p.scanComment())
+				scan = p.sc.Peek()
 				continue
 			} else if p.sc.TokenText() == "(" {
 				scan = '('
@@ -261,6 +390,8 @@ func (p *PGNParser) parseMoves() {
 					lastMove.WhiteMove = AlgebraicNotation(white)
 				})
 
+			case black == "" && isMoveNumber(p.sc.TokenText()) && p.sc.Peek() == '.':
+				// Black's move number repeated after a comment, e.g. 1... e5
 			case black == "":
 				p.scanMovetextForColor(&black)
 
@@ -280,6 +411,47 @@ func (p *PGNParser) parseMoves() {
 	}
 }
 
+// Reads up to and past the closing brace of a comment whose opening brace
+// has been read, collapsing runs of whitespace
+func (p *PGNParser) scanComment() string {
+	var comment strings.Builder
+
+	for next := p.sc.Next(); next != '}' && next != scanner.EOF; next = p.sc.Next() {
+		comment.WriteRune(next)
+	}
+
+	return strings.Join(strings.Fields(comment.String()), " ")
+}
+
+// Attaches a comment to the move it follows
+func (p *PGNParser) addComment(comment string) {
+	appendTo := func(existing *string) {
+		if *existing != "" {
+			*existing += " "
+		}
+		*existing += comment
+	}
+
+	switch last := len(p.pgn.Moves) - 1; {
+	case comment == "":
+	case last < 0:
+		appendTo(&p.pgn.Movetext.Comment)
+	case p.pgn.Moves[last].BlackMove != "":
+		appendTo(&p.pgn.Moves[last].BlackComment)
+	case p.pgn.Moves[last].WhiteMove != "":
+		appendTo(&p.pgn.Moves[last].WhiteComment)
+	case last > 0:
+		appendTo(&p.pgn.Moves[last-1].BlackComment)
+	default:
+		appendTo(&p.pgn.Movetext.Comment)
+	}
+}
+
+func isMoveNumber(token string) bool {
+	_, err := strconv.ParseUint(token, 10, 16)
+	return err == nil
+}
+
 func (p *PGNParser) scanUntilPast(r rune, scan *rune) {
 	for *scan != r && *scan != scanner.EOF {
 		*scan = p.sc.Next()
@@ -332,6 +504,7 @@ var outcomes = map[string]Outcome{
 	string(WhiteWin): WhiteWin,
 	string(BlackWin): BlackWin,
 	string(Draw):     Draw,
+	string(Ongoing):  Ongoing,
 }
 
 func reachedOutcome(str string) bool {
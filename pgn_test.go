@@ -1,6 +1,7 @@
 package pawn
 
 import (
+	"errors"
 	"fmt"
 	"strings"
 	"testing"
@@ -61,6 +62,56 @@ func TestMultipleEntries(t *testing.T) {
 	assert.Equal(t, len(pgns), 2)
 }
 
+func TestNext(t *testing.T) {
+	parser := NewPGNParserFromReader(strings.NewReader(multipleEntries))
+
+	games := 0
+	for pgn, more := parser.Next(); more; pgn, more = parser.Next() {
+		assert.NotEmpty(t, pgn.Moves)
+		games++
+	}
+
+	assert.Equal(t, 2, games)
+
+	_, more := parser.Next()
+	assert.False(t, more)
+}
+
+func TestOngoingOutcome(t *testing.T) {
+	pgns := NewPGNParserFromReader(strings.NewReader(ongoing + multipleEntries)).ParseAll()
+
+	assert.Equal(t, 3, len(pgns))
+	assert.Equal(t, Outcome(Ongoing), pgns[0].Outcome)
+	assert.Equal(t, 5, len(pgns[0].Moves))
+	assert.Equal(t, "Carlsen,M", pgns[1].Tags["White"])
+	assert.Equal(t, Outcome(WhiteWin), pgns[1].Outcome)
+}
+
+func TestMissingTerminator(t *testing.T) {
+	pgns := NewPGNParserFromReader(strings.NewReader(missingTerminator + multipleEntries)).ParseAll()
+
+	assert.Equal(t, 3, len(pgns))
+	assert.Equal(t, Outcome(""), pgns[0].Outcome)
+	assert.Equal(t, AlgebraicNotation("Nf6"), pgns[0].Turns()[1])
+	assert.Equal(t, "Onischuk,V", pgns[1].Tags["Black"])
+	assert.Equal(t, 10, len(pgns[1].Tags))
+}
+
+func TestVerifyResult(t *testing.T) {
+	assert.Nil(t, ParsePGN(win).VerifyResult())
+	assert.Nil(t, ParsePGN(ongoing).VerifyResult())
+
+	mismatch := ParsePGN(strings.Replace(win, `[Result "0-1"]`, `[Result "1-0"]`, 1))
+	assert.True(t, errors.Is(mismatch.VerifyResult(), ErrorResultMismatch))
+
+	unterminated := NewPGNParserFromReader(strings.NewReader(missingTerminator)).ParseAll()[0]
+	assert.True(t, errors.Is(unterminated.VerifyResult(), ErrorResultMismatch))
+
+	untagged := NewPGN()
+	untagged.Outcome = Draw
+	assert.Nil(t, untagged.VerifyResult())
+}
+
 func TestPGNString(t *testing.T) {
 	pgn := ParsePGN(win)
 
@@ -70,6 +121,39 @@ func TestPGNString(t *testing.T) {
 	assert.Equal(t, strings.Count(win, "["), strings.Count(pgn.String(), "["))
 }
 
+func TestPGNExport(t *testing.T) {
+	exported := ParsePGN(win).String()
+
+	assert.True(t, strings.HasPrefix(exported, "[Event \"WCh 2013\"]\n[Site \"Chennai IND\"]\n[Date"))
+	assert.True(t, strings.Contains(exported, "[Result \"0-1\"]\n[BlackElo \"2870\"]\n[ECO \"E25\"]\n"))
+	assert.True(t, strings.HasSuffix(exported, "28.Nf1 Qe1 0-1\n"))
+
+	for _, line := range strings.Split(exported, "\n") {
+		assert.True(t, len(line) <= movetextLineLength, line)
+	}
+
+	reparsed := ParsePGN(exported)
+	assert.Equal(t, ParsePGN(win).Turns(), reparsed.Turns())
+	assert.Equal(t, Outcome(BlackWin), reparsed.Outcome)
+
+	assert.Equal(t, "42.Kh2", ParsePGN(finalMoveByWhite).Moves[41].String())
+}
+
+func TestComments(t *testing.T) {
+	pgn := ParsePGN(embeddedComments)
+
+	assert.Equal(t, "Notes by Lasker", pgn.Moves[0].WhiteComment)
+	assert.Equal(t, "", pgn.Moves[0].BlackComment)
+	assert.True(t, strings.HasPrefix(pgn.Moves[14].BlackComment, "Black has defended conscientiously. If White"))
+	assert.Equal(t, "1.e4 {Notes by Lasker} 1...e6", pgn.Moves[0].String())
+
+	reparsed := ParsePGN(pgn.String())
+	assert.Equal(t, pgn.Movetext, reparsed.Movetext)
+	assert.Equal(t, pgn.Outcome, reparsed.Outcome)
+
+	assert.Equal(t, "Opening notes", ParsePGN("{Opening notes} 1. e4 e5 *").Comment)
+}
+
 func TestMatchUp(t *testing.T) {
 	pgn := ParsePGN(win)
 
@@ -248,3 +332,27 @@ var multipleEntries = `
 23.Rxd7 Bxd7 24.Qxd7  1-0
 
 `
+
+var ongoing = `
+[Event "Norway Chess 2017"]
+[Site "Stavanger NOR"]
+[Date "2017.06.06"]
+[Round "1"]
+[White "Carlsen,M"]
+[Black "Nakamura,Hi"]
+[Result "*"]
+
+1.e4 e5 2.Nf3 Nc6 3.Bb5 Nf6 4.O-O Nxe4 5.Re1 Nd6 *
+`
+
+var missingTerminator = `
+[Event "Norway Chess 2017"]
+[Site "Stavanger NOR"]
+[Date "2017.06.07"]
+[Round "2"]
+[White "Aronian,L"]
+[Black "Carlsen,M"]
+[Result "1/2-1/2"]
+
+1.d4 Nf6 2.c4 e6 3.Nf3 d5 4.Nc3 Be7
+`
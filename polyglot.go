@@ -0,0 +1,311 @@
+package pawn
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"math/rand"
+	"sort"
+)
+
+var ErrorInvalidBook = errors.New("pawn: polyglot book is not a whole number of 16 byte entries")
+
+// Polyglot numbers pieces black pawn, white pawn, black knight ... white king
+var polyglotKinds = [King + 1]int{Pawn: 0, Knight: 1, Bishop: 2, Rook: 3, Queen: 4, King: 5}
+
+var polyglotCastling = [...]castlingRights{whiteKingSide, whiteQueenSide, blackKingSide, blackQueenSide}
+
+const (
+	polyglotCastlingOffset  = 768
+	polyglotEnPassantOffset = 772
+	polyglotTurnOffset      = 780
+	polyglotEntrySize       = 16
+)
+
+// The key Polyglot books index the position by. It differs from Hash, which
+// uses pawn's own random values.
+func (b Board) PolyglotKey() uint64 {
+	var key uint64
+
+	for index, square := range b.Squares {
+		piece := square.Piece
+		if piece == NoPiece {
+			continue
+		}
+
+		kind := 2 * polyglotKinds[piece.Material]
+		if piece.Color == White {
+			kind++
+		}
+
+		file, row := index/len(allRanks), index%len(allRanks)
+		key ^= polyglotRandom[64*kind+8*row+file]
+	}
+
+	for i, rights := range polyglotCastling {
+		if b.castling&rights != 0 {
+			key ^= polyglotRandom[polyglotCastlingOffset+i]
+		}
+	}
+
+	// Like Hash, only when a pawn can actually capture en passant
+	if b.enPassantKey() != 0 {
+		key ^= polyglotRandom[polyglotEnPassantOffset+b.enPassant.File.index()]
+	}
+
+	if b.SideToMove() == White {
+		key ^= polyglotRandom[polyglotTurnOffset]
+	}
+
+	return key
+}
+
+// A move as Polyglot encodes it: destination file and row in bits 0-5,
+// origin file and row in bits 6-11 and the promotion piece in bits 12-14.
+// Castling is written as the king taking its own rook, e.g. e1h1.
+type PolyglotMove uint16
+
+var polyglotPromotions = [King + 1]PolyglotMove{Knight: 1, Bishop: 2, Rook: 3, Queen: 4}
+
+func EncodePolyglotMove(move Move) PolyglotMove {
+	to := move.To
+	if move.Material == King && move.From.File == E {
+		switch to.File {
+		case G:
+			to.File = H
+		case C:
+			to.File = A
+		}
+	}
+
+	return polyglotPromotions[move.Promotion]<<12 | PolyglotMove(squareNumber(move.From)<<6|squareNumber(to))
+}
+
+type PolyglotEntry struct {
+	Key    uint64
+	Move   PolyglotMove
+	Weight uint16
+	Learn  uint32
+}
+
+// A Polyglot opening book. Entries are kept sorted by key and, for the same
+// key, by descending weight as the format requires.
+type PolyglotBook struct {
+	Entries []PolyglotEntry
+}
+
+// A legal move found in a book for a position
+type BookMove struct {
+	Move
+	Weight uint16
+}
+
+// Reads a .bin book from r
+func ReadPolyglotBook(r io.Reader) (*PolyglotBook, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(data)%polyglotEntrySize != 0 {
+		return nil, ErrorInvalidBook
+	}
+
+	entries := make([]PolyglotEntry, len(data)/polyglotEntrySize)
+	if err := binary.Read(bytes.NewReader(data), binary.BigEndian, entries); err != nil {
+		return nil, err
+	}
+
+	book := &PolyglotBook{Entries: entries}
+	book.sort()
+
+	return book, nil
+}
+
+// Writes the book in .bin format
+func (p *PolyglotBook) Write(w io.Writer) error {
+	p.sort()
+
+	return binary.Write(w, binary.BigEndian, p.Entries)
+}
+
+func (p *PolyglotBook) sort() {
+	sort.SliceStable(p.Entries, func(i, j int) bool {
+		if p.Entries[i].Key != p.Entries[j].Key {
+			return p.Entries[i].Key < p.Entries[j].Key
+		}
+
+		return p.Entries[i].Weight > p.Entries[j].Weight
+	})
+}
+
+// The book's legal moves for the board's position, highest weight first.
+// Entries that don't describe a legal move are ignored.
+func (p *PolyglotBook) Moves(board *Board) []BookMove {
+	key := board.PolyglotKey()
+	first := sort.Search(len(p.Entries), func(i int) bool {
+		return p.Entries[i].Key >= key
+	})
+
+	moves := []BookMove{}
+	if first == len(p.Entries) || p.Entries[first].Key != key {
+		return moves
+	}
+
+	legal := map[PolyglotMove]Move{}
+	for _, move := range board.LegalMoves() {
+		legal[EncodePolyglotMove(move)] = move
+	}
+
+	for _, entry := range p.Entries[first:] {
+		if entry.Key != key {
+			break
+		}
+
+		if move, found := legal[entry.Move]; found {
+			moves = append(moves, BookMove{move, entry.Weight})
+		}
+	}
+
+	return moves
+}
+
+// Picks one of the book's moves for the position with a probability
+// proportional to its weight. Returns false if the book has no move with a
+// weight above zero.
+func (p *PolyglotBook) WeightedMove(board *Board, random *rand.Rand) (Move, bool) {
+	moves := p.Moves(board)
+
+	total := 0
+	for _, move := range moves {
+		total += int(move.Weight)
+	}
+
+	if total == 0 {
+		return Move{}, false
+	}
+
+	pick := random.Intn(total)
+	for _, move := range moves {
+		if pick < int(move.Weight) {
+			return move.Move, true
+		}
+		pick -= int(move.Weight)
+	}
+
+	return Move{}, false
+}
+
+// Which games and moves BuildPolyglotBook takes into account
+type BookOptions struct {
+	MinGames    int  // Moves played in fewer games are left out
+	MaxPlies    int  // Moves after this many plies aren't added; zero for no limit
+	WinnersOnly bool // Only add moves played by the side that went on to win
+}
+
+type bookMoveKey struct {
+	key  uint64
+	move PolyglotMove
+}
+
+type bookMoveScore struct {
+	games int
+	score int // Two points for every win and one for every draw
+}
+
+// Builds a book from the games' moves up to their first illegal one. Each
+// move is weighted by the score of the side that played it, so moves that
+// never scored get a weight of zero and are left out.
+func BuildPolyglotBook(pgns []PGN, options BookOptions) *PolyglotBook {
+	scores := map[bookMoveKey]*bookMoveScore{}
+
+	for _, pgn := range pgns {
+		if options.WinnersOnly && pgn.Outcome != WhiteWin && pgn.Outcome != BlackWin {
+			continue
+		}
+
+		board := NewBoard()
+		ply := 0
+
+		for _, an := range pgn.Turns() {
+			if options.MaxPlies > 0 && ply >= options.MaxPlies {
+				break
+			}
+
+			if an == "" {
+				continue
+			}
+
+			move, err := board.ResolveAlgebraic(an)
+			if err != nil {
+				break
+			}
+
+			side := board.SideToMove()
+			if score := outcomeScore(pgn.Outcome, side); !options.WinnersOnly || score == 2 {
+				key := bookMoveKey{board.PolyglotKey(), EncodePolyglotMove(move)}
+				if scores[key] == nil {
+					scores[key] = &bookMoveScore{}
+				}
+
+				scores[key].games++
+				scores[key].score += score
+			}
+
+			board.MakeMove(move)
+			ply++
+		}
+	}
+
+	return newPolyglotBook(scores, options.MinGames)
+}
+
+// Points for side from a game's outcome, two for a win and one for a draw
+func outcomeScore(outcome Outcome, side Color) int {
+	switch {
+	case outcome == Draw:
+		return 1
+	case outcome == WhiteWin && side == White, outcome == BlackWin && side == Black:
+		return 2
+	}
+
+	return 0
+}
+
+// Weights are scaled down to fit in 16 bits if the highest score doesn't
+func newPolyglotBook(scores map[bookMoveKey]*bookMoveScore, minGames int) *PolyglotBook {
+	highest := 0
+	for _, score := range scores {
+		if score.score > highest {
+			highest = score.score
+		}
+	}
+
+	book := &PolyglotBook{Entries: []PolyglotEntry{}}
+
+	for key, score := range scores {
+		if score.games < minGames || score.score == 0 {
+			continue
+		}
+
+		weight := score.score
+		if highest > 0xffff {
+			weight = weight * 0xffff / highest
+			if weight == 0 {
+				weight = 1
+			}
+		}
+
+		book.Entries = append(book.Entries, PolyglotEntry{Key: key.key, Move: key.move, Weight: uint16(weight)})
+	}
+
+	// Map iteration order is random so ties are broken by move to keep the
+	// output reproducible
+	sort.Slice(book.Entries, func(i, j int) bool {
+		return book.Entries[i].Move < book.Entries[j].Move
+	})
+	book.sort()
+
+	return book
+}
@@ -0,0 +1,139 @@
+package pawn
+
+import (
+	"bytes"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type PolyglotTestSuite struct {
+	suite.Suite
+}
+
+func TestPolyglotTestSuite(t *testing.T) {
+	suite.Run(t, new(PolyglotTestSuite))
+}
+
+func (s *PolyglotTestSuite) boardAfter(moves ...AlgebraicNotation) *Board {
+	board := NewBoard()
+	for _, an := range moves {
+		_, err := board.MoveFromAlgebraic(an)
+		s.Require().Nil(err, string(an))
+	}
+
+	return board
+}
+
+// The test positions from the Polyglot book format specification
+func (s *PolyglotTestSuite) TestPolyglotKey() {
+	examples := []struct {
+		moves []AlgebraicNotation
+		key   uint64
+	}{
+		{[]AlgebraicNotation{}, 0x463b96181691fc9c},
+		{[]AlgebraicNotation{"e4"}, 0x823c9b50fd114196},
+		{[]AlgebraicNotation{"e4", "d5"}, 0x0756b94461c50fb0},
+		{[]AlgebraicNotation{"e4", "d5", "e5"}, 0x662fafb965db29d4},
+		{[]AlgebraicNotation{"e4", "d5", "e5", "f5"}, 0x22a48b5a8e47ff78},
+		{[]AlgebraicNotation{"e4", "d5", "e5", "f5", "Ke2"}, 0x652a607ca3f242c1},
+		{[]AlgebraicNotation{"e4", "d5", "e5", "f5", "Ke2", "Kf7"}, 0x00fdd303c946bdd9},
+		{[]AlgebraicNotation{"a4", "b5", "h4", "b4", "c4"}, 0x3c8123ea7b067637},
+		{[]AlgebraicNotation{"a4", "b5", "h4", "b4", "c4", "bxc3", "Ra3"}, 0x5c3f9b829b279560},
+	}
+
+	for _, example := range examples {
+		s.Equal(example.key, s.boardAfter(example.moves...).PolyglotKey(), "%v", example.moves)
+	}
+}
+
+func (s *PolyglotTestSuite) TestEncodePolyglotMove() {
+	s.Equal(PolyglotMove(0x031c), EncodePolyglotMove(Move{Piece{White, Pawn}, E2, E4, false, 0}))
+	s.Equal(PolyglotMove(0x0107), EncodePolyglotMove(Move{Piece{White, King}, E1, G1, false, 0}))
+	s.Equal(PolyglotMove(0x0f38), EncodePolyglotMove(Move{Piece{Black, King}, E8, C8, false, 0}))
+	s.Equal(PolyglotMove(0x4d3c), EncodePolyglotMove(Move{Piece{White, Pawn}, E7, E8, false, Queen}))
+}
+
+func (s *PolyglotTestSuite) TestReadWrite() {
+	book := &PolyglotBook{Entries: []PolyglotEntry{
+		{Key: 2, Move: 3, Weight: 4, Learn: 5},
+		{Key: 1, Move: 6, Weight: 7, Learn: 8},
+	}}
+
+	var buffer bytes.Buffer
+	s.Nil(book.Write(&buffer))
+	s.Equal([]byte{0, 0, 0, 0, 0, 0, 0, 1, 0, 6, 0, 7, 0, 0, 0, 8}, buffer.Bytes()[:16])
+
+	read, err := ReadPolyglotBook(&buffer)
+	s.Nil(err)
+	s.Equal(book.Entries, read.Entries)
+
+	_, err = ReadPolyglotBook(bytes.NewReader(make([]byte, 17)))
+	s.Equal(ErrorInvalidBook, err)
+}
+
+func (s *PolyglotTestSuite) TestMoves() {
+	start := NewBoard().PolyglotKey()
+	castles := s.boardAfter("e4", "e5", "Nf3", "Nc6", "Bc4", "Bc5")
+
+	book := &PolyglotBook{Entries: []PolyglotEntry{
+		{Key: start, Move: 0x031c, Weight: 10},                          // e4
+		{Key: start, Move: 0x02db, Weight: 30},                          // d4
+		{Key: start, Move: 0x0124, Weight: 5},                           // Illegal
+		{Key: castles.PolyglotKey(), Move: 0x0107, Weight: 1},           // O-O
+		{Key: castles.PolyglotKey() + 1, Move: 0x0000, Weight: 1 << 15}, // Another position
+	}}
+	book.sort()
+
+	moves := book.Moves(NewBoard())
+	s.Equal(2, len(moves))
+	s.Equal(D4, moves[0].To)
+	s.Equal(uint16(30), moves[0].Weight)
+	s.Equal(E4, moves[1].To)
+
+	moves = book.Moves(castles)
+	s.Equal(1, len(moves))
+	s.Equal(AlgebraicNotation("O-O"), castles.Algebraic(moves[0].Move))
+
+	s.Empty(book.Moves(s.boardAfter("a3")))
+
+	random := rand.New(rand.NewSource(1))
+	counts := map[Position]int{}
+	for i := 0; i < 400; i++ {
+		move, found := book.WeightedMove(NewBoard(), random)
+		s.True(found)
+		counts[move.To]++
+	}
+	s.InDelta(300, counts[D4], 40)
+	s.InDelta(100, counts[E4], 40)
+
+	_, found := book.WeightedMove(s.boardAfter("a3"), random)
+	s.False(found)
+}
+
+func (s *PolyglotTestSuite) TestBuildPolyglotBook() {
+	pgns := []PGN{
+		ParsePGN("[Result \"1-0\"]\n\n1.e4 e5 2.Nf3 1-0"),
+		ParsePGN("[Result \"1/2-1/2\"]\n\n1.e4 c5 2.Nf3 1/2-1/2"),
+		ParsePGN("[Result \"0-1\"]\n\n1.d4 d5 2.c4 0-1"),
+	}
+
+	book := BuildPolyglotBook(pgns, BookOptions{})
+	moves := book.Moves(NewBoard())
+	s.Equal(1, len(moves), "d4 lost its only game")
+	s.Equal(E4, moves[0].To)
+	s.Equal(uint16(3), moves[0].Weight)
+
+	moves = book.Moves(s.boardAfter("d4"))
+	s.Equal(uint16(2), moves[0].Weight)
+
+	book = BuildPolyglotBook(pgns, BookOptions{MinGames: 2})
+	s.Equal(1, len(book.Entries))
+
+	book = BuildPolyglotBook(pgns, BookOptions{MaxPlies: 1})
+	s.Empty(book.Moves(s.boardAfter("e4")))
+
+	book = BuildPolyglotBook(pgns, BookOptions{WinnersOnly: true})
+	s.Equal(3, len(book.Entries), "e4 and Nf3 from the first game and d5 from the third")
+}
@@ -0,0 +1,594 @@
+package pawn
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+var ErrorInvalidQuery = errors.New("pawn: invalid query")
+
+// A compiled pattern query. A game matches when some position in it, from
+// the initial one to the last, satisfies the query. Queries are written as
+// filters combined with and, or, not and parentheses:
+//
+//	N@f5                   a white knight on f5
+//	count Pp@a-d* == 0     no pawns on the queenside
+//	move capture B@h7      the last move was a white bishop capturing on h7
+//	attacked B@h7          a white bishop on h7 that Black attacks
+//	castled black short    Black has castled king side
+//	within 5 mate          checkmate comes in the next 5 moves
+//	check, mate, stalemate, wtm, btm, ply > 40
+//
+// Pieces are given as letters, upper case for White and lower case for
+// Black, with A and a for any piece of that color. Squares after @ are a
+// comma separated list of files and ranks, each of which may be a range or
+// * for all, e.g. g-h7-8,f*.
+type Query struct {
+	Source string
+	filter queryFilter
+}
+
+// Decides whether the position after ply moves of the game satisfies a filter
+type queryFilter func(game *queryGame, ply int) bool
+
+// A game being searched, replayed to whichever ply a filter needs
+type queryGame struct {
+	board *Board
+	moves []Move
+	ply   int
+}
+
+func (g *queryGame) seek(ply int) *Board {
+	for g.ply < ply {
+		g.board.MakeMove(g.moves[g.ply])
+		g.ply++
+	}
+
+	for g.ply > ply {
+		g.board.UnmakeMove()
+		g.ply--
+	}
+
+	return g.board
+}
+
+func CompileQuery(source string) (*Query, error) {
+	parser := &queryParser{tokens: tokenizeQuery(source)}
+
+	filter, err := parser.parseOr()
+	if err != nil {
+		return nil, err
+	}
+
+	if !parser.done() {
+		return nil, parser.errorf("unexpected \"%s\"", parser.peek())
+	}
+
+	return &Query{Source: source, filter: filter}, nil
+}
+
+// The first ply at which the game satisfies the query. Only moves up to the
+// game's first illegal one are searched.
+func (q *Query) Match(pgn PGN) (int, bool) {
+	game, _ := pgn.Game()
+	searched := &queryGame{board: NewBoard(), moves: game.Moves}
+
+	for ply := 0; ply <= len(game.Moves); ply++ {
+		if q.filter(searched, ply) {
+			return ply, true
+		}
+	}
+
+	return 0, false
+}
+
+// The games matching the query with the first ply each matched at, in the
+// order of pgns. Games are searched by workers goroutines at once.
+func (q *Query) Search(pgns []PGN, workers int) []PositionMatch {
+	if workers < 1 {
+		workers = 1
+	}
+
+	var (
+		matches []PositionMatch
+		mutex   sync.Mutex
+		wait    sync.WaitGroup
+	)
+	games := make(chan int)
+
+	for worker := 0; worker < workers; worker++ {
+		wait.Add(1)
+		go func() {
+			defer wait.Done()
+
+			for game := range games {
+				if ply, found := q.Match(pgns[game]); found {
+					mutex.Lock()
+					matches = append(matches, PositionMatch{game, ply})
+					mutex.Unlock()
+				}
+			}
+		}()
+	}
+
+	for game := range pgns {
+		games <- game
+	}
+	close(games)
+	wait.Wait()
+
+	sort.Slice(matches, func(i, j int) bool {
+		return matches[i].Game < matches[j].Game
+	})
+
+	return matches
+}
+
+func tokenizeQuery(source string) []string {
+	tokens := []string{}
+	runes := []rune(source)
+
+	for i := 0; i < len(runes); {
+		start := i
+
+		switch r := runes[i]; {
+		case unicode.IsSpace(r):
+			i++
+			continue
+		case r == '(' || r == ')':
+			i++
+		case strings.ContainsRune("<>=!", r):
+			for i < len(runes) && strings.ContainsRune("<>=!", runes[i]) {
+				i++
+			}
+		default:
+			for i < len(runes) && !unicode.IsSpace(runes[i]) && !strings.ContainsRune("()<>=!", runes[i]) {
+				i++
+			}
+		}
+
+		tokens = append(tokens, string(runes[start:i]))
+	}
+
+	return tokens
+}
+
+type queryParser struct {
+	tokens []string
+	next   int
+}
+
+func (p *queryParser) done() bool {
+	return p.next >= len(p.tokens)
+}
+
+func (p *queryParser) peek() string {
+	if p.done() {
+		return ""
+	}
+
+	return p.tokens[p.next]
+}
+
+func (p *queryParser) take() string {
+	token := p.peek()
+	p.next++
+
+	return token
+}
+
+func (p *queryParser) errorf(format string, args ...interface{}) error {
+	return fmt.Errorf("%w: %s", ErrorInvalidQuery, fmt.Sprintf(format, args...))
+}
+
+func (p *queryParser) parseOr() (queryFilter, error) {
+	filter, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.peek() == "or" {
+		p.take()
+
+		rhs, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+
+		lhs := filter
+		filter = func(game *queryGame, ply int) bool {
+			return lhs(game, ply) || rhs(game, ply)
+		}
+	}
+
+	return filter, nil
+}
+
+func (p *queryParser) parseAnd() (queryFilter, error) {
+	filter, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.peek() == "and" {
+		p.take()
+
+		rhs, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+
+		lhs := filter
+		filter = func(game *queryGame, ply int) bool {
+			return lhs(game, ply) && rhs(game, ply)
+		}
+	}
+
+	return filter, nil
+}
+
+func (p *queryParser) parseUnary() (queryFilter, error) {
+	switch token := p.take(); token {
+	case "":
+		return nil, p.errorf("unexpected end of query")
+	case "not":
+		filter, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+
+		return func(game *queryGame, ply int) bool {
+			return !filter(game, ply)
+		}, nil
+	case "(":
+		filter, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+
+		if p.take() != ")" {
+			return nil, p.errorf("missing )")
+		}
+
+		return filter, nil
+	case "within":
+		moves, err := p.parseNumber()
+		if err != nil {
+			return nil, err
+		}
+
+		filter, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+
+		return within(moves, filter), nil
+	default:
+		return p.parseFilter(token)
+	}
+}
+
+// Holds when filter does at one of the positions after the next moves full
+// moves, i.e. twice as many plies
+func within(moves int, filter queryFilter) queryFilter {
+	return func(game *queryGame, ply int) bool {
+		for later := ply + 1; later <= ply+2*moves && later <= len(game.moves); later++ {
+			if filter(game, later) {
+				return true
+			}
+		}
+
+		return false
+	}
+}
+
+func (p *queryParser) parseFilter(token string) (queryFilter, error) {
+	switch token {
+	case "check":
+		return func(game *queryGame, ply int) bool {
+			return game.seek(ply).InCheck()
+		}, nil
+	case "mate":
+		return func(game *queryGame, ply int) bool {
+			return game.seek(ply).IsCheckmate()
+		}, nil
+	case "stalemate":
+		return func(game *queryGame, ply int) bool {
+			return game.seek(ply).IsStalemate()
+		}, nil
+	case "wtm", "btm":
+		side := White
+		if token == "btm" {
+			side = Black
+		}
+
+		return func(game *queryGame, ply int) bool {
+			return game.seek(ply).SideToMove() == side
+		}, nil
+	case "ply":
+		compare, err := p.parseComparison()
+		if err != nil {
+			return nil, err
+		}
+
+		return func(game *queryGame, ply int) bool {
+			return compare(ply)
+		}, nil
+	case "castled":
+		return p.parseCastled()
+	case "count":
+		designator, err := p.parseDesignator(p.take())
+		if err != nil {
+			return nil, err
+		}
+
+		compare, err := p.parseComparison()
+		if err != nil {
+			return nil, err
+		}
+
+		return func(game *queryGame, ply int) bool {
+			return compare(designator.count(game.seek(ply)))
+		}, nil
+	case "attacked":
+		designator, err := p.parseDesignator(p.take())
+		if err != nil {
+			return nil, err
+		}
+
+		return func(game *queryGame, ply int) bool {
+			board := game.seek(ply)
+
+			for _, index := range designator.indices(board) {
+				if board.attacked(index, board.Squares[index].Color.Opponent()) {
+					return true
+				}
+			}
+
+			return false
+		}, nil
+	case "move":
+		return p.parseMove()
+	}
+
+	designator, err := p.parseDesignator(token)
+	if err != nil {
+		return nil, err
+	}
+
+	return func(game *queryGame, ply int) bool {
+		return designator.count(game.seek(ply)) > 0
+	}, nil
+}
+
+func (p *queryParser) parseNumber() (int, error) {
+	token := p.take()
+
+	number, err := strconv.Atoi(token)
+	if err != nil || number < 0 {
+		return 0, p.errorf("expected a number but found \"%s\"", token)
+	}
+
+	return number, nil
+}
+
+func (p *queryParser) parseComparison() (func(int) bool, error) {
+	operator := p.take()
+
+	value, err := p.parseNumber()
+	if err != nil {
+		return nil, err
+	}
+
+	switch operator {
+	case "==", "=":
+		return func(n int) bool { return n == value }, nil
+	case "!=":
+		return func(n int) bool { return n != value }, nil
+	case "<":
+		return func(n int) bool { return n < value }, nil
+	case "<=":
+		return func(n int) bool { return n <= value }, nil
+	case ">":
+		return func(n int) bool { return n > value }, nil
+	case ">=":
+		return func(n int) bool { return n >= value }, nil
+	}
+
+	return nil, p.errorf("expected a comparison but found \"%s\"", operator)
+}
+
+func (p *queryParser) parseCastled() (queryFilter, error) {
+	color := Color("")
+	switch token := p.take(); token {
+	case "white":
+		color = White
+	case "black":
+		color = Black
+	default:
+		return nil, p.errorf("expected white or black after castled but found \"%s\"", token)
+	}
+
+	kingTo := Position{G, color.homeRank()}
+	switch token := p.take(); token {
+	case "short":
+	case "long":
+		kingTo.File = C
+	default:
+		return nil, p.errorf("expected short or long but found \"%s\"", token)
+	}
+
+	king := Piece{color, King}
+	kingFrom := Position{E, color.homeRank()}
+
+	return func(game *queryGame, ply int) bool {
+		for _, move := range game.moves[:ply] {
+			if move.Piece == king && move.From == kingFrom && move.To == kingTo {
+				return true
+			}
+		}
+
+		return false
+	}, nil
+}
+
+// move [capture] designator: the move leading to the position was made by a
+// piece matching the designator and ended on one of its squares
+func (p *queryParser) parseMove() (queryFilter, error) {
+	capture := false
+	token := p.take()
+	if token == "capture" {
+		capture = true
+		token = p.take()
+	}
+
+	designator, err := p.parseDesignator(token)
+	if err != nil {
+		return nil, err
+	}
+
+	return func(game *queryGame, ply int) bool {
+		if ply == 0 {
+			return false
+		}
+
+		move := game.moves[ply-1]
+		board := game.seek(ply - 1)
+		takes := board.SquareAtPosition(move.To).Piece != NoPiece ||
+			(move.Material == Pawn && move.From.File != move.To.File)
+
+		return (!capture || takes) && designator.matches(move.Piece, move.To.index())
+	}, nil
+}
+
+// Pieces of some kinds on a set of squares
+type pieceDesignator struct {
+	pieces  map[Piece]bool
+	squares [64]bool
+}
+
+var designatorLetters = map[rune][]Piece{}
+
+func init() {
+	for piece, letter := range fenLetters {
+		designatorLetters[rune(letter)] = []Piece{piece}
+	}
+
+	for material := Pawn; material <= King; material++ {
+		designatorLetters['A'] = append(designatorLetters['A'], Piece{White, material})
+		designatorLetters['a'] = append(designatorLetters['a'], Piece{Black, material})
+	}
+}
+
+func (p *queryParser) parseDesignator(token string) (*pieceDesignator, error) {
+	letters, squares := token, "*"
+	if at := strings.IndexRune(token, '@'); at >= 0 {
+		letters, squares = token[:at], token[at+1:]
+	}
+
+	if letters == "" {
+		return nil, p.errorf("expected pieces but found \"%s\"", token)
+	}
+
+	designator := &pieceDesignator{pieces: map[Piece]bool{}}
+
+	for _, letter := range letters {
+		pieces, found := designatorLetters[letter]
+		if !found {
+			return nil, p.errorf("unknown filter or piece \"%s\"", token)
+		}
+
+		for _, piece := range pieces {
+			designator.pieces[piece] = true
+		}
+	}
+
+	for _, set := range strings.Split(squares, ",") {
+		if err := designator.addSquares(set); err != nil {
+			return nil, p.errorf("invalid squares \"%s\" in \"%s\"", set, token)
+		}
+	}
+
+	return designator, nil
+}
+
+// Adds a set of squares such as f5, a-d*, g-h7-8 or *
+func (d *pieceDesignator) addSquares(set string) error {
+	fileFrom, fileTo, rest, err := squareRange(set, 'a', 'h')
+	if err != nil {
+		return err
+	}
+
+	if set == "*" {
+		rest = "*"
+	}
+
+	rankFrom, rankTo, rest, err := squareRange(rest, '1', '8')
+	if err != nil || rest != "" {
+		return ErrorInvalidPosition
+	}
+
+	for file := fileFrom; file <= fileTo; file++ {
+		for rank := rankFrom; rank <= rankTo; rank++ {
+			d.squares[file*len(allRanks)+rank] = true
+		}
+	}
+
+	return nil
+}
+
+// Reads a single character, a range such as a-d or * for every one from
+// lowest to highest, returning their offsets from lowest and what follows
+func squareRange(str string, lowest, highest byte) (int, int, string, error) {
+	switch {
+	case str == "":
+		return 0, 0, "", ErrorInvalidPosition
+	case str[0] == '*':
+		return 0, int(highest - lowest), str[1:], nil
+	case str[0] < lowest || str[0] > highest:
+		return 0, 0, "", ErrorInvalidPosition
+	}
+
+	from, to := int(str[0]-lowest), int(str[0]-lowest)
+	str = str[1:]
+
+	if len(str) >= 2 && str[0] == '-' {
+		if str[1] < lowest || str[1] > highest {
+			return 0, 0, "", ErrorInvalidPosition
+		}
+
+		to = int(str[1] - lowest)
+		str = str[2:]
+	}
+
+	if to < from {
+		return 0, 0, "", ErrorInvalidPosition
+	}
+
+	return from, to, str, nil
+}
+
+func (d *pieceDesignator) matches(piece Piece, index int) bool {
+	return d.pieces[piece] && d.squares[index]
+}
+
+func (d *pieceDesignator) indices(board *Board) []int {
+	indices := []int{}
+
+	for index, square := range board.Squares {
+		if square.Piece != NoPiece && d.matches(square.Piece, index) {
+			indices = append(indices, index)
+		}
+	}
+
+	return indices
+}
+
+func (d *pieceDesignator) count(board *Board) int {
+	return len(d.indices(board))
+}
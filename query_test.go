@@ -0,0 +1,144 @@
+package pawn
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type QueryTestSuite struct {
+	suite.Suite
+}
+
+func TestQueryTestSuite(t *testing.T) {
+	suite.Run(t, new(QueryTestSuite))
+}
+
+func (s *QueryTestSuite) match(query string, movetext string) (int, bool) {
+	compiled, err := CompileQuery(query)
+	s.Require().Nil(err, query)
+
+	return compiled.Match(ParsePGN("[Result \"*\"]\n\n" + movetext + " *"))
+}
+
+func (s *QueryTestSuite) assertMatch(query string, movetext string, expectedPly int) {
+	ply, found := s.match(query, movetext)
+	s.True(found, query)
+	s.Equal(expectedPly, ply, query)
+}
+
+func (s *QueryTestSuite) assertNoMatch(query string, movetext string) {
+	_, found := s.match(query, movetext)
+	s.False(found, query)
+}
+
+func (s *QueryTestSuite) TestPieces() {
+	s.assertMatch("N@f3", "1.e4 e5 2.Nf3 Nc6", 3)
+	s.assertMatch("n@c6", "1.e4 e5 2.Nf3 Nc6", 4)
+	s.assertMatch("N@a-h3", "1.e4 e5 2.Nf3 Nc6", 3)
+	s.assertMatch("Pp@d-e4-5,c5", "1.e4 e5 2.Nf3 Nc6", 1)
+	s.assertNoMatch("Q@*5", "1.e4 e5 2.Nf3 Nc6")
+	s.assertMatch("count a@*1-3 == 1", "1.Nc3 e5 2.Nb1 Bc5 3.Nc3 Bxf2+", 6)
+	s.assertMatch("count Pp < 16 and count Pp >= 15", "1.e4 d5 2.exd5 Qxd5", 3)
+}
+
+func (s *QueryTestSuite) TestPositionFilters() {
+	foolsMate := "1.f3 e5 2.g4 Qh4#"
+
+	s.assertMatch("check", foolsMate, 4)
+	s.assertMatch("mate and wtm", foolsMate, 4)
+	s.assertNoMatch("mate and btm", foolsMate)
+	s.assertMatch("ply >= 2 and not (P@f2 or p@e7)", foolsMate, 2)
+	s.assertMatch("within 1 mate", foolsMate, 2)
+	s.assertNoMatch("ply < 2 and within 1 mate", foolsMate)
+	s.assertMatch("attacked K@e1", foolsMate, 4)
+	s.assertMatch("attacked q", "1.e4 e5 2.Nf3 Qf6 3.Nc3 Qg6 4.Nh4", 7)
+}
+
+func (s *QueryTestSuite) TestMoves() {
+	ruyLopez := "1.e4 e5 2.Nf3 Nc6 3.Bb5 a6 4.Bxc6 dxc6 5.O-O f6 6.d4 Bd6 7.dxe5 fxe5"
+
+	s.assertMatch("move B@b5", ruyLopez, 5)
+	s.assertMatch("move capture B", ruyLopez, 7)
+	s.assertMatch("move capture p@c6", ruyLopez, 8)
+	s.assertMatch("castled white short", ruyLopez, 9)
+	s.assertNoMatch("castled white long or castled black short", ruyLopez)
+	s.assertMatch("move capture P", "1.e4 d5 2.e5 f5 3.exf6", 5)
+}
+
+// The examples the query language was designed around
+func (s *QueryTestSuite) TestPatterns() {
+	s.assertMatch(
+		"N@f5 and castled black short",
+		"1.e4 e5 2.Nf3 Nf6 3.Nc3 Be7 4.Bc4 O-O 5.Nh4 d6 6.Nf5",
+		11,
+	)
+
+	// Greek gift
+	s.assertMatch(
+		"move capture B@h7 and attacked B@h7 and within 5 mate",
+		"1.e4 e6 2.d4 d5 3.Nc3 Nf6 4.e5 Nfd7 5.Nf3 Be7 6.Bd3 O-O 7.Bxh7+ Kxh7 8.Ng5+ Kg8 9.Qh5 a6 10.Qh7#",
+		13,
+	)
+}
+
+func (s *QueryTestSuite) TestRookEndgame() {
+	query := "count R == 1 and count r == 1 and count QqBbNn == 0 and count Pp > 0 and (count Pp@a-d* == 0 or count Pp@e-h* == 0)"
+
+	compiled, err := CompileQuery(query)
+	s.Nil(err)
+
+	for _, example := range []struct {
+		fen     string
+		matches bool
+	}{
+		{"6k1/5ppp/8/8/8/8/5PPP/3R2K1 w - - 0 1", false},
+		{"3r2k1/5ppp/8/8/8/8/5PPP/3R2K1 w - - 0 1", true},
+		{"3r2k1/p4ppp/8/8/8/8/5PPP/3R2K1 w - - 0 1", false},
+	} {
+		board, err := ParseFEN(example.fen)
+		s.Require().Nil(err)
+		s.Equal(example.matches, compiled.filter(&queryGame{board: board}, 0), example.fen)
+	}
+}
+
+func (s *QueryTestSuite) TestSearch() {
+	pgns := NewPGNParserFromReader(strings.NewReader(win + draw + finalMoveByWhite + multipleEntries)).ParseAll()
+	query, err := CompileQuery("move P@e4 and ply == 1")
+	s.Nil(err)
+
+	matches := query.Search(pgns, 3)
+	s.Equal(3, len(matches))
+
+	sequential := []PositionMatch{}
+	for game, pgn := range pgns {
+		if ply, found := query.Match(pgn); found {
+			sequential = append(sequential, PositionMatch{game, ply})
+		}
+	}
+	s.Equal(sequential, matches)
+}
+
+func (s *QueryTestSuite) TestCompileErrors() {
+	for _, query := range []string{
+		"",
+		"N@f9",
+		"X@f5",
+		"N@i5",
+		"check and",
+		"(check",
+		"check)",
+		"count N",
+		"count N => 2",
+		"ply > x",
+		"castled white",
+		"castled red short",
+		"within mate",
+		"@f5",
+	} {
+		_, err := CompileQuery(query)
+		s.True(errors.Is(err, ErrorInvalidQuery), query)
+	}
+}
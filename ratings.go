@@ -0,0 +1,293 @@
+package pawn
+
+import (
+	"math"
+	"sort"
+)
+
+type RatingSystem int
+
+const (
+	Elo RatingSystem = iota
+	Glicko2
+)
+
+func (r RatingSystem) String() string {
+	if r == Glicko2 {
+		return "Glicko-2"
+	}
+
+	return "Elo"
+}
+
+const (
+	DefaultKFactor       = 20
+	DefaultInitialRating = 1500
+	DefaultTau           = 0.5
+
+	defaultDeviation  = 350
+	defaultVolatility = 0.06
+
+	glicko2Scale = 173.7178 // Between Glicko and Glicko-2's internal scale
+)
+
+// Zero valued options take the defaults. K only applies to Elo and Tau to
+// Glicko-2.
+type RatingOptions struct {
+	System  RatingSystem
+	K       float64 // How far one Elo game moves a rating
+	Initial float64 // Every player's rating before their first game
+
+	// Constrains how much Glicko-2 volatility changes between periods;
+	// Glickman suggests 0.3 to 1.2
+	Tau float64
+}
+
+func (o RatingOptions) withDefaults() RatingOptions {
+	if o.K == 0 {
+		o.K = DefaultKFactor
+	}
+	if o.Initial == 0 {
+		o.Initial = DefaultInitialRating
+	}
+	if o.Tau == 0 {
+		o.Tau = DefaultTau
+	}
+
+	return o
+}
+
+// A player's rating after a game, or after a rating period under Glicko-2
+type RatingPoint struct {
+	Date      string  `json:"date"`
+	Game      int     `json:"game"` // Index of the last game rated
+	Rating    float64 `json:"rating"`
+	Deviation float64 `json:"deviation,omitempty"`
+}
+
+type PlayerRating struct {
+	Name       string        `json:"name"`
+	Rating     float64       `json:"rating"`
+	Deviation  float64       `json:"deviation,omitempty"`  // Glicko-2 only
+	Volatility float64       `json:"volatility,omitempty"` // Glicko-2 only
+	Games      int           `json:"games"`
+	History    []RatingPoint `json:"history,omitempty"`
+}
+
+// Ratings computed from scratch from a collection's results
+type Ratings struct {
+	Options RatingOptions
+	Players map[string]*PlayerRating
+}
+
+// Rates every player in pgns by name as tagged, playing through the games in
+// date order, those on the same date in collection order. Games without a
+// result or a named player on each side aren't rated. Glicko-2 treats each
+// date as a rating period.
+func ComputeRatings(pgns []PGN, options RatingOptions) *Ratings {
+	ratings := &Ratings{Options: options.withDefaults(), Players: map[string]*PlayerRating{}}
+
+	order := make([]int, 0, len(pgns))
+	for index, pgn := range pgns {
+		if ratedGame(pgn) {
+			order = append(order, index)
+		}
+	}
+
+	sort.SliceStable(order, func(i, j int) bool {
+		return pgns[order[i]].Tags["Date"] < pgns[order[j]].Tags["Date"]
+	})
+
+	if ratings.Options.System == Glicko2 {
+		ratings.rateGlicko2(pgns, order)
+	} else {
+		ratings.rateElo(pgns, order)
+	}
+
+	return ratings
+}
+
+func ratedGame(pgn PGN) bool {
+	decided := pgn.Outcome == WhiteWin || pgn.Outcome == BlackWin || pgn.Outcome == Draw
+	return decided && !unknownTag(pgn.Tags["White"]) && !unknownTag(pgn.Tags["Black"])
+}
+
+func (r *Ratings) player(name string) *PlayerRating {
+	player, present := r.Players[name]
+	if !present {
+		player = &PlayerRating{Name: name, Rating: r.Options.Initial, History: []RatingPoint{}}
+		if r.Options.System == Glicko2 {
+			player.Deviation = defaultDeviation
+			player.Volatility = defaultVolatility
+		}
+		r.Players[name] = player
+	}
+
+	return player
+}
+
+// White's score from 0 to 1
+func whiteScore(outcome Outcome) float64 {
+	return float64(outcomeScore(outcome, White)) / 2
+}
+
+func expectedScore(rating, opponent float64) float64 {
+	return 1 / (1 + math.Pow(10, (opponent-rating)/400))
+}
+
+func (r *Ratings) rateElo(pgns []PGN, order []int) {
+	for _, index := range order {
+		pgn := pgns[index]
+		white, black := r.player(pgn.Tags["White"]), r.player(pgn.Tags["Black"])
+
+		change := r.Options.K * (whiteScore(pgn.Outcome) - expectedScore(white.Rating, black.Rating))
+		white.Rating += change
+		black.Rating -= change
+
+		for _, player := range []*PlayerRating{white, black} {
+			player.Games++
+			player.History = append(player.History, RatingPoint{Date: pgn.Tags["Date"], Game: index, Rating: player.Rating})
+		}
+	}
+}
+
+type glicko2Result struct {
+	opponentRating    float64
+	opponentDeviation float64
+	score             float64
+}
+
+// Rates each date's games as a period, every player's rating and deviation
+// at its start standing for them throughout it
+func (r *Ratings) rateGlicko2(pgns []PGN, order []int) {
+	for start := 0; start < len(order); {
+		date := pgns[order[start]].Tags["Date"]
+		end := start
+		for end < len(order) && pgns[order[end]].Tags["Date"] == date {
+			end++
+		}
+
+		results := map[*PlayerRating][]glicko2Result{}
+		last := map[*PlayerRating]int{}
+
+		for _, index := range order[start:end] {
+			pgn := pgns[index]
+			white, black := r.player(pgn.Tags["White"]), r.player(pgn.Tags["Black"])
+			score := whiteScore(pgn.Outcome)
+
+			results[white] = append(results[white], glicko2Result{black.Rating, black.Deviation, score})
+			results[black] = append(results[black], glicko2Result{white.Rating, white.Deviation, 1 - score})
+			last[white], last[black] = index, index
+		}
+
+		for _, player := range r.Players {
+			rating, deviation, volatility := glicko2Update(player.Rating, player.Deviation, player.Volatility, results[player], r.Options.Tau)
+			player.Rating, player.Deviation, player.Volatility = rating, math.Min(deviation, defaultDeviation), volatility
+		}
+
+		for player, played := range results {
+			player.Games += len(played)
+			player.History = append(player.History, RatingPoint{
+				Date:      date,
+				Game:      last[player],
+				Rating:    player.Rating,
+				Deviation: player.Deviation,
+			})
+		}
+
+		start = end
+	}
+}
+
+// One rating period of Glickman's Glicko-2 for a player, returning their new
+// rating, deviation and volatility. A player without results only becomes
+// less certain.
+//
+// Spec: http://www.glicko.net/glicko/glicko2.pdf
+func glicko2Update(rating, deviation, volatility float64, results []glicko2Result, tau float64) (float64, float64, float64) {
+	mu, phi := (rating-DefaultInitialRating)/glicko2Scale, deviation/glicko2Scale
+
+	if len(results) == 0 {
+		return rating, math.Sqrt(phi*phi+volatility*volatility) * glicko2Scale, volatility
+	}
+
+	g := func(phi float64) float64 {
+		return 1 / math.Sqrt(1+3*phi*phi/(math.Pi*math.Pi))
+	}
+
+	variance, improvement := 0.0, 0.0
+	for _, result := range results {
+		opponentMu := (result.opponentRating - DefaultInitialRating) / glicko2Scale
+		opponentG := g(result.opponentDeviation / glicko2Scale)
+		expected := 1 / (1 + math.Exp(-opponentG*(mu-opponentMu)))
+
+		variance += opponentG * opponentG * expected * (1 - expected)
+		improvement += opponentG * (result.score - expected)
+	}
+	variance = 1 / variance
+	delta := variance * improvement
+
+	volatility = glicko2Volatility(phi, volatility, variance, delta, tau)
+
+	phiStar := math.Sqrt(phi*phi + volatility*volatility)
+	phi = 1 / math.Sqrt(1/(phiStar*phiStar)+1/variance)
+	mu += phi * phi * improvement
+
+	return mu*glicko2Scale + DefaultInitialRating, phi * glicko2Scale, volatility
+}
+
+// Solves for the new volatility by the Illinois algorithm, step 5 of the
+// spec
+func glicko2Volatility(phi, volatility, variance, delta, tau float64) float64 {
+	const tolerance = 0.000001
+
+	a := math.Log(volatility * volatility)
+	f := func(x float64) float64 {
+		ex := math.Exp(x)
+		denominator := phi*phi + variance + ex
+		return ex*(delta*delta-phi*phi-variance-ex)/(2*denominator*denominator) - (x-a)/(tau*tau)
+	}
+
+	lower, upper := a, 0.0
+	if delta*delta > phi*phi+variance {
+		upper = math.Log(delta*delta - phi*phi - variance)
+	} else {
+		k := 1.0
+		for f(a-k*tau) < 0 {
+			k++
+		}
+		upper = a - k*tau
+	}
+
+	fLower, fUpper := f(lower), f(upper)
+	for math.Abs(upper-lower) > tolerance {
+		next := lower + (lower-upper)*fLower/(fUpper-fLower)
+		fNext := f(next)
+
+		if fNext*fUpper <= 0 {
+			lower, fLower = upper, fUpper
+		} else {
+			fLower /= 2
+		}
+		upper, fUpper = next, fNext
+	}
+
+	return math.Exp(lower / 2)
+}
+
+// Players by rating, highest first
+func (r *Ratings) Leaderboard() []PlayerRating {
+	leaderboard := make([]PlayerRating, 0, len(r.Players))
+	for _, player := range r.Players {
+		leaderboard = append(leaderboard, *player)
+	}
+
+	sort.Slice(leaderboard, func(i, j int) bool {
+		if leaderboard[i].Rating != leaderboard[j].Rating {
+			return leaderboard[i].Rating > leaderboard[j].Rating
+		}
+		return leaderboard[i].Name < leaderboard[j].Name
+	})
+
+	return leaderboard
+}
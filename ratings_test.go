@@ -0,0 +1,88 @@
+package pawn
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type RatingsTestSuite struct {
+	suite.Suite
+}
+
+func TestRatingsTestSuite(t *testing.T) {
+	suite.Run(t, new(RatingsTestSuite))
+}
+
+func (s *RatingsTestSuite) game(white, black, date string, outcome Outcome) PGN {
+	pgn := ParsePGN("1. e4 e5 " + string(outcome))
+	pgn.Tags = Tags{"White": white, "Black": black, "Date": date}
+
+	return pgn
+}
+
+func (s *RatingsTestSuite) TestElo() {
+	pgns := []PGN{
+		s.game("B", "C", "2020.01.02", WhiteWin),
+		s.game("A", "B", "2020.01.01", WhiteWin),
+		s.game("A", "C", "2020.01.03", Ongoing),
+	}
+
+	ratings := ComputeRatings(pgns, RatingOptions{K: 32})
+
+	// A beats B when both are 1500, then B at 1484 beats C at 1500
+	a, b, c := ratings.Players["A"], ratings.Players["B"], ratings.Players["C"]
+	s.InDelta(1516, a.Rating, 0.001)
+	s.InDelta(1484+32*(1-expectedScore(1484, 1500)), b.Rating, 0.001)
+	s.InDelta(3000, b.Rating+c.Rating+a.Rating-1500, 0.001, "Elo is zero sum")
+
+	s.Equal(1, a.Games, "the unfinished game isn't rated")
+	s.Equal([]RatingPoint{{Date: "2020.01.01", Game: 1, Rating: 1484}, {Date: "2020.01.02", Game: 0, Rating: b.Rating}}, b.History)
+
+	leaderboard := ratings.Leaderboard()
+	s.Equal("A", leaderboard[0].Name)
+	s.Equal("C", leaderboard[2].Name)
+}
+
+func (s *RatingsTestSuite) TestEloDefaults() {
+	ratings := ComputeRatings([]PGN{s.game("A", "B", "2020.01.01", Draw)}, RatingOptions{})
+
+	s.Equal(float64(DefaultInitialRating), ratings.Players["A"].Rating)
+	s.Equal(float64(DefaultKFactor), ratings.Options.K)
+}
+
+// The example worked in Glickman's description of the system
+func (s *RatingsTestSuite) TestGlicko2Update() {
+	rating, deviation, volatility := glicko2Update(1500, 200, 0.06, []glicko2Result{
+		{1400, 30, 1},
+		{1550, 100, 0},
+		{1700, 300, 0},
+	}, 0.5)
+
+	s.InDelta(1464.06, rating, 0.01)
+	s.InDelta(151.52, deviation, 0.01)
+	s.InDelta(0.05999, volatility, 0.00001)
+
+	rating, deviation, _ = glicko2Update(1500, 200, 0.06, nil, 0.5)
+	s.Equal(1500.0, rating)
+	s.InDelta(200.27, deviation, 0.01)
+}
+
+func (s *RatingsTestSuite) TestGlicko2() {
+	pgns := []PGN{
+		s.game("A", "B", "2020.01.01", WhiteWin),
+		s.game("A", "C", "2020.01.01", WhiteWin),
+		s.game("B", "C", "2020.01.02", Draw),
+	}
+
+	ratings := ComputeRatings(pgns, RatingOptions{System: Glicko2})
+	a, b := ratings.Players["A"], ratings.Players["B"]
+
+	s.Greater(a.Rating, 1500.0)
+	s.Less(a.Deviation, 350.0)
+	s.Equal(2, a.Games)
+	s.Len(a.History, 1, "one point per period played")
+	s.Len(b.History, 2)
+	s.Equal(1, a.History[0].Game)
+	s.Equal("A", ratings.Leaderboard()[0].Name)
+}
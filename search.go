@@ -0,0 +1,244 @@
+package pawn
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+var ErrorInvalidMaterialSignature = errors.New("pawn: invalid material signature")
+
+// How many of each piece both sides have, indexed by color and material
+type MaterialSignature [2][King + 1]uint8
+
+// The order pieces are written in signatures, e.g. "KRPPvKR"
+var signatureOrder = [...]Material{King, Queen, Rook, Bishop, Knight, Pawn}
+
+func (b Board) MaterialSignature() MaterialSignature {
+	var signature MaterialSignature
+
+	for _, square := range b.Squares {
+		if square.Piece != NoPiece {
+			signature[square.Color.index()][square.Material]++
+		}
+	}
+
+	return signature
+}
+
+// Parses a signature such as "KQPPvKR" listing White's pieces then Black's
+func ParseMaterialSignature(str string) (MaterialSignature, error) {
+	var signature MaterialSignature
+
+	sides := strings.Split(strings.ToUpper(str), "V")
+	if len(sides) != 2 {
+		return signature, fmt.Errorf("%w: \"%s\"", ErrorInvalidMaterialSignature, str)
+	}
+
+	for color, side := range sides {
+		for _, letter := range side {
+			material, found := materialFromLetter(letter)
+			if !found {
+				return signature, fmt.Errorf("%w: \"%s\"", ErrorInvalidMaterialSignature, str)
+			}
+
+			signature[color][material]++
+		}
+	}
+
+	return signature, nil
+}
+
+func materialFromLetter(letter rune) (Material, bool) {
+	if letter == 'P' {
+		return Pawn, true
+	}
+
+	for material, name := range materialNames {
+		if name != "" && name == string(letter) {
+			return material, true
+		}
+	}
+
+	return 0, false
+}
+
+func (m MaterialSignature) String() string {
+	sides := make([]string, len(m))
+
+	for color := range m {
+		for _, material := range signatureOrder {
+			letter := material.AN()
+			if material == Pawn {
+				letter = "P"
+			}
+
+			sides[color] += strings.Repeat(letter, int(m[color][material]))
+		}
+	}
+
+	return strings.Join(sides, "v")
+}
+
+// The squares each side has pawns on, one bit per index into Board.Squares
+type PawnStructure [2]uint64
+
+func (b Board) PawnStructure() PawnStructure {
+	var structure PawnStructure
+
+	for index, square := range b.Squares {
+		if square.Material == Pawn {
+			structure[square.Color.index()] |= 1 << uint(index)
+		}
+	}
+
+	return structure
+}
+
+// A position reached in a game. Game is the index of the game in the order
+// it was added and Ply the number of moves played to reach the position,
+// zero for the initial position.
+type PositionMatch struct {
+	Game int
+	Ply  int
+}
+
+// What positions a search matches. Criteria left nil match any position.
+type PositionQuery struct {
+	Position *Board // Matched by hash, so side to move, castling and en passant count
+	Material *MaterialSignature
+	Pawns    *PawnStructure
+}
+
+type indexedPosition struct {
+	hash     uint64
+	material MaterialSignature
+	pawns    PawnStructure
+}
+
+// An index of every position reached in a collection of games
+type PositionIndex struct {
+	PGNs      []PGN
+	plies     [][]indexedPosition // Indexed by game and ply
+	positions map[uint64][]PositionMatch
+	materials map[MaterialSignature][]PositionMatch
+}
+
+func NewPositionIndex() *PositionIndex {
+	return &PositionIndex{
+		positions: map[uint64][]PositionMatch{},
+		materials: map[MaterialSignature][]PositionMatch{},
+	}
+}
+
+// Indexes every game in pgns, each up to its first illegal move
+func PositionIndexFromPGNs(pgns []PGN) *PositionIndex {
+	index := NewPositionIndex()
+
+	for _, pgn := range pgns {
+		index.Add(pgn)
+	}
+
+	return index
+}
+
+// Adds the positions of a game to the index. Positions up to the first
+// illegal move are kept and its error returned.
+func (i *PositionIndex) Add(pgn PGN) error {
+	game := len(i.PGNs)
+	i.PGNs = append(i.PGNs, pgn)
+	i.plies = append(i.plies, []indexedPosition{})
+
+	board := NewBoard()
+	i.addPosition(game, board)
+
+	for _, an := range pgn.Turns() {
+		if an == "" {
+			continue
+		}
+
+		move, err := board.ResolveAlgebraic(an)
+		if err != nil {
+			return err
+		}
+
+		board.MakeMove(move)
+		i.addPosition(game, board)
+	}
+
+	return nil
+}
+
+func (i *PositionIndex) addPosition(game int, board *Board) {
+	position := indexedPosition{board.Hash(), board.MaterialSignature(), board.PawnStructure()}
+	match := PositionMatch{game, len(i.plies[game])}
+
+	i.plies[game] = append(i.plies[game], position)
+	i.positions[position.hash] = append(i.positions[position.hash], match)
+	i.materials[position.material] = append(i.materials[position.material], match)
+}
+
+// The games that reached the board's position
+func (i *PositionIndex) Find(board *Board) []PositionMatch {
+	return i.Search(PositionQuery{Position: board})
+}
+
+// The games that reached a position matching every criterion of the query,
+// in the order they were added, each with the first ply it matched at
+func (i *PositionIndex) Search(query PositionQuery) []PositionMatch {
+	var candidates []PositionMatch
+
+	switch {
+	case query.Position != nil:
+		candidates = i.positions[query.Position.Hash()]
+	case query.Material != nil:
+		candidates = i.materials[*query.Material]
+	default:
+		for game, plies := range i.plies {
+			for ply := range plies {
+				candidates = append(candidates, PositionMatch{game, ply})
+			}
+		}
+	}
+
+	first := map[int]int{}
+	for _, candidate := range candidates {
+		if !i.matches(candidate, query) {
+			continue
+		}
+
+		if ply, seen := first[candidate.Game]; !seen || candidate.Ply < ply {
+			first[candidate.Game] = candidate.Ply
+		}
+	}
+
+	matches := make([]PositionMatch, 0, len(first))
+	for game, ply := range first {
+		matches = append(matches, PositionMatch{game, ply})
+	}
+
+	sort.Slice(matches, func(a, b int) bool {
+		return matches[a].Game < matches[b].Game
+	})
+
+	return matches
+}
+
+func (i *PositionIndex) matches(match PositionMatch, query PositionQuery) bool {
+	position := i.plies[match.Game][match.Ply]
+
+	if query.Position != nil && position.hash != query.Position.Hash() {
+		return false
+	}
+
+	if query.Material != nil && position.material != *query.Material {
+		return false
+	}
+
+	if query.Pawns != nil && position.pawns != *query.Pawns {
+		return false
+	}
+
+	return true
+}
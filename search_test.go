@@ -0,0 +1,76 @@
+package pawn
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMaterialSignature(t *testing.T) {
+	assert.Equal(t, "KQRRBBNNPPPPPPPPvKQRRBBNNPPPPPPPP", NewBoard().MaterialSignature().String())
+
+	signature, err := ParseMaterialSignature("KRPPvkr")
+	assert.Nil(t, err)
+	assert.Equal(t, uint8(2), signature[White.index()][Pawn])
+	assert.Equal(t, uint8(1), signature[Black.index()][Rook])
+	assert.Equal(t, "KRPPvKR", signature.String())
+
+	_, err = ParseMaterialSignature("KRX")
+	assert.True(t, errors.Is(err, ErrorInvalidMaterialSignature))
+	_, err = ParseMaterialSignature("KRXvK")
+	assert.True(t, errors.Is(err, ErrorInvalidMaterialSignature))
+}
+
+func TestPositionIndex(t *testing.T) {
+	pgns := NewPGNParserFromReader(strings.NewReader(win + draw + finalMoveByWhite + multipleEntries)).ParseAll()
+	pgns = append(pgns, ParsePGN("1. d4 d5 2. Qd3 Qd6 3. Qh3 Qh6 4. Qxh6 Nxh6 *"))
+	index := PositionIndexFromPGNs(pgns)
+
+	assert.Equal(t, len(pgns), len(index.Find(NewBoard())))
+	for _, match := range index.Find(NewBoard()) {
+		assert.Equal(t, 0, match.Ply)
+	}
+
+	board := NewBoard()
+	board.MoveFromAlgebraic("e4")
+	matches := index.Find(board)
+	assert.Equal(t, 3, len(matches))
+	assert.Equal(t, 1, matches[0].Ply)
+
+	board.MoveFromAlgebraic("c5")
+	fen, _ := ParseFEN(board.FEN())
+	assert.Equal(t, index.Find(board), index.Find(fen))
+
+	// A queen trade as the first capture in some game
+	start := NewBoard().MaterialSignature()
+	noQueens := start
+	noQueens[White.index()][Queen] = 0
+	noQueens[Black.index()][Queen] = 0
+	queenTrades := index.Search(PositionQuery{Material: &noQueens})
+	require.NotEmpty(t, queenTrades)
+	assert.Equal(t, 8, queenTrades[len(queenTrades)-1].Ply)
+	for _, match := range queenTrades {
+		assert.Equal(t, noQueens, index.plies[match.Game][match.Ply].material)
+		assert.NotEqual(t, noQueens, index.plies[match.Game][match.Ply-1].material, "first ply is reported")
+	}
+
+	pawns := NewBoard().PawnStructure()
+	matches = index.Search(PositionQuery{Material: &start, Pawns: &pawns})
+	assert.Equal(t, len(pgns), len(matches))
+}
+
+func TestPositionIndexTranspositions(t *testing.T) {
+	index := NewPositionIndex()
+	index.Add(ParsePGN("[Result \"1-0\"]\n\n1.e4 e6 2.d4 d5 1-0"))
+	index.Add(ParsePGN("[Result \"0-1\"]\n\n1.d4 e6 2.e4 c5 0-1"))
+	assert.NotNil(t, index.Add(ParsePGN("[Result \"0-1\"]\n\n1.d4 e6 2.Ke3 0-1")))
+
+	board, _ := ParseFEN("rnbqkbnr/pppp1ppp/4p3/8/3PP3/8/PPP2PPP/RNBQKBNR b KQkq - 0 2")
+	assert.Equal(t, []PositionMatch{{0, 3}, {1, 3}}, index.Find(board))
+
+	pawns := board.PawnStructure()
+	assert.Equal(t, []PositionMatch{{0, 3}, {1, 3}}, index.Search(PositionQuery{Pawns: &pawns}))
+}
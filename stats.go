@@ -0,0 +1,294 @@
+package pawn
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Results of a set of games from one player's point of view. Games without
+// a result are counted in Games but not as wins, draws or losses.
+type Score struct {
+	Games  int `json:"games"`
+	Wins   int `json:"wins"`
+	Draws  int `json:"draws"`
+	Losses int `json:"losses"`
+}
+
+// Wins plus half the draws
+func (s Score) Points() float64 {
+	return float64(s.Wins) + float64(s.Draws)/2
+}
+
+// Points as a percentage of finished games, or zero if none are
+func (s Score) Percentage() float64 {
+	if s.finished() == 0 {
+		return 0
+	}
+
+	return 100 * s.Points() / float64(s.finished())
+}
+
+func (s Score) finished() int {
+	return s.Wins + s.Draws + s.Losses
+}
+
+func (s *Score) add(outcome Outcome, side Color) {
+	s.Games++
+
+	switch {
+	case outcome == Draw:
+		s.Draws++
+	case outcome == WhiteWin && side == White, outcome == BlackWin && side == Black:
+		s.Wins++
+	case outcome == WhiteWin, outcome == BlackWin:
+		s.Losses++
+	}
+}
+
+// The longest runs of results in a player's games in date order
+type Streaks struct {
+	Winning  int `json:"winning"`
+	Unbeaten int `json:"unbeaten"`
+	Losing   int `json:"losing"`
+	Winless  int `json:"winless"`
+}
+
+// A player's record across a collection
+type PlayerStats struct {
+	Name  string `json:"name"`
+	Score Score  `json:"score"`
+	White Score  `json:"white"`
+	Black Score  `json:"black"`
+
+	Openings     map[string]*Score `json:"openings"`      // By ECO code
+	Opponents    map[string]*Score `json:"opponents"`     // By name as tagged
+	TimeControls map[string]*Score `json:"time_controls"` // By TimeControlCategory
+
+	// The rating the player's results against rated opponents are worth: the
+	// opponents' average rating plus 400 times wins less losses per game.
+	// Zero without any finished games against rated opponents.
+	Performance int `json:"performance"`
+
+	Streaks      Streaks `json:"streaks"`
+	AverageMoves float64 `json:"average_moves"`
+}
+
+func newPlayerStats(name string) *PlayerStats {
+	return &PlayerStats{
+		Name:         name,
+		Openings:     map[string]*Score{},
+		Opponents:    map[string]*Score{},
+		TimeControls: map[string]*Score{},
+	}
+}
+
+// A game as one of its players saw it
+type playerGame struct {
+	pgn  PGN
+	side Color
+}
+
+// Statistics for the player named across pgns. Names match loosely so
+// "Carlsen" finds the games of "Carlsen,M" and "Carlsen, Magnus"; the name
+// reported is the most common of those matched.
+func PlayerStatistics(pgns []PGN, name string) PlayerStats {
+	games := []playerGame{}
+	names := map[string]int{}
+
+	for _, pgn := range pgns {
+		for _, side := range []Color{White, Black} {
+			if tagged := pgn.Tags[string(side)]; !unknownTag(tagged) && similarNames(name, tagged) {
+				games = append(games, playerGame{pgn, side})
+				names[tagged]++
+				break
+			}
+		}
+	}
+
+	reported, most := name, 0
+	for tagged, count := range names {
+		if count > most || (count == most && tagged < reported) {
+			reported, most = tagged, count
+		}
+	}
+
+	return playerStatistics(reported, games)
+}
+
+// Statistics for every player in pgns by name exactly as tagged, most games
+// first
+func AllPlayerStatistics(pgns []PGN) []PlayerStats {
+	games := map[string][]playerGame{}
+
+	for _, pgn := range pgns {
+		for _, side := range []Color{White, Black} {
+			if name := pgn.Tags[string(side)]; !unknownTag(name) {
+				games[name] = append(games[name], playerGame{pgn, side})
+			}
+		}
+	}
+
+	all := []PlayerStats{}
+	for name, played := range games {
+		all = append(all, playerStatistics(name, played))
+	}
+
+	sort.Slice(all, func(i, j int) bool {
+		if all[i].Score.Games != all[j].Score.Games {
+			return all[i].Score.Games > all[j].Score.Games
+		}
+		return all[i].Name < all[j].Name
+	})
+
+	return all
+}
+
+func playerStatistics(name string, games []playerGame) PlayerStats {
+	stats := newPlayerStats(name)
+
+	sort.SliceStable(games, func(i, j int) bool {
+		return games[i].pgn.Tags["Date"] < games[j].pgn.Tags["Date"]
+	})
+
+	addTo := func(scores map[string]*Score, key string, game playerGame) {
+		if scores[key] == nil {
+			scores[key] = &Score{}
+		}
+		scores[key].add(game.pgn.Outcome, game.side)
+	}
+
+	plies, opponentElo, rated := 0, 0, Score{}
+
+	for _, game := range games {
+		opponent := White
+		if game.side == White {
+			opponent = Black
+		}
+
+		stats.Score.add(game.pgn.Outcome, game.side)
+		if game.side == White {
+			stats.White.add(game.pgn.Outcome, game.side)
+		} else {
+			stats.Black.add(game.pgn.Outcome, game.side)
+		}
+
+		eco := game.pgn.Tags["ECO"]
+		if unknownTag(eco) {
+			eco = "unknown"
+		}
+		addTo(stats.Openings, eco, game)
+		addTo(stats.Opponents, strings.TrimSpace(game.pgn.Tags[string(opponent)]), game)
+		addTo(stats.TimeControls, TimeControlCategory(game.pgn.Tags), game)
+
+		if elo, err := strconv.Atoi(game.pgn.Tags[string(opponent)+"Elo"]); err == nil && elo > 0 {
+			before := rated.finished()
+			rated.add(game.pgn.Outcome, game.side)
+
+			if rated.finished() > before {
+				opponentElo += elo
+			}
+		}
+
+		plies += game.pgn.PlyCount()
+	}
+
+	if finished := rated.finished(); finished > 0 {
+		stats.Performance = (opponentElo + 400*(rated.Wins-rated.Losses)) / finished
+	}
+
+	if len(games) > 0 {
+		stats.AverageMoves = float64(plies) / 2 / float64(len(games))
+	}
+
+	stats.Streaks = streaks(games)
+
+	return *stats
+}
+
+// Games without a result don't break a streak
+func streaks(games []playerGame) Streaks {
+	var longest, current Streaks
+
+	extend := func(run *int, best *int, continues bool) {
+		if continues {
+			*run++
+		} else {
+			*run = 0
+		}
+
+		if *run > *best {
+			*best = *run
+		}
+	}
+
+	for _, game := range games {
+		var result Score
+		result.add(game.pgn.Outcome, game.side)
+
+		if result.finished() == 0 {
+			continue
+		}
+
+		extend(&current.Winning, &longest.Winning, result.Wins == 1)
+		extend(&current.Unbeaten, &longest.Unbeaten, result.Losses == 0)
+		extend(&current.Losing, &longest.Losing, result.Losses == 1)
+		extend(&current.Winless, &longest.Winless, result.Wins == 0)
+	}
+
+	return longest
+}
+
+// Thinking time by FIDE's categories for a game: bullet, blitz, rapid or
+// classical. Taken from the TimeControl tag, estimating a game's length as
+// its base time plus 40 increments, or failing that from words in the
+// Event tag. Unknown if neither says.
+func TimeControlCategory(tags Tags) string {
+	if seconds, known := estimatedGameSeconds(tags["TimeControl"]); known {
+		switch {
+		case seconds < 3*60:
+			return "bullet"
+		case seconds <= 10*60:
+			return "blitz"
+		case seconds < 60*60:
+			return "rapid"
+		default:
+			return "classical"
+		}
+	}
+
+	event := strings.ToLower(tags["Event"])
+	for _, category := range []string{"bullet", "blitz", "rapid"} {
+		if strings.Contains(event, category) {
+			return category
+		}
+	}
+
+	return "unknown"
+}
+
+// From the first period of a PGN TimeControl such as 40/7200:3600, 180+2 or
+// 300
+func estimatedGameSeconds(timeControl string) (int, bool) {
+	period, _, _ := strings.Cut(timeControl, ":")
+	if _, afterMoves, hasMoves := strings.Cut(period, "/"); hasMoves {
+		period = afterMoves
+	}
+
+	base, increment, hasIncrement := strings.Cut(period, "+")
+
+	seconds, err := strconv.Atoi(base)
+	if err != nil || seconds <= 0 {
+		return 0, false
+	}
+
+	if hasIncrement {
+		perMove, err := strconv.Atoi(increment)
+		if err != nil {
+			return 0, false
+		}
+		seconds += 40 * perMove
+	}
+
+	return seconds, true
+}
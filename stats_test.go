@@ -0,0 +1,84 @@
+package pawn
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type StatsTestSuite struct {
+	suite.Suite
+	pgns []PGN
+}
+
+func TestStatsTestSuite(t *testing.T) {
+	suite.Run(t, new(StatsTestSuite))
+}
+
+func (s *StatsTestSuite) game(white, black, date, eco string, outcome Outcome, elos ...string) PGN {
+	pgn := ParsePGN("1. e4 e5 2. Nf3 Nc6 " + string(outcome))
+	pgn.Tags = Tags{"White": white, "Black": black, "Date": date, "ECO": eco, "Event": "Rapid"}
+	if len(elos) == 2 {
+		pgn.Tags["WhiteElo"], pgn.Tags["BlackElo"] = elos[0], elos[1]
+	}
+
+	return pgn
+}
+
+func (s *StatsTestSuite) SetupTest() {
+	s.pgns = []PGN{
+		s.game("Carlsen,M", "Anand,V", "2013.11.22", "C65", Draw, "2870", "2775"),
+		s.game("Anand,V", "Carlsen,M", "2013.11.21", "E25", BlackWin, "2775", "2870"),
+		s.game("Carlsen,M", "Anand,V", "2013.11.23", "C65", WhiteWin, "2870", "2775"),
+		s.game("Carlsen, Magnus", "Kramnik,V", "2013.12.01", "D37", BlackWin),
+		s.game("Kramnik,V", "Anand,V", "2013.12.02", "", Ongoing),
+	}
+}
+
+func (s *StatsTestSuite) TestPlayerStatistics() {
+	stats := PlayerStatistics(s.pgns, "Carlsen")
+
+	s.Equal("Carlsen,M", stats.Name)
+	s.Equal(Score{Games: 4, Wins: 2, Draws: 1, Losses: 1}, stats.Score)
+	s.Equal(Score{Games: 3, Wins: 1, Draws: 1, Losses: 1}, stats.White)
+	s.Equal(Score{Games: 1, Wins: 1}, stats.Black)
+	s.InDelta(62.5, stats.Score.Percentage(), 0.001)
+
+	s.Equal(Score{Games: 2, Wins: 1, Draws: 1}, *stats.Openings["C65"])
+	s.Equal(Score{Games: 3, Wins: 2, Draws: 1}, *stats.Opponents["Anand,V"])
+	s.Equal(Score{Games: 4, Wins: 2, Draws: 1, Losses: 1}, *stats.TimeControls["rapid"])
+
+	// Against Anand only, who is rated: 2775 + 400 * 2 / 3
+	s.Equal(3041, stats.Performance)
+	s.Equal(Streaks{Winning: 1, Unbeaten: 3, Losing: 1, Winless: 1}, stats.Streaks)
+	s.Equal(2.0, stats.AverageMoves)
+}
+
+func (s *StatsTestSuite) TestUnfinishedGames() {
+	stats := PlayerStatistics(s.pgns, "Kramnik")
+
+	s.Equal(Score{Games: 2, Wins: 1}, stats.Score)
+	s.Equal(0, stats.Performance)
+	s.Equal(Streaks{Winning: 1, Unbeaten: 1}, stats.Streaks)
+	s.Equal(Score{Games: 1}, *stats.Openings["unknown"])
+}
+
+func (s *StatsTestSuite) TestAllPlayerStatistics() {
+	all := AllPlayerStatistics(s.pgns)
+
+	names := []string{}
+	for _, stats := range all {
+		names = append(names, stats.Name)
+	}
+
+	s.Equal([]string{"Anand,V", "Carlsen,M", "Kramnik,V", "Carlsen, Magnus"}, names)
+}
+
+func (s *StatsTestSuite) TestTimeControlCategory() {
+	s.Equal("bullet", TimeControlCategory(Tags{"TimeControl": "60"}))
+	s.Equal("blitz", TimeControlCategory(Tags{"TimeControl": "180+2"}))
+	s.Equal("rapid", TimeControlCategory(Tags{"TimeControl": "900+10"}))
+	s.Equal("classical", TimeControlCategory(Tags{"TimeControl": "40/7200:3600"}))
+	s.Equal("blitz", TimeControlCategory(Tags{"TimeControl": "-", "Event": "World Blitz 2016"}))
+	s.Equal("unknown", TimeControlCategory(Tags{"Event": "WCh 2013"}))
+}
@@ -0,0 +1,327 @@
+package pawn
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+)
+
+var (
+	ErrorInvalidTournament = errors.New("pawn: invalid tournament")
+	ErrorRoundUnfinished   = errors.New("pawn: round has games without a result")
+	ErrorTournamentOver    = errors.New("pawn: every round has been paired")
+	ErrorNoSuchPairing     = errors.New("pawn: no such pairing")
+	ErrorNoPairing         = errors.New("pawn: no pairing satisfies the rules")
+)
+
+type TournamentFormat string
+
+const (
+	RoundRobin TournamentFormat = "round-robin"
+	Swiss      TournamentFormat = "swiss"
+)
+
+// The opponent of a player given a bye
+const Bye = -1
+
+type TournamentPlayer struct {
+	Name   string `json:"name"`
+	Rating int    `json:"rating,omitempty"`
+}
+
+// A game of a round, or a bye when Black is Bye. A bye scores its player a
+// point and is recorded as a win for White.
+type Pairing struct {
+	White    int     `json:"white"` // Indexes into the tournament's Players
+	Black    int     `json:"black"`
+	Result   Outcome `json:"result,omitempty"` // Empty until played
+	Movetext string  `json:"movetext,omitempty"`
+}
+
+func (p Pairing) IsBye() bool {
+	return p.Black == Bye
+}
+
+func (p Pairing) played() bool {
+	return p.Result == WhiteWin || p.Result == BlackWin || p.Result == Draw
+}
+
+// A tournament's players and rounds so far, which can be saved between rounds.
+// Players are kept in seeding order, highest rated first.
+type Tournament struct {
+	Name    string             `json:"name"`
+	Format  TournamentFormat   `json:"format"`
+	Rounds  int                `json:"rounds"`
+	Players []TournamentPlayer `json:"players"`
+	Played  [][]Pairing        `json:"played"` // Each round's pairings, board by board
+}
+
+// Sets up a tournament. A round robin's rounds are played as many times over
+// as cycles, each time with colors reversed; a Swiss runs for the given number
+// of rounds.
+func NewTournament(name string, format TournamentFormat, players []TournamentPlayer, rounds int) (*Tournament, error) {
+	if len(players) < 2 {
+		return nil, fmt.Errorf("%w: fewer than two players", ErrorInvalidTournament)
+	}
+
+	seen := map[string]bool{}
+	for _, player := range players {
+		if player.Name == "" || seen[player.Name] {
+			return nil, fmt.Errorf("%w: player names must be given and differ: %q", ErrorInvalidTournament, player.Name)
+		}
+		seen[player.Name] = true
+	}
+
+	seeded := append([]TournamentPlayer{}, players...)
+	sort.SliceStable(seeded, func(i, j int) bool {
+		return seeded[i].Rating > seeded[j].Rating
+	})
+
+	switch {
+	case format == RoundRobin && rounds >= 1:
+		rounds *= bergerRounds(len(seeded))
+	case format == Swiss && rounds >= 1:
+	default:
+		return nil, fmt.Errorf("%w: need a format of %s or %s and at least one round or cycle", ErrorInvalidTournament, RoundRobin, Swiss)
+	}
+
+	return &Tournament{Name: name, Format: format, Rounds: rounds, Players: seeded, Played: [][]Pairing{}}, nil
+}
+
+// Pairs the next round, which can't be done until every game of the last one
+// has a result
+func (t *Tournament) PairNextRound() ([]Pairing, error) {
+	if len(t.Played) >= t.Rounds {
+		return nil, ErrorTournamentOver
+	}
+
+	if round := len(t.Played); round > 0 {
+		for _, pairing := range t.Played[round-1] {
+			if !pairing.played() {
+				return nil, fmt.Errorf("%w: round %d", ErrorRoundUnfinished, round)
+			}
+		}
+	}
+
+	var pairings []Pairing
+	if t.Format == RoundRobin {
+		pairings = bergerRound(len(t.Players), len(t.Played))
+	} else {
+		var err error
+		if pairings, err = t.pairSwiss(); err != nil {
+			return nil, err
+		}
+	}
+
+	t.Played = append(t.Played, pairings)
+
+	return pairings, nil
+}
+
+func (t *Tournament) pairing(round, board int) (*Pairing, error) {
+	if round < 1 || round > len(t.Played) || board < 1 || board > len(t.Played[round-1]) {
+		return nil, fmt.Errorf("%w: round %d board %d", ErrorNoSuchPairing, round, board)
+	}
+
+	return &t.Played[round-1][board-1], nil
+}
+
+// Records the result of a game, rounds and boards counted from 1
+func (t *Tournament) RecordResult(round, board int, result Outcome) error {
+	pairing, err := t.pairing(round, board)
+	if err != nil {
+		return err
+	}
+
+	if pairing.IsBye() || (result != WhiteWin && result != BlackWin && result != Draw) {
+		return fmt.Errorf("%w: can't record %q for round %d board %d", ErrorInvalidTournament, result, round, board)
+	}
+
+	pairing.Result = result
+
+	return nil
+}
+
+// Records a played game, finding its pairing from its Round, White and Black
+// tags, and keeping its moves
+func (t *Tournament) RecordGame(pgn PGN) error {
+	round := roundNumber(pgn.Tags["Round"])
+	if round < 1 || round > len(t.Played) {
+		return fmt.Errorf("%w: round %q", ErrorNoSuchPairing, pgn.Tags["Round"])
+	}
+
+	for board, pairing := range t.Played[round-1] {
+		if pairing.IsBye() || t.Players[pairing.White].Name != pgn.Tags["White"] || t.Players[pairing.Black].Name != pgn.Tags["Black"] {
+			continue
+		}
+
+		if err := t.RecordResult(round, board+1, pgn.Outcome); err != nil {
+			return err
+		}
+		t.Played[round-1][board].Movetext = pgn.Movetext.String()
+
+		return nil
+	}
+
+	return fmt.Errorf("%w: %s vs %s in round %d", ErrorNoSuchPairing, pgn.Tags["White"], pgn.Tags["Black"], round)
+}
+
+// The games played so far, byes aside, with their moves if recorded
+func (t *Tournament) PGNs() []PGN {
+	pgns := []PGN{}
+
+	for round, pairings := range t.Played {
+		for board, pairing := range pairings {
+			if pairing.IsBye() || !pairing.played() {
+				continue
+			}
+
+			pgn := ParsePGN(pairing.Movetext + " " + string(pairing.Result))
+			pgn.Tags = t.gameTags(round, board, pairing)
+			pgns = append(pgns, pgn)
+		}
+	}
+
+	return pgns
+}
+
+func (t *Tournament) gameTags(round, board int, pairing Pairing) Tags {
+	white, black := t.Players[pairing.White], t.Players[pairing.Black]
+	tags := Tags{
+		"Event":  t.Name,
+		"Site":   "?",
+		"Date":   "????.??.??",
+		"Round":  fmt.Sprintf("%d.%d", round+1, board+1),
+		"White":  white.Name,
+		"Black":  black.Name,
+		"Result": string(pairing.Result),
+	}
+
+	if white.Rating > 0 {
+		tags["WhiteElo"] = strconv.Itoa(white.Rating)
+	}
+	if black.Rating > 0 {
+		tags["BlackElo"] = strconv.Itoa(black.Rating)
+	}
+
+	return tags
+}
+
+// Points per player including byes, by index into Players
+func (t *Tournament) points() []float64 {
+	points := make([]float64, len(t.Players))
+
+	for _, pairings := range t.Played {
+		for _, pairing := range pairings {
+			if pairing.IsBye() {
+				points[pairing.White]++
+				continue
+			}
+
+			score := whiteScore(pairing.Result)
+			if pairing.played() {
+				points[pairing.White] += score
+				points[pairing.Black] += 1 - score
+			}
+		}
+	}
+
+	return points
+}
+
+// Standings so far as a crosstable, byes counting a point. Every player is
+// listed, even before they've played.
+func (t *Tournament) Crosstable() *Crosstable {
+	byes := map[string]float64{}
+	for _, player := range t.Players {
+		byes[player.Name] = 0
+	}
+	for _, pairings := range t.Played {
+		for _, pairing := range pairings {
+			if pairing.IsBye() {
+				byes[t.Players[pairing.White].Name]++
+			}
+		}
+	}
+
+	if t.Format == RoundRobin {
+		crosstable := newCrosstable(t.PGNs(), byes, roundRobinTiebreaks...)
+		crosstable.Event, crosstable.RoundRobin = t.Name, true
+		return crosstable
+	}
+
+	crosstable := newCrosstable(t.PGNs(), byes, swissTiebreaks...)
+	crosstable.Event, crosstable.RoundRobin = t.Name, false
+
+	return crosstable
+}
+
+// Reads a tournament saved by Save
+func LoadTournament(path string) (*Tournament, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	tournament := &Tournament{}
+	if err := json.Unmarshal(data, tournament); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrorInvalidTournament, err)
+	}
+
+	if err := tournament.check(); err != nil {
+		return nil, err
+	}
+
+	return tournament, nil
+}
+
+func (t *Tournament) check() error {
+	if (t.Format != RoundRobin && t.Format != Swiss) || len(t.Players) < 2 {
+		return fmt.Errorf("%w: bad format or too few players", ErrorInvalidTournament)
+	}
+
+	valid := func(player int) bool {
+		return player >= 0 && player < len(t.Players)
+	}
+
+	for _, pairings := range t.Played {
+		for _, pairing := range pairings {
+			if !valid(pairing.White) || !(valid(pairing.Black) || pairing.IsBye()) {
+				return fmt.Errorf("%w: pairing of unknown players", ErrorInvalidTournament)
+			}
+		}
+	}
+
+	return nil
+}
+
+// Writes the tournament as JSON, replacing the file only once it's written in
+// full
+func (t *Tournament) Save(path string) error {
+	data, err := json.MarshalIndent(t, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	temporary, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*")
+	if err != nil {
+		return err
+	}
+
+	if _, err := temporary.Write(append(data, '\n')); err != nil {
+		temporary.Close()
+		os.Remove(temporary.Name())
+		return err
+	}
+
+	if err := temporary.Close(); err != nil {
+		os.Remove(temporary.Name())
+		return err
+	}
+
+	return os.Rename(temporary.Name(), path)
+}
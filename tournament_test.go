@@ -0,0 +1,216 @@
+package pawn
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type TournamentTestSuite struct {
+	suite.Suite
+}
+
+func TestTournamentTestSuite(t *testing.T) {
+	suite.Run(t, new(TournamentTestSuite))
+}
+
+func (s *TournamentTestSuite) players(count int) []TournamentPlayer {
+	players := []TournamentPlayer{}
+	for i := 0; i < count; i++ {
+		players = append(players, TournamentPlayer{Name: fmt.Sprintf("Player %d", i+1), Rating: 2500 - 10*i})
+	}
+
+	return players
+}
+
+// FIDE's Berger table for six players, numbered from 1
+func (s *TournamentTestSuite) TestBergerTable() {
+	expected := [][][2]int{
+		{{1, 6}, {2, 5}, {3, 4}},
+		{{6, 4}, {5, 3}, {1, 2}},
+		{{2, 6}, {3, 1}, {4, 5}},
+		{{6, 5}, {1, 4}, {2, 3}},
+		{{3, 6}, {4, 2}, {5, 1}},
+	}
+
+	for round, boards := range expected {
+		pairings := bergerRound(6, round)
+		s.Require().Len(pairings, 3)
+
+		for board, players := range boards {
+			s.Equal(Pairing{White: players[0] - 1, Black: players[1] - 1}, pairings[board], "round %d board %d", round+1, board+1)
+		}
+	}
+}
+
+func (s *TournamentTestSuite) TestRoundRobin() {
+	tournament, err := NewTournament("Club", RoundRobin, s.players(5), 2)
+	s.Require().Nil(err)
+	s.Equal(10, tournament.Rounds, "five rounds a cycle with one player sitting out each")
+
+	games := map[[2]int]int{}
+	for round := 0; round < tournament.Rounds; round++ {
+		pairings, err := tournament.PairNextRound()
+		s.Require().Nil(err)
+		s.Len(pairings, 2)
+
+		for board, pairing := range pairings {
+			games[[2]int{pairing.White, pairing.Black}]++
+			s.Nil(tournament.RecordResult(round+1, board+1, Draw))
+		}
+	}
+
+	s.Len(games, 20, "everyone has White and Black against everyone else")
+	for _, count := range games {
+		s.Equal(1, count)
+	}
+
+	_, err = tournament.PairNextRound()
+	s.Equal(ErrorTournamentOver, err)
+
+	crosstable := tournament.Crosstable()
+	s.True(crosstable.RoundRobin)
+	s.Equal(4.0, crosstable.Players[0].Points)
+}
+
+func (s *TournamentTestSuite) TestNewTournament() {
+	_, err := NewTournament("Club", Swiss, s.players(1), 3)
+	s.True(errors.Is(err, ErrorInvalidTournament))
+
+	_, err = NewTournament("Club", Swiss, []TournamentPlayer{{Name: "A"}, {Name: "A"}}, 3)
+	s.True(errors.Is(err, ErrorInvalidTournament))
+
+	_, err = NewTournament("Club", "knockout", s.players(4), 3)
+	s.True(errors.Is(err, ErrorInvalidTournament))
+
+	tournament, err := NewTournament("Club", Swiss, []TournamentPlayer{{"Low", 1800}, {"High", 2200}}, 3)
+	s.Require().Nil(err)
+	s.Equal("High", tournament.Players[0].Name, "seeded by rating")
+}
+
+func (s *TournamentTestSuite) TestSwissFirstRound() {
+	tournament, _ := NewTournament("Open", Swiss, s.players(8), 5)
+
+	pairings, err := tournament.PairNextRound()
+	s.Require().Nil(err)
+
+	s.Equal([]Pairing{{White: 0, Black: 4}, {White: 5, Black: 1}, {White: 2, Black: 6}, {White: 7, Black: 3}}, pairings)
+
+	_, err = tournament.PairNextRound()
+	s.True(errors.Is(err, ErrorRoundUnfinished))
+}
+
+func (s *TournamentTestSuite) TestSwissScoreGroups() {
+	tournament, _ := NewTournament("Open", Swiss, s.players(8), 5)
+	tournament.PairNextRound()
+
+	// The higher seed wins each game
+	for board, result := range []Outcome{WhiteWin, BlackWin, WhiteWin, BlackWin} {
+		s.Nil(tournament.RecordResult(1, board+1, result))
+	}
+
+	// Winners pair 1v3 and 2v4, colors alternating from round 1; among the
+	// losers both of each pair want the same color and the higher ranked gets it
+	pairings, err := tournament.PairNextRound()
+	s.Require().Nil(err)
+	s.Equal([]Pairing{{White: 2, Black: 0}, {White: 1, Black: 3}, {White: 4, Black: 6}, {White: 7, Black: 5}}, pairings)
+}
+
+func (s *TournamentTestSuite) TestSwissRules() {
+	random := rand.New(rand.NewSource(1))
+	tournament, _ := NewTournament("Open", Swiss, s.players(7), 6)
+	results := []Outcome{WhiteWin, BlackWin, Draw}
+
+	for round := 1; round <= 6; round++ {
+		pairings, err := tournament.PairNextRound()
+		s.Require().Nil(err, "round %d", round)
+		s.Len(pairings, 4)
+
+		for board, pairing := range pairings {
+			if !pairing.IsBye() {
+				s.Nil(tournament.RecordResult(round, board+1, results[random.Intn(len(results))]))
+			}
+		}
+	}
+
+	met, byes := map[[2]int]bool{}, map[int]int{}
+	for _, player := range tournament.swissPlayers() {
+		balance := 0
+		for i, color := range player.colors {
+			if color == White {
+				balance++
+			} else {
+				balance--
+			}
+			s.True(balance >= -2 && balance <= 2)
+			s.False(i >= 2 && player.colors[i-1] == color && player.colors[i-2] == color, "a color three times running")
+		}
+	}
+
+	for _, pairings := range tournament.Played {
+		for _, pairing := range pairings {
+			if pairing.IsBye() {
+				byes[pairing.White]++
+				continue
+			}
+
+			pair := [2]int{pairing.White, pairing.Black}
+			if pair[0] > pair[1] {
+				pair[0], pair[1] = pair[1], pair[0]
+			}
+			s.False(met[pair], "a rematch")
+			met[pair] = true
+		}
+	}
+
+	s.Len(byes, 6)
+	for _, count := range byes {
+		s.Equal(1, count)
+	}
+}
+
+func (s *TournamentTestSuite) TestRecordGame() {
+	tournament, _ := NewTournament("Open", Swiss, s.players(3), 3)
+	tournament.PairNextRound()
+
+	game := ParsePGN("[Round \"1\"]\n[White \"Player 1\"]\n[Black \"Player 2\"]\n\n1. e4 e5 2. Qh5 Nc6 3. Bc4 Nf6 4. Qxf7# 1-0")
+	s.Nil(tournament.RecordGame(game))
+
+	game.Tags["White"] = "Player 3"
+	s.True(errors.Is(tournament.RecordGame(game), ErrorNoSuchPairing))
+
+	s.True(errors.Is(tournament.RecordResult(1, 2, WhiteWin), ErrorInvalidTournament), "a bye")
+	s.True(errors.Is(tournament.RecordResult(1, 1, Ongoing), ErrorInvalidTournament))
+	s.True(errors.Is(tournament.RecordResult(2, 1, Draw), ErrorNoSuchPairing))
+
+	pgns := tournament.PGNs()
+	s.Require().Len(pgns, 1)
+	s.Equal("1.1", pgns[0].Tags["Round"])
+	s.Equal("Open", pgns[0].Tags["Event"])
+	s.Equal("2500", pgns[0].Tags["WhiteElo"])
+	s.Equal(Outcome(WhiteWin), pgns[0].Outcome)
+	s.Equal("Qxf7#", string(pgns[0].Moves[3].WhiteMove))
+
+	crosstable := tournament.Crosstable()
+	s.Equal([]float64{1, 1, 0}, []float64{crosstable.Players[0].Points, crosstable.Players[1].Points, crosstable.Players[2].Points})
+}
+
+func (s *TournamentTestSuite) TestSaveAndLoad() {
+	tournament, _ := NewTournament("Open", Swiss, s.players(4), 3)
+	tournament.PairNextRound()
+	tournament.RecordResult(1, 1, Draw)
+
+	path := filepath.Join(s.T().TempDir(), "open.json")
+	s.Require().Nil(tournament.Save(path))
+
+	loaded, err := LoadTournament(path)
+	s.Require().Nil(err)
+	s.Equal(tournament, loaded)
+
+	_, err = LoadTournament(filepath.Join(s.T().TempDir(), "missing.json"))
+	s.NotNil(err)
+}
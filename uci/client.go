@@ -0,0 +1,517 @@
+package uci
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/marcel/pawn"
+)
+
+var (
+	ErrorTimeout      = errors.New("uci: engine didn't answer in time")
+	ErrorEngineExited = errors.New("uci: engine exited")
+)
+
+// How long new clients wait for an engine to answer
+var DefaultTimeout = 10 * time.Second
+
+// Drives an external engine. A client isn't safe for concurrent use, except
+// that a search may be stopped from another goroutine.
+type Client struct {
+	Name    string
+	Author  string
+	Options []string // The option lines the engine declared, less "option "
+
+	// How long to wait for the engine to answer commands other than go, and
+	// beyond the time a search was given for its move
+	Timeout time.Duration
+
+	in    io.Writer
+	lines chan string // Closed once the engine's output ends
+	close func() error
+
+	position *pawn.Board // The last position sent, to read moves against
+}
+
+// Launches an engine and shakes hands with it
+func Start(path string, args ...string) (*Client, error) {
+	command := exec.Command(path, args...)
+
+	in, err := command.StdinPipe()
+	if err != nil {
+		return nil, err
+	}
+
+	out, err := command.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := command.Start(); err != nil {
+		return nil, err
+	}
+
+	client := newClient(out, in)
+	client.close = func() error {
+		in.Close()
+
+		exited := make(chan error, 1)
+		go func() {
+			exited <- command.Wait()
+		}()
+
+		select {
+		case err := <-exited:
+			return err
+		case <-time.After(client.Timeout):
+			command.Process.Kill()
+			<-exited
+			return ErrorTimeout
+		}
+	}
+
+	if err := client.handshake(); err != nil {
+		command.Process.Kill()
+		command.Wait()
+		return nil, err
+	}
+
+	return client, nil
+}
+
+// Shakes hands with an engine already running, reading its output from out
+// and writing commands to in. Closing the client closes in if it's a Closer.
+func Connect(out io.Reader, in io.Writer) (*Client, error) {
+	client := newClient(out, in)
+	client.close = func() error {
+		if closer, ok := in.(io.Closer); ok {
+			return closer.Close()
+		}
+		return nil
+	}
+
+	if err := client.handshake(); err != nil {
+		return nil, err
+	}
+
+	return client, nil
+}
+
+func newClient(out io.Reader, in io.Writer) *Client {
+	client := &Client{Timeout: DefaultTimeout, in: in, lines: make(chan string, 64), position: pawn.NewBoard()}
+
+	go func() {
+		defer close(client.lines)
+
+		scanner := bufio.NewScanner(out)
+		for scanner.Scan() {
+			client.lines <- scanner.Text()
+		}
+	}()
+
+	return client
+}
+
+func (c *Client) send(format string, args ...interface{}) error {
+	_, err := fmt.Fprintf(c.in, format+"\n", args...)
+	return err
+}
+
+// Reads lines until one starts with prefix, returning those before it
+func (c *Client) await(prefix string, timeout time.Duration) ([]string, error) {
+	var deadline <-chan time.Time
+	if timeout > 0 {
+		timer := time.NewTimer(timeout)
+		defer timer.Stop()
+		deadline = timer.C
+	}
+
+	read := []string{}
+	for {
+		select {
+		case line, open := <-c.lines:
+			if !open {
+				return read, ErrorEngineExited
+			}
+
+			if strings.HasPrefix(line, prefix) {
+				return append(read, line), nil
+			}
+			read = append(read, line)
+		case <-deadline:
+			return read, fmt.Errorf("%w: waiting for %s", ErrorTimeout, strings.TrimSpace(prefix))
+		}
+	}
+}
+
+func (c *Client) handshake() error {
+	if err := c.send("uci"); err != nil {
+		return err
+	}
+
+	lines, err := c.await("uciok", c.Timeout)
+	if err != nil {
+		return err
+	}
+
+	for _, line := range lines {
+		switch {
+		case strings.HasPrefix(line, "id name "):
+			c.Name = strings.TrimPrefix(line, "id name ")
+		case strings.HasPrefix(line, "id author "):
+			c.Author = strings.TrimPrefix(line, "id author ")
+		case strings.HasPrefix(line, "option "):
+			c.Options = append(c.Options, strings.TrimPrefix(line, "option "))
+		}
+	}
+
+	return nil
+}
+
+// Waits until the engine has dealt with every command sent
+func (c *Client) IsReady() error {
+	if err := c.send("isready"); err != nil {
+		return err
+	}
+
+	_, err := c.await("readyok", c.Timeout)
+
+	return err
+}
+
+func (c *Client) SetOption(name, value string) error {
+	if err := c.send("setoption name %s value %s", name, value); err != nil {
+		return err
+	}
+
+	return c.IsReady()
+}
+
+// Tells the engine the next position is from another game
+func (c *Client) NewGame() error {
+	if err := c.send("ucinewgame"); err != nil {
+		return err
+	}
+
+	return c.IsReady()
+}
+
+// Sets the position to search to the board's
+func (c *Client) Position(board *pawn.Board) error {
+	position, err := pawn.ParseFEN(board.FEN())
+	if err != nil {
+		return err
+	}
+
+	c.position = position
+
+	return c.send("position fen %s", board.FEN())
+}
+
+// Sets the position to search to that after the game's moves from the
+// initial position, so the engine knows the moves that led to it
+func (c *Client) PositionGame(game pawn.Game) error {
+	board := pawn.NewBoard()
+	moves := []string{}
+
+	for _, move := range game.Moves {
+		moves = append(moves, board.EncodeMove(move).String())
+		board.MakeMove(move)
+	}
+
+	c.position = board
+
+	if len(moves) == 0 {
+		return c.send("position startpos")
+	}
+	return c.send("position startpos moves %s", strings.Join(moves, " "))
+}
+
+// What a search is given; zero values are left out of the go command
+type Limits struct {
+	Depth          int
+	Nodes          uint64
+	MoveTime       time.Duration
+	WhiteTime      time.Duration
+	BlackTime      time.Duration
+	WhiteIncrement time.Duration
+	BlackIncrement time.Duration
+	MovesToGo      int
+	Infinite       bool // Search until stopped
+}
+
+func (l Limits) command() string {
+	command := []string{"go"}
+
+	add := func(name string, value int64) {
+		if value > 0 {
+			command = append(command, name, strconv.FormatInt(value, 10))
+		}
+	}
+
+	add("depth", int64(l.Depth))
+	add("nodes", int64(l.Nodes))
+	add("movetime", l.MoveTime.Milliseconds())
+	add("wtime", l.WhiteTime.Milliseconds())
+	add("btime", l.BlackTime.Milliseconds())
+	add("winc", l.WhiteIncrement.Milliseconds())
+	add("binc", l.BlackIncrement.Milliseconds())
+	add("movestogo", int64(l.MovesToGo))
+
+	if l.Infinite {
+		command = append(command, "infinite")
+	}
+
+	return strings.Join(command, " ")
+}
+
+// The longest a search may take before its engine is overdue: its move time,
+// or all the side to move's time, or no limit for other searches
+func (l Limits) allowance(side pawn.Color) time.Duration {
+	switch {
+	case l.Infinite:
+		return 0
+	case l.MoveTime > 0:
+		return l.MoveTime
+	case side == pawn.White:
+		return l.WhiteTime
+	default:
+		return l.BlackTime
+	}
+}
+
+// A search report. Fields the engine left out are zero.
+type Info struct {
+	Depth    int
+	SelDepth int
+	MultiPV  int
+	Score    int // Centipawns to the side to move, unless Mate is set
+	Mate     int // Moves to mate, negative when the side to move is mated
+	Bound    string
+	Nodes    uint64
+	NPS      uint64
+	Time     time.Duration
+	HashFull int
+	PV       []pawn.Move
+	String   string // Free text from info string
+}
+
+type BestMove struct {
+	Move   pawn.Move // The zero Move if the position has none
+	Ponder pawn.Move // The reply expected, if the engine gave one
+}
+
+// A search running on the engine
+type Search struct {
+	// Reports as the engine sends them, closed once it has moved. They must
+	// be received for the search to finish.
+	Info <-chan Info
+
+	client *Client
+	done   chan struct{}
+	best   BestMove
+	err    error
+}
+
+// Starts a search of the last position sent
+func (c *Client) Go(limits Limits) (*Search, error) {
+	if err := c.send(limits.command()); err != nil {
+		return nil, err
+	}
+
+	info := make(chan Info)
+	search := &Search{Info: info, client: c, done: make(chan struct{})}
+
+	timeout := time.Duration(0)
+	if allowance := limits.allowance(c.position.SideToMove()); allowance > 0 {
+		timeout = allowance + c.Timeout
+	}
+
+	go func() {
+		defer close(search.done)
+		defer close(info)
+
+		var deadline <-chan time.Time
+		if timeout > 0 {
+			timer := time.NewTimer(timeout)
+			defer timer.Stop()
+			deadline = timer.C
+		}
+
+		for {
+			select {
+			case line, open := <-c.lines:
+				if !open {
+					search.err = ErrorEngineExited
+					return
+				}
+
+				fields := strings.Fields(line)
+				switch {
+				case len(fields) > 0 && fields[0] == "info":
+					info <- c.parseInfo(fields[1:])
+				case len(fields) > 0 && fields[0] == "bestmove":
+					search.best, search.err = c.parseBestMove(fields[1:])
+					return
+				}
+			case <-deadline:
+				search.err = fmt.Errorf("%w: waiting for bestmove", ErrorTimeout)
+				return
+			}
+		}
+	}()
+
+	return search, nil
+}
+
+// Tells the engine to move now
+func (s *Search) Stop() error {
+	return s.client.send("stop")
+}
+
+// Waits for the engine's move, discarding any reports not yet received
+func (s *Search) Wait() (BestMove, error) {
+	for range s.Info {
+	}
+	<-s.done
+
+	return s.best, s.err
+}
+
+// Searches the last position sent, returning the engine's move and its last
+// report with a principal variation
+func (c *Client) Analyze(limits Limits) (BestMove, Info, error) {
+	search, err := c.Go(limits)
+	if err != nil {
+		return BestMove{}, Info{}, err
+	}
+
+	var last Info
+	for info := range search.Info {
+		if len(info.PV) > 0 {
+			last = info
+		}
+	}
+
+	best, err := search.Wait()
+
+	return best, last, err
+}
+
+// Parses the fields of an info line after "info". Moves of a principal
+// variation that can't be read against the position stop it short.
+func (c *Client) parseInfo(fields []string) Info {
+	info := Info{}
+
+	for i := 0; i < len(fields); i++ {
+		name := fields[i]
+
+		switch name {
+		case "string":
+			info.String = strings.Join(fields[i+1:], " ")
+			return info
+		case "pv":
+			info.PV = c.parseMoves(fields[i+1:])
+			return info
+		case "lowerbound", "upperbound":
+			info.Bound = name
+			continue
+		case "score":
+			if i+2 < len(fields) {
+				value, _ := strconv.Atoi(fields[i+2])
+				if fields[i+1] == "mate" {
+					info.Mate = value
+				} else {
+					info.Score = value
+				}
+				i += 2
+			}
+			continue
+		}
+
+		if i+1 >= len(fields) {
+			break
+		}
+
+		value, err := strconv.ParseInt(fields[i+1], 10, 64)
+		if err != nil {
+			continue
+		}
+		i++
+
+		switch name {
+		case "depth":
+			info.Depth = int(value)
+		case "seldepth":
+			info.SelDepth = int(value)
+		case "multipv":
+			info.MultiPV = int(value)
+		case "nodes":
+			info.Nodes = uint64(value)
+		case "nps":
+			info.NPS = uint64(value)
+		case "time":
+			info.Time = time.Duration(value) * time.Millisecond
+		case "hashfull":
+			info.HashFull = int(value)
+		}
+	}
+
+	return info
+}
+
+func (c *Client) parseMoves(texts []string) []pawn.Move {
+	moves := []pawn.Move{}
+
+	for _, text := range texts {
+		move, err := c.position.ResolveLongAlgebraic(text)
+		if err != nil {
+			break
+		}
+
+		moves = append(moves, move)
+		c.position.MakeMove(move)
+	}
+
+	for range moves {
+		c.position.UnmakeMove()
+	}
+
+	return moves
+}
+
+// bestmove <move> [ponder <move>]
+func (c *Client) parseBestMove(fields []string) (BestMove, error) {
+	if len(fields) == 0 || fields[0] == "0000" || fields[0] == "(none)" {
+		return BestMove{}, nil
+	}
+
+	texts := []string{fields[0]}
+	if len(fields) >= 3 && fields[1] == "ponder" {
+		texts = append(texts, fields[2])
+	}
+
+	moves := c.parseMoves(texts)
+	if len(moves) == 0 {
+		return BestMove{}, fmt.Errorf("%w: engine played %s", pawn.ErrorIllegalMove, fields[0])
+	}
+
+	best := BestMove{Move: moves[0]}
+	if len(moves) > 1 {
+		best.Ponder = moves[1]
+	}
+
+	return best, nil
+}
+
+// Asks the engine to quit, killing it if it doesn't within the timeout
+func (c *Client) Close() error {
+	c.send("quit")
+	return c.close()
+}
@@ -0,0 +1,236 @@
+package uci
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/marcel/pawn"
+	"github.com/stretchr/testify/suite"
+)
+
+// Set to run the test binary as a fake engine instead: "scripted" plays out
+// a canned search, "silent" never answers and "stubborn" ignores quit
+const fakeEngineVariable = "PAWN_FAKE_ENGINE"
+
+func TestMain(m *testing.M) {
+	if mode := os.Getenv(fakeEngineVariable); mode != "" {
+		fakeEngine(mode)
+		os.Exit(0)
+	}
+
+	os.Exit(m.Run())
+}
+
+func fakeEngine(mode string) {
+	lines := bufio.NewScanner(os.Stdin)
+
+	for lines.Scan() {
+		if mode == "silent" {
+			continue
+		}
+
+		fields := strings.Fields(lines.Text())
+		if len(fields) == 0 {
+			continue
+		}
+
+		switch fields[0] {
+		case "uci":
+			fmt.Println("id name fake")
+			fmt.Println("id author pawn tests")
+			fmt.Println("option name Hash type spin default 16 min 1 max 64")
+			fmt.Println("uciok")
+		case "isready":
+			fmt.Println("readyok")
+		case "go":
+			fmt.Println("info string thinking")
+			fmt.Println("info depth 1 seldepth 2 score cp 25 nodes 120 nps 12000 time 10 pv e2e4")
+			fmt.Println("info depth 2 score cp -10 upperbound nodes 400 time 20 hashfull 3 pv e2e4 e7e5")
+
+			if fields[len(fields)-1] == "infinite" {
+				for lines.Scan() && lines.Text() != "stop" {
+				}
+			} else if fields[1] == "movetime" {
+				// Overdue
+				continue
+			}
+
+			fmt.Println("info depth 3 multipv 1 score mate 2 nodes 900 time 30 pv e2e4 e7e5 g1f3 zz")
+			fmt.Println("bestmove e2e4 ponder e7e5")
+		case "quit":
+			if mode != "stubborn" {
+				return
+			}
+		}
+	}
+
+	if mode == "stubborn" {
+		time.Sleep(time.Minute)
+	}
+}
+
+type ClientTestSuite struct {
+	suite.Suite
+}
+
+func TestClientTestSuite(t *testing.T) {
+	suite.Run(t, new(ClientTestSuite))
+}
+
+func (s *ClientTestSuite) start(mode string) (*Client, error) {
+	os.Setenv(fakeEngineVariable, mode)
+	defer os.Unsetenv(fakeEngineVariable)
+
+	// Don't let the race detector hold up the engine quitting
+	os.Setenv("GORACE", "atexit_sleep_ms=0")
+	defer os.Unsetenv("GORACE")
+
+	return Start(os.Args[0])
+}
+
+func (s *ClientTestSuite) TestHandshake() {
+	client, err := s.start("scripted")
+	s.Require().Nil(err)
+
+	s.Equal("fake", client.Name)
+	s.Equal("pawn tests", client.Author)
+	s.Equal([]string{"name Hash type spin default 16 min 1 max 64"}, client.Options)
+
+	s.Nil(client.SetOption("Hash", "32"))
+	s.Nil(client.NewGame())
+	s.Nil(client.Close())
+}
+
+func (s *ClientTestSuite) TestSearch() {
+	client, err := s.start("scripted")
+	s.Require().Nil(err)
+	defer client.Close()
+
+	s.Require().Nil(client.PositionGame(pawn.Game{}))
+
+	search, err := client.Go(Limits{Depth: 3})
+	s.Require().Nil(err)
+
+	infos := []Info{}
+	for info := range search.Info {
+		infos = append(infos, info)
+	}
+
+	best, err := search.Wait()
+	s.Require().Nil(err)
+
+	board := pawn.NewBoard()
+	e4, _ := board.ResolveLongAlgebraic("e2e4")
+	board.MakeMove(e4)
+	e5, _ := board.ResolveLongAlgebraic("e7e5")
+	board.MakeMove(e5)
+	nf3, _ := board.ResolveLongAlgebraic("g1f3")
+
+	s.Equal(BestMove{Move: e4, Ponder: e5}, best)
+
+	s.Require().Len(infos, 4)
+	s.Equal(Info{String: "thinking"}, infos[0])
+	s.Equal(Info{Depth: 1, SelDepth: 2, Score: 25, Nodes: 120, NPS: 12000, Time: 10 * time.Millisecond,
+		PV: []pawn.Move{e4}}, infos[1])
+	s.Equal(Info{Depth: 2, Score: -10, Bound: "upperbound", Nodes: 400, Time: 20 * time.Millisecond,
+		HashFull: 3, PV: []pawn.Move{e4, e5}}, infos[2])
+
+	// The move it can't read ends the variation
+	s.Equal(Info{Depth: 3, MultiPV: 1, Mate: 2, Nodes: 900, Time: 30 * time.Millisecond,
+		PV: []pawn.Move{e4, e5, nf3}}, infos[3])
+}
+
+func (s *ClientTestSuite) TestPosition() {
+	client, err := s.start("scripted")
+	s.Require().Nil(err)
+	defer client.Close()
+
+	// Black to move, so the engine's e2e4 isn't legal
+	board, err := pawn.ParseFEN("rnbqkbnr/pppppppp/8/8/4P3/8/PPPP1PPP/RNBQKBNR b KQkq e3 0 1")
+	s.Require().Nil(err)
+	s.Require().Nil(client.Position(board))
+
+	best, info, err := client.Analyze(Limits{Depth: 3})
+	s.ErrorIs(err, pawn.ErrorIllegalMove)
+	s.Equal(BestMove{}, best)
+	s.Equal(Info{}, info)
+}
+
+func (s *ClientTestSuite) TestInfinite() {
+	client, err := s.start("scripted")
+	s.Require().Nil(err)
+	defer client.Close()
+
+	s.Require().Nil(client.PositionGame(pawn.Game{}))
+
+	search, err := client.Go(Limits{Infinite: true})
+	s.Require().Nil(err)
+
+	for info := range search.Info {
+		if info.Depth == 2 {
+			s.Nil(search.Stop())
+		}
+	}
+
+	best, err := search.Wait()
+	s.Nil(err)
+	s.NotEqual(pawn.Move{}, best.Move)
+}
+
+func (s *ClientTestSuite) TestTimeouts() {
+	client, err := s.start("scripted")
+	s.Require().Nil(err)
+	client.Timeout = 100 * time.Millisecond
+
+	_, _, err = client.Analyze(Limits{MoveTime: 50 * time.Millisecond})
+	s.ErrorIs(err, ErrorTimeout)
+	s.Nil(client.Close())
+
+	defer func(timeout time.Duration) {
+		DefaultTimeout = timeout
+	}(DefaultTimeout)
+	DefaultTimeout = 100 * time.Millisecond
+
+	_, err = s.start("silent")
+	s.ErrorIs(err, ErrorTimeout)
+
+	client, err = s.start("stubborn")
+	s.Require().Nil(err)
+	client.Timeout = 100 * time.Millisecond
+	s.ErrorIs(client.Close(), ErrorTimeout)
+}
+
+// Against the package's own engine, as any other
+func (s *ClientTestSuite) TestServer() {
+	inReader, inWriter := io.Pipe()
+	outReader, outWriter := io.Pipe()
+
+	done := make(chan error, 1)
+	go func() {
+		err := NewServer(inReader, outWriter).Run()
+		outWriter.Close()
+		done <- err
+	}()
+
+	client, err := Connect(outReader, inWriter)
+	s.Require().Nil(err)
+	s.Equal("pawn", client.Name)
+
+	board, err := pawn.ParseFEN("6k1/5ppp/8/8/8/8/8/R5K1 w - - 0 1")
+	s.Require().Nil(err)
+	s.Require().Nil(client.Position(board))
+
+	best, info, err := client.Analyze(Limits{Depth: 2})
+	s.Require().Nil(err)
+	s.Equal("a1a8", board.EncodeMove(best.Move).String())
+	s.Equal(1, info.Mate)
+	s.Equal(best.Move, info.PV[0])
+
+	s.Nil(client.Close())
+	s.Nil(<-done)
+}
@@ -0,0 +1,386 @@
+// Package uci speaks the Universal Chess Interface, the text protocol chess
+// GUIs and match runners use to talk to engines. Server plays the engine's
+// side and Client the GUI's, driving an external engine.
+//
+// Spec: https://www.shredderchess.com/download/div/uci.zip
+package uci
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/marcel/pawn"
+	"github.com/marcel/pawn/engine"
+)
+
+const (
+	maxHashMegabytes = 4096
+	maxThreads       = 256
+
+	// No position has more legal moves than this
+	maxMultiPV = 218
+)
+
+// Plays the engine's side of the protocol, reading commands from a GUI and
+// writing replies
+type Server struct {
+	in  io.Reader
+	out io.Writer
+
+	outMutex sync.Mutex
+
+	engine  *engine.Engine
+	board   *pawn.Board
+	multiPV int
+
+	searching chan struct{} // Closed once the search in progress reports its move
+	stopping  chan struct{} // Closed to stop it
+	ponderHit chan struct{} // Closed when the move pondered on is played
+}
+
+func NewServer(in io.Reader, out io.Writer) *Server {
+	return &Server{in: in, out: out, engine: engine.New(), board: pawn.NewBoard(), multiPV: 1}
+}
+
+// Serves commands until quit or the end of input, stopping any search still
+// running
+func (s *Server) Run() error {
+	lines := bufio.NewScanner(s.in)
+
+	for lines.Scan() {
+		fields := strings.Fields(lines.Text())
+		if len(fields) == 0 {
+			continue
+		}
+
+		command, args := fields[0], fields[1:]
+		switch command {
+		case "uci":
+			s.identify()
+		case "isready":
+			s.send("readyok")
+		case "ucinewgame":
+			s.stop()
+			s.engine.Table.Clear()
+			s.board = pawn.NewBoard()
+		case "position":
+			s.stop()
+			s.position(args)
+		case "go":
+			s.stop()
+			s.goSearch(args)
+		case "stop":
+			s.stop()
+		case "ponderhit":
+			s.ponderhit()
+		case "setoption":
+			s.stop()
+			s.setOption(args)
+		case "quit":
+			s.stop()
+			return nil
+		}
+	}
+
+	s.stop()
+
+	return lines.Err()
+}
+
+func (s *Server) send(format string, args ...interface{}) {
+	s.outMutex.Lock()
+	defer s.outMutex.Unlock()
+
+	fmt.Fprintf(s.out, format+"\n", args...)
+}
+
+func (s *Server) identify() {
+	s.send("id name pawn")
+	s.send("id author Marcel Molina")
+	s.send("option name Hash type spin default %d min 1 max %d", engine.DefaultHashMegabytes, maxHashMegabytes)
+	s.send("option name Ponder type check default false")
+	s.send("option name Threads type spin default 1 min 1 max %d", maxThreads)
+	for _, option := range engine.OptionNames {
+		s.send("option name %s type check default %t", option, engine.DefaultOptions.Enabled(option))
+	}
+	s.send("option name MultiPV type spin default 1 min 1 max %d", maxMultiPV)
+	s.send("uciok")
+}
+
+// setoption name <id> [value <x>], where the name may have spaces
+func (s *Server) setOption(args []string) {
+	name, value := []string{}, []string{}
+	target := &name
+
+	for _, arg := range args {
+		switch arg {
+		case "name":
+			target = &name
+		case "value":
+			target = &value
+		default:
+			*target = append(*target, arg)
+		}
+	}
+
+	// GUIs only say whether they'll ask for pondering, which needs nothing
+	if strings.EqualFold(strings.Join(name, " "), "ponder") {
+		return
+	}
+
+	// The search's heuristics are turned on and off to measure them
+	for _, option := range engine.OptionNames {
+		if strings.EqualFold(strings.Join(name, " "), option) {
+			s.engine.Options.Set(option, strings.EqualFold(strings.Join(value, " "), "true"))
+			return
+		}
+	}
+
+	number, err := strconv.Atoi(strings.Join(value, " "))
+	if err != nil {
+		s.send("info string %s needs a number", strings.Join(name, " "))
+		return
+	}
+
+	switch strings.ToLower(strings.Join(name, " ")) {
+	case "hash":
+		s.engine.Table = engine.NewTranspositionTable(clamp(number, 1, maxHashMegabytes))
+	case "threads":
+		s.engine.Threads = clamp(number, 1, maxThreads)
+	case "multipv":
+		s.multiPV = clamp(number, 1, maxMultiPV)
+	default:
+		s.send("info string no option %s", strings.Join(name, " "))
+	}
+}
+
+func clamp(number, min, max int) int {
+	switch {
+	case number < min:
+		return min
+	case number > max:
+		return max
+	}
+
+	return number
+}
+
+// position [startpos | fen <fen>] [moves <move> ...]
+func (s *Server) position(args []string) {
+	if len(args) == 0 {
+		return
+	}
+
+	moves := len(args)
+	for index, arg := range args {
+		if arg == "moves" {
+			moves = index
+			break
+		}
+	}
+
+	var board *pawn.Board
+	switch args[0] {
+	case "startpos":
+		board = pawn.NewBoard()
+	case "fen":
+		parsed, err := pawn.ParseFEN(strings.Join(args[1:moves], " "))
+		if err != nil {
+			s.send("info string %s", err)
+			return
+		}
+		board = parsed
+	default:
+		return
+	}
+
+	if moves < len(args) {
+		for _, text := range args[moves+1:] {
+			move, err := board.ResolveLongAlgebraic(text)
+			if err != nil {
+				s.send("info string %s", err)
+				return
+			}
+			board.MakeMove(move)
+		}
+	}
+
+	s.board = board
+}
+
+// go [depth <plies>] [nodes <n>] [movetime <ms>] [wtime <ms>] [btime <ms>]
+// [winc <ms>] [binc <ms>] [movestogo <n>] [infinite] [ponder]
+func (s *Server) goSearch(args []string) {
+	var limits engine.Limits
+	var times, increments [2]time.Duration
+	infinite, ponder := false, false
+
+	for i := 0; i < len(args); i++ {
+		switch args[i] {
+		case "infinite":
+			infinite = true
+			continue
+		case "ponder":
+			ponder = true
+			continue
+		}
+
+		if i+1 >= len(args) {
+			break
+		}
+
+		number, err := strconv.ParseInt(args[i+1], 10, 64)
+		if err != nil {
+			continue
+		}
+		milliseconds := time.Duration(number) * time.Millisecond
+
+		switch args[i] {
+		case "depth":
+			limits.Depth = int(number)
+		case "nodes":
+			limits.Nodes = uint64(number)
+		case "movetime":
+			limits.MoveTime = milliseconds
+		case "wtime":
+			times[0] = milliseconds
+		case "btime":
+			times[1] = milliseconds
+		case "winc":
+			increments[0] = milliseconds
+		case "binc":
+			increments[1] = milliseconds
+		case "movestogo":
+			limits.MovesToGo = int(number)
+		default:
+			continue
+		}
+		i++
+	}
+
+	side := 0
+	if s.board.SideToMove() == pawn.Black {
+		side = 1
+	}
+
+	if !infinite {
+		limits.Time, limits.Increment = times[side], increments[side]
+	}
+
+	limits.MultiPV = s.multiPV
+
+	board := s.board
+	s.searching, s.stopping = make(chan struct{}), make(chan struct{})
+	searching, stopping := s.searching, s.stopping
+	limits.Stop = stopping
+
+	if ponder {
+		s.ponderHit = make(chan struct{})
+		limits.PonderHit = s.ponderHit
+	}
+	ponderHit := limits.PonderHit
+
+	s.engine.Progress = func(result engine.Result) {
+		s.info(board, result)
+	}
+
+	go func() {
+		defer close(searching)
+
+		result := s.engine.Search(board, limits)
+
+		// An infinite search waits to be stopped before it reports its move,
+		// and a ponder search for the ponder hit too
+		if infinite {
+			<-stopping
+		} else if ponderHit != nil {
+			select {
+			case <-stopping:
+			case <-ponderHit:
+			}
+		}
+
+		s.bestMove(board, result)
+	}()
+}
+
+// Switches the search pondering on the move played to a search on the clock
+func (s *Server) ponderhit() {
+	if s.ponderHit != nil {
+		close(s.ponderHit)
+		s.ponderHit = nil
+	}
+}
+
+// Stops the search in progress, if any, and waits for it to report its move
+func (s *Server) stop() {
+	if s.searching == nil {
+		return
+	}
+
+	close(s.stopping)
+	<-s.searching
+
+	s.searching, s.stopping, s.ponderHit = nil, nil, nil
+}
+
+// An info line for each line the search found, ranked by multipv
+func (s *Server) info(board *pawn.Board, result engine.Result) {
+	nps := uint64(0)
+	if result.Time > 0 {
+		nps = uint64(float64(result.Nodes) / result.Time.Seconds())
+	}
+
+	hashfull := s.engine.Table.Hashfull()
+	for index, line := range result.Lines {
+		s.send("info depth %d multipv %d score %s nodes %d nps %d time %d hashfull %d pv %s",
+			result.Depth, index+1, Score(line.Score), result.Nodes, nps, result.Time.Milliseconds(),
+			hashfull, moveList(board, line.PV))
+	}
+}
+
+func (s *Server) bestMove(board *pawn.Board, result engine.Result) {
+	if result.Move == (pawn.Move{}) {
+		s.send("bestmove 0000")
+		return
+	}
+
+	if len(result.PV) > 1 {
+		moves := strings.Fields(moveList(board, result.PV[:2]))
+		s.send("bestmove %s ponder %s", moves[0], moves[1])
+		return
+	}
+
+	s.send("bestmove %s", moveList(board, result.PV[:1]))
+}
+
+// A score as UCI writes it, cp <centipawns> or mate <moves>
+func Score(score int) string {
+	if engine.IsMateScore(score) {
+		return "mate " + strconv.Itoa(engine.MateIn(score))
+	}
+
+	return "cp " + strconv.Itoa(score)
+}
+
+// Moves played in turn from board in long algebraic notation, separated by
+// spaces
+func moveList(board *pawn.Board, moves []pawn.Move) string {
+	texts := make([]string, len(moves))
+
+	for index, move := range moves {
+		texts[index] = board.EncodeMove(move).String()
+		board.MakeMove(move)
+	}
+
+	for range moves {
+		board.UnmakeMove()
+	}
+
+	return strings.Join(texts, " ")
+}
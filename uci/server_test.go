@@ -0,0 +1,226 @@
+package uci
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type ServerTestSuite struct {
+	suite.Suite
+	server *Server
+	in     *io.PipeWriter
+	out    *bufio.Scanner
+	done   chan error
+}
+
+func TestServerTestSuite(t *testing.T) {
+	suite.Run(t, new(ServerTestSuite))
+}
+
+func (s *ServerTestSuite) SetupTest() {
+	inReader, inWriter := io.Pipe()
+	outReader, outWriter := io.Pipe()
+
+	s.server = NewServer(inReader, outWriter)
+	s.in, s.out = inWriter, bufio.NewScanner(outReader)
+	s.done = make(chan error, 1)
+
+	go func() {
+		err := s.server.Run()
+		outWriter.Close()
+		s.done <- err
+	}()
+}
+
+func (s *ServerTestSuite) TearDownTest() {
+	s.in.Close()
+
+	// Drain anything left so the server isn't blocked writing
+	out := s.out
+	go func() {
+		for out.Scan() {
+		}
+	}()
+
+	select {
+	case err := <-s.done:
+		s.Nil(err)
+	case <-time.After(10 * time.Second):
+		s.Fail("the server didn't finish")
+	}
+}
+
+func (s *ServerTestSuite) send(format string, args ...interface{}) {
+	_, err := fmt.Fprintf(s.in, format+"\n", args...)
+	s.Require().Nil(err)
+}
+
+// Reads lines up to and including the first starting with prefix
+func (s *ServerTestSuite) expect(prefix string) []string {
+	lines := make(chan []string, 1)
+
+	go func() {
+		read := []string{}
+		for s.out.Scan() {
+			read = append(read, s.out.Text())
+			if strings.HasPrefix(s.out.Text(), prefix) {
+				break
+			}
+		}
+		lines <- read
+	}()
+
+	select {
+	case read := <-lines:
+		s.Require().True(len(read) > 0 && strings.HasPrefix(read[len(read)-1], prefix), "no %q in %q", prefix, read)
+		return read
+	case <-time.After(10 * time.Second):
+		s.FailNow("timed out waiting for " + prefix)
+		return nil
+	}
+}
+
+func (s *ServerTestSuite) TestHandshake() {
+	s.send("uci")
+	lines := s.expect("uciok")
+
+	s.Equal("id name pawn", lines[0])
+	s.Contains(lines, "option name Hash type spin default 16 min 1 max 4096")
+	s.Contains(lines, "option name NullMove type check default true")
+
+	s.send("isready")
+	s.expect("readyok")
+}
+
+func (s *ServerTestSuite) TestSearch() {
+	s.send("position startpos moves e2e4 e7e5 g1f3")
+	s.send("go depth 3")
+	lines := s.expect("bestmove")
+
+	s.True(strings.HasPrefix(lines[0], "info depth 1 multipv 1 score cp "), lines[0])
+	s.Contains(lines[2], " pv ")
+
+	fields := strings.Fields(lines[len(lines)-1])
+	s.Len(fields, 4)
+	s.Equal("ponder", fields[2])
+	s.Contains(lines[len(lines)-2], "pv "+fields[1]+" "+fields[3])
+}
+
+func (s *ServerTestSuite) TestMultiPV() {
+	s.send("setoption name MultiPV value 3")
+	s.send("position fen 6k1/5ppp/8/8/8/8/5PPP/3R2K1 w - - 0 1")
+	s.send("go depth 2")
+	lines := s.expect("bestmove")
+
+	s.Len(lines, 4)
+	s.True(strings.HasPrefix(lines[0], "info depth 1 multipv 1 score mate 1 "), lines[0])
+	s.True(strings.HasSuffix(lines[0], " pv d1d8"), lines[0])
+	s.True(strings.HasPrefix(lines[1], "info depth 1 multipv 2 score cp "), lines[1])
+	s.True(strings.HasPrefix(lines[2], "info depth 1 multipv 3 score cp "), lines[2])
+	s.Equal("bestmove d1d8", lines[3])
+
+	s.send("setoption name MultiPV value 1000")
+	s.send("isready")
+	s.expect("readyok")
+	s.Equal(maxMultiPV, s.server.multiPV)
+}
+
+func (s *ServerTestSuite) TestMate() {
+	s.send("position fen 6k1/5ppp/8/8/8/8/5PPP/3R2K1 w - - 0 1")
+	s.send("go wtime 60000 btime 60000 winc 1000 binc 1000")
+	lines := s.expect("bestmove")
+
+	s.Contains(lines[0], "score mate 1")
+	s.Equal("bestmove d1d8", lines[len(lines)-1])
+
+	s.send("position fen 1R5k/R7/8/8/8/8/8/7K b - - 0 1")
+	s.send("go depth 2")
+	s.Equal([]string{"bestmove 0000"}, s.expect("bestmove"))
+}
+
+func (s *ServerTestSuite) TestInfinite() {
+	s.send("position fen 6k1/5ppp/8/8/8/8/5PPP/3R2K1 w - - 0 1")
+	s.send("go infinite")
+	s.expect("info depth 1")
+
+	// The mate is found at once but the move waits for stop
+	s.send("isready")
+	s.expect("readyok")
+
+	s.send("stop")
+	s.expect("bestmove d1d8")
+}
+
+func (s *ServerTestSuite) TestStop() {
+	s.send("go movetime 60000")
+	s.expect("info depth 1")
+
+	s.send("stop")
+	s.expect("bestmove")
+
+	// A stop once nothing is searching doesn't affect the next search
+	s.send("stop")
+	s.send("go depth 2")
+	lines := s.expect("bestmove")
+	s.True(strings.HasPrefix(lines[len(lines)-2], "info depth 2"))
+}
+
+func (s *ServerTestSuite) TestOptionsAndErrors() {
+	s.send("setoption name Hash value 1")
+	s.send("setoption name Threads value 8")
+	s.send("isready")
+	s.expect("readyok")
+	s.Equal(8, s.server.engine.Threads)
+	s.send("setoption name Threads value 1000")
+	s.send("isready")
+	s.expect("readyok")
+	s.Equal(maxThreads, s.server.engine.Threads)
+	s.send("setoption name NullMove value false")
+	s.send("setoption name mvvlva value false")
+	s.send("setoption name Futility value true")
+	s.send("isready")
+	s.expect("readyok")
+	s.False(s.server.engine.Options.NullMove)
+	s.False(s.server.engine.Options.MVVLVA)
+	s.True(s.server.engine.Options.Futility)
+
+	s.send("setoption name Nonsense value 1")
+	s.expect("info string no option Nonsense")
+
+	s.send("position startpos moves e2e5")
+	s.expect("info string pawn: illegal move: e2e5")
+
+	s.send("ucinewgame")
+	s.send("quit")
+}
+
+func (s *ServerTestSuite) TestPonder() {
+	s.send("setoption name Ponder value true")
+	s.send("position startpos moves e2e4")
+	s.send("go ponder depth 2")
+	s.expect("info depth 2")
+
+	// The search is done, but the move waits for the ponder hit
+	s.send("isready")
+	s.expect("readyok")
+
+	s.send("ponderhit")
+	s.expect("bestmove")
+
+	s.send("go ponder wtime 60000 btime 60000")
+	s.expect("info depth 1")
+	s.send("ponderhit")
+	s.expect("bestmove")
+}
+
+func (s *ServerTestSuite) TestScore() {
+	s.Equal("cp -35", Score(-35))
+	s.Equal("mate 2", Score(31997))
+	s.Equal("mate -1", Score(-31998))
+}
@@ -0,0 +1,123 @@
+package pawn
+
+import (
+	"errors"
+	"fmt"
+)
+
+type IssueKind string
+
+const (
+	IllegalMove    IssueKind = "illegal-move"
+	AmbiguousMove            = "ambiguous-move"
+	CheckMismatch            = "check-mismatch"
+	ResultMismatch           = "result-mismatch"
+)
+
+// A problem found while replaying a game. Ply is counted from 1 for White's
+// first move and is zero for issues that don't belong to a particular move.
+type Issue struct {
+	Kind    IssueKind         `json:"kind"`
+	Ply     int               `json:"ply,omitempty"`
+	SAN     AlgebraicNotation `json:"san,omitempty"`
+	Message string            `json:"message"`
+}
+
+func (i Issue) String() string {
+	if i.Ply == 0 {
+		return fmt.Sprintf("%s: %s", i.Kind, i.Message)
+	}
+
+	return fmt.Sprintf("%s at %s: %s", i.Kind, moveLabel(i.Ply, i.SAN), i.Message)
+}
+
+// E.g. "12.Nf3" for White's move and "12...Nc6" for Black's
+func moveLabel(ply int, an AlgebraicNotation) string {
+	number := (ply + 1) / 2
+	if ply%2 == 1 {
+		return fmt.Sprintf("%d.%s", number, an)
+	}
+
+	return fmt.Sprintf("%d...%s", number, an)
+}
+
+// Replays the game on a Board reporting the first illegal or ambiguous move,
+// check and checkmate suffixes that don't agree with the position they lead
+// to and a result that doesn't agree with the Result tag or the final
+// position. Returns no issues for a valid game.
+func Validate(pgn PGN) []Issue {
+	issues := []Issue{}
+	board := NewBoard()
+	ply := 0
+
+	for _, an := range pgn.Turns() {
+		if an == "" {
+			continue
+		}
+		ply++
+
+		move, err := board.ResolveAlgebraic(an)
+		if err != nil {
+			kind := IssueKind(IllegalMove)
+			if errors.Is(err, ErrorAmbiguousMove) {
+				kind = AmbiguousMove
+			}
+
+			return append(issues, Issue{kind, ply, an, fmt.Sprintf("%s for %s", err, board.SideToMove())})
+		}
+
+		board.MakeMove(move)
+
+		if message := checkSuffixMismatch(board, an); message != "" {
+			issues = append(issues, Issue{CheckMismatch, ply, an, message})
+		}
+	}
+
+	if err := pgn.VerifyResult(); err != nil {
+		issues = append(issues, Issue{Kind: ResultMismatch, Message: err.Error()})
+	}
+
+	if expected, decided := finalOutcome(board); decided && pgn.Outcome != expected {
+		issues = append(issues, Issue{
+			Kind:    ResultMismatch,
+			Message: fmt.Sprintf("final position is decided as \"%s\" but the game ends with \"%s\"", expected, pgn.Outcome),
+		})
+	}
+
+	return issues
+}
+
+func checkSuffixMismatch(board *Board, an AlgebraicNotation) string {
+	inCheck := board.InCheck()
+	checkmate := inCheck && len(board.LegalMoves()) == 0
+
+	switch {
+	case checkmate && !an.IsCheckMate():
+		return "move delivers checkmate but is not marked with #"
+	case !checkmate && an.IsCheckMate():
+		return "move is marked with # but does not deliver checkmate"
+	case inCheck && !checkmate && !an.IsCheck():
+		return "move gives check but is not marked with +"
+	case !inCheck && an.IsCheck():
+		return "move is marked with + but does not give check"
+	}
+
+	return ""
+}
+
+// The outcome forced by the rules in the board's position, if any
+func finalOutcome(board *Board) (Outcome, bool) {
+	if len(board.LegalMoves()) > 0 {
+		return "", false
+	}
+
+	if !board.InCheck() {
+		return Draw, true
+	}
+
+	if board.SideToMove() == White {
+		return BlackWin, true
+	}
+
+	return WhiteWin, true
+}
@@ -0,0 +1,68 @@
+package pawn
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidate(t *testing.T) {
+	for _, pgnString := range []string{win, draw, finalMoveByWhite, embeddedComments, checkMate, ongoing} {
+		pgn := ParsePGN(pgnString)
+		assert.Empty(t, Validate(pgn), pgn.MatchUp())
+	}
+}
+
+func TestValidateIllegalMove(t *testing.T) {
+	pgn := ParsePGN(strings.Replace(win, "14.Ng3", "14.Nh3", 1))
+
+	assert.Equal(t,
+		[]Issue{Issue{IllegalMove, 27, "Nh3", "pawn: illegal move for White"}},
+		Validate(pgn),
+	)
+	assert.Equal(t, "illegal-move at 14.Nh3: pawn: illegal move for White", Validate(pgn)[0].String())
+
+	// 1.e4 b5 leaves the f8 bishop blocked in
+	assert.Equal(t,
+		[]Issue{Issue{IllegalMove, 6, "Bc5", "pawn: illegal move for Black"}},
+		Validate(ParsePGN(movetextWithRAV)),
+	)
+}
+
+func TestValidateAmbiguousMove(t *testing.T) {
+	pgn := ParsePGN(strings.Replace(draw, "17.Bd4 Rfd8", "17.Bd4 Rd8", 1))
+
+	issues := Validate(pgn)
+	assert.Equal(t, 1, len(issues))
+	assert.Equal(t, IssueKind(AmbiguousMove), issues[0].Kind)
+	assert.Equal(t, 34, issues[0].Ply)
+	assert.Equal(t, "17...Rd8", moveLabel(issues[0].Ply, issues[0].SAN))
+}
+
+func TestValidateCheckSuffixes(t *testing.T) {
+	unmarkedMate := ParsePGN(strings.Replace(checkMate, "c6#", "c6+", 1))
+	assert.Equal(t,
+		[]Issue{Issue{CheckMismatch, 20, "c6+", "move delivers checkmate but is not marked with #"}},
+		Validate(unmarkedMate),
+	)
+
+	unmarkedCheck := ParsePGN(strings.Replace(checkMate, "Qe7+", "Qe7", 1))
+	assert.Equal(t, IssueKind(CheckMismatch), Validate(unmarkedCheck)[0].Kind)
+	assert.Equal(t, 10, Validate(unmarkedCheck)[0].Ply)
+
+	falseCheck := ParsePGN(strings.Replace(checkMate, "g6", "g6+", 1))
+	assert.Equal(t, "move is marked with + but does not give check", Validate(falseCheck)[0].Message)
+}
+
+func TestValidateResult(t *testing.T) {
+	wrongTag := ParsePGN(strings.Replace(checkMate, `[Result "0-1"]`, `[Result "1/2-1/2"]`, 1))
+	issues := Validate(wrongTag)
+	assert.Equal(t, 1, len(issues))
+	assert.Equal(t, IssueKind(ResultMismatch), issues[0].Kind)
+
+	wrongOutcome := ParsePGN(strings.Replace(strings.Replace(checkMate, `[Result "0-1"]`, `[Result "1-0"]`, 1), "0-1", "1-0", 1))
+	issues = Validate(wrongOutcome)
+	assert.Equal(t, 1, len(issues))
+	assert.Equal(t, `final position is decided as "0-1" but the game ends with "1-0"`, issues[0].Message)
+}
@@ -0,0 +1,441 @@
+// Package xboard speaks the Chess Engine Communication Protocol, version 2,
+// that XBoard, WinBoard and older GUIs use to talk to engines.
+//
+// Spec: https://www.gnu.org/software/xboard/engine-intf.html
+package xboard
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/marcel/pawn"
+	"github.com/marcel/pawn/engine"
+)
+
+const (
+	// What a move gets before the GUI has said anything about time
+	defaultMoveTime = 5 * time.Second
+
+	// Mate scores as XBoard shows them, plus or minus the moves to mate
+	mateScore = 100000
+)
+
+// Plays the engine's side of the protocol, reading commands from a GUI and
+// writing replies
+type Server struct {
+	in  io.Reader
+	out io.Writer
+
+	outMutex sync.Mutex
+
+	engine *engine.Engine
+	board  *pawn.Board
+	hashes []uint64 // Every position of the game so far, for repetitions
+
+	force bool       // Whether the engine only records moves, playing neither side
+	color pawn.Color // The side the engine plays when it isn't forced
+	post  bool       // Whether to show its thinking
+
+	// Time controls: moves per control, or 0 for all the game, the time for
+	// each control and the increment per move, or a fixed time per move
+	movesPerControl int
+	base, increment time.Duration
+	moveTime        time.Duration
+	depth           int
+
+	clock, opponentClock time.Duration
+
+	// Set by memory and cores, and applied when the engine next thinks so a
+	// search in progress keeps its table and threads. Zero when unchanged.
+	hashMegabytes, threads int
+
+	search *search // The one in progress, if any
+}
+
+type search struct {
+	done     chan struct{} // Closed once the search has finished
+	stopping chan struct{} // Closed to stop it
+	aborted  int32         // Set to stop it without moving
+}
+
+func NewServer(in io.Reader, out io.Writer) *Server {
+	s := &Server{in: in, out: out, engine: engine.New(), color: pawn.Black}
+	s.newGame(pawn.NewBoard())
+
+	return s
+}
+
+// Serves commands until quit or the end of input, abandoning any search still
+// running
+func (s *Server) Run() error {
+	lines := bufio.NewScanner(s.in)
+
+	for lines.Scan() {
+		fields := strings.Fields(lines.Text())
+		if len(fields) == 0 {
+			continue
+		}
+
+		command, args := fields[0], fields[1:]
+		switch command {
+		case "protover":
+			s.send(`feature myname="pawn" setboard=1 usermove=1 ping=1 playother=1 colors=0 analyze=0 ` +
+				`sigint=0 sigterm=0 reuse=1 memory=1 smp=1 done=1`)
+		case "new":
+			s.abort()
+			s.engine.Table.Clear()
+			s.newGame(pawn.NewBoard())
+			s.force, s.color = false, pawn.Black
+			s.depth = 0
+		case "force":
+			s.abort()
+			s.force = true
+		case "go":
+			s.abort()
+			s.force, s.color = false, s.board.SideToMove()
+			s.think()
+		case "playother":
+			s.abort()
+			s.force, s.color = false, s.board.SideToMove().Opponent()
+		case "usermove":
+			s.abort()
+			s.userMove(args)
+		case "?":
+			s.moveNow()
+		case "level":
+			s.level(args)
+		case "st":
+			if seconds, err := strconv.Atoi(arg(args)); err == nil {
+				s.moveTime = time.Duration(seconds) * time.Second
+			}
+		case "sd":
+			if depth, err := strconv.Atoi(arg(args)); err == nil {
+				s.depth = depth
+			}
+		case "time", "otim":
+			centiseconds, err := strconv.Atoi(arg(args))
+			if err != nil {
+				break
+			}
+
+			if command == "time" {
+				s.clock = time.Duration(centiseconds) * 10 * time.Millisecond
+			} else {
+				s.opponentClock = time.Duration(centiseconds) * 10 * time.Millisecond
+			}
+		case "undo":
+			s.abort()
+			s.takeBack(1)
+		case "remove":
+			s.abort()
+			s.takeBack(2)
+		case "setboard":
+			s.abort()
+			board, err := pawn.ParseFEN(strings.Join(args, " "))
+			if err != nil {
+				s.send("tellusererror Illegal position: %s", err)
+				break
+			}
+			s.newGame(board)
+		case "result":
+			s.abort()
+			s.force = true
+		case "ping":
+			s.wait()
+			s.send("pong %s", arg(args))
+		case "post":
+			s.post = true
+		case "nopost":
+			s.post = false
+		case "memory":
+			if megabytes, err := strconv.Atoi(arg(args)); err == nil && megabytes > 0 {
+				s.hashMegabytes = megabytes
+			}
+		case "cores":
+			if cores, err := strconv.Atoi(arg(args)); err == nil && cores > 0 {
+				s.threads = cores
+			}
+		case "quit":
+			s.abort()
+			return nil
+		case "xboard", "accepted", "rejected", "random", "hard", "easy", "computer", "name", "rating",
+			"white", "black", "draw", "hint", "bk", ".":
+		default:
+			s.send("Error (unknown command): %s", command)
+		}
+	}
+
+	s.abort()
+
+	return lines.Err()
+}
+
+func (s *Server) send(format string, args ...interface{}) {
+	s.outMutex.Lock()
+	defer s.outMutex.Unlock()
+
+	fmt.Fprintf(s.out, format+"\n", args...)
+}
+
+func arg(args []string) string {
+	if len(args) == 0 {
+		return ""
+	}
+
+	return args[0]
+}
+
+// Starts a game from board with the clocks reset. Only new leaves force
+// mode, so the sides the engine plays are left to the caller.
+func (s *Server) newGame(board *pawn.Board) {
+	s.board = board
+	s.hashes = []uint64{board.Hash()}
+	s.clock, s.opponentClock = s.base, s.base
+}
+
+// usermove <move> in long algebraic notation, which the engine answers if
+// it's its turn
+func (s *Server) userMove(args []string) {
+	move, err := s.board.ResolveLongAlgebraic(arg(args))
+	if err != nil {
+		s.send("Illegal move: %s", arg(args))
+		return
+	}
+
+	s.play(move)
+
+	if !s.force && s.board.SideToMove() == s.color {
+		s.think()
+	}
+}
+
+// Makes a move, reporting the result if it ends the game. The engine stops
+// playing once the game is over.
+func (s *Server) play(move pawn.Move) {
+	s.board.MakeMove(move)
+	s.hashes = append(s.hashes, s.board.Hash())
+
+	if result := s.result(); result != "" {
+		s.send("%s", result)
+		s.force = true
+	}
+}
+
+// The result and its reason as XBoard wants them, or "" while the game goes
+// on
+func (s *Server) result() string {
+	if len(s.board.LegalMoves()) == 0 {
+		switch {
+		case !s.board.InCheck():
+			return "1/2-1/2 {Stalemate}"
+		case s.board.SideToMove() == pawn.White:
+			return "0-1 {Black mates}"
+		default:
+			return "1-0 {White mates}"
+		}
+	}
+
+	if engine.InsufficientMaterial(s.board) {
+		return "1/2-1/2 {Insufficient material}"
+	}
+
+	if s.board.HalfmoveClock() >= 100 {
+		return "1/2-1/2 {Fifty move rule}"
+	}
+
+	occurrences := 0
+	for _, hash := range s.hashes {
+		if hash == s.board.Hash() {
+			occurrences++
+		}
+	}
+	if occurrences >= 3 {
+		return "1/2-1/2 {Threefold repetition}"
+	}
+
+	return ""
+}
+
+// Takes back moves, stopping at the start of the game
+func (s *Server) takeBack(moves int) {
+	for ; moves > 0 && len(s.hashes) > 1; moves-- {
+		s.board.UnmakeMove()
+		s.hashes = s.hashes[:len(s.hashes)-1]
+	}
+}
+
+// level <moves per control> <minutes[:seconds]> <increment seconds>
+func (s *Server) level(args []string) {
+	if len(args) != 3 {
+		s.send("Error (wrong number of arguments): level")
+		return
+	}
+
+	moves, err := strconv.Atoi(args[0])
+	if err != nil {
+		s.send("Error (bad moves per control): %s", args[0])
+		return
+	}
+
+	minutes, seconds := args[1], "0"
+	if colon := strings.Index(minutes, ":"); colon != -1 {
+		minutes, seconds = minutes[:colon], minutes[colon+1:]
+	}
+
+	base, err := parseDuration(minutes, time.Minute)
+	if err == nil {
+		var extra time.Duration
+		extra, err = parseDuration(seconds, time.Second)
+		base += extra
+	}
+	if err != nil {
+		s.send("Error (bad time control): %s", args[1])
+		return
+	}
+
+	increment, err := parseDuration(args[2], time.Second)
+	if err != nil {
+		s.send("Error (bad increment): %s", args[2])
+		return
+	}
+
+	s.movesPerControl, s.base, s.increment, s.moveTime = moves, base, increment, 0
+	s.clock, s.opponentClock = base, base
+}
+
+// A number of units, which may be fractional
+func parseDuration(text string, unit time.Duration) (time.Duration, error) {
+	number, err := strconv.ParseFloat(text, 64)
+	if err != nil {
+		return 0, err
+	}
+
+	return time.Duration(number * float64(unit)), nil
+}
+
+// Searches for the engine's move under the time controls, playing it when
+// the search is done unless it's aborted
+func (s *Server) think() {
+	if s.hashMegabytes > 0 {
+		s.engine.Table, s.hashMegabytes = engine.NewTranspositionTable(s.hashMegabytes), 0
+	}
+	if s.threads > 0 {
+		s.engine.Threads, s.threads = s.threads, 0
+	}
+
+	limits := engine.Limits{Depth: s.depth, MoveTime: s.moveTime, Time: s.clock, Increment: s.increment}
+
+	if s.movesPerControl > 0 {
+		limits.MovesToGo = s.movesPerControl - (s.moveNumber()-1)%s.movesPerControl
+	}
+
+	if limits.MoveTime == 0 && limits.Time == 0 && limits.Depth == 0 {
+		limits.MoveTime = defaultMoveTime
+	}
+
+	current := &search{done: make(chan struct{}), stopping: make(chan struct{})}
+	s.search = current
+	limits.Stop = current.stopping
+
+	board, post := s.board, s.post
+	s.engine.Progress = func(result engine.Result) {
+		if post {
+			s.thinking(board, result)
+		}
+	}
+
+	go func() {
+		defer close(current.done)
+
+		result := s.engine.Search(board, limits)
+		if atomic.LoadInt32(&current.aborted) != 0 || result.Move == (pawn.Move{}) {
+			return
+		}
+
+		s.send("move %s", board.EncodeMove(result.Move))
+		s.play(result.Move)
+	}()
+}
+
+// The number of the move to be played, counting a move by each side as one
+func (s *Server) moveNumber() int {
+	fields := strings.Fields(s.board.FEN())
+	number, err := strconv.Atoi(fields[len(fields)-1])
+	if err != nil {
+		return 1
+	}
+
+	return number
+}
+
+// Stops the search in progress, if any, so it plays the best move found so
+// far
+func (s *Server) moveNow() {
+	if s.search == nil {
+		return
+	}
+
+	select {
+	case <-s.search.stopping:
+	default:
+		close(s.search.stopping)
+	}
+}
+
+// Stops the search in progress, if any, without playing its move
+func (s *Server) abort() {
+	if s.search == nil {
+		return
+	}
+
+	atomic.StoreInt32(&s.search.aborted, 1)
+	s.moveNow()
+	s.wait()
+}
+
+// Waits for the search in progress, if any, to finish
+func (s *Server) wait() {
+	if s.search == nil {
+		return
+	}
+
+	<-s.search.done
+	s.search = nil
+}
+
+// ply score time nodes pv, with the time in centiseconds and the variation in
+// standard algebraic notation
+func (s *Server) thinking(board *pawn.Board, result engine.Result) {
+	moves := make([]string, len(result.PV))
+	for index, move := range result.PV {
+		moves[index] = string(board.Algebraic(move))
+		board.MakeMove(move)
+	}
+
+	for range result.PV {
+		board.UnmakeMove()
+	}
+
+	s.send("%d %d %d %d %s", result.Depth, Score(result.Score), result.Time.Milliseconds()/10, result.Nodes,
+		strings.Join(moves, " "))
+}
+
+// A score as XBoard shows it, in centipawns or 100000 plus the moves to mate
+func Score(score int) int {
+	if !engine.IsMateScore(score) {
+		return score
+	}
+
+	moves := engine.MateIn(score)
+	if moves < 0 {
+		return -mateScore + moves
+	}
+
+	return mateScore + moves
+}
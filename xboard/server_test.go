@@ -0,0 +1,271 @@
+package xboard
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type ServerTestSuite struct {
+	suite.Suite
+	server *Server
+	in     *io.PipeWriter
+	out    *bufio.Scanner
+	done   chan error
+}
+
+func TestServerTestSuite(t *testing.T) {
+	suite.Run(t, new(ServerTestSuite))
+}
+
+func (s *ServerTestSuite) SetupTest() {
+	inReader, inWriter := io.Pipe()
+	outReader, outWriter := io.Pipe()
+
+	s.server = NewServer(inReader, outWriter)
+	s.in, s.out = inWriter, bufio.NewScanner(outReader)
+	s.done = make(chan error, 1)
+
+	go func() {
+		err := s.server.Run()
+		outWriter.Close()
+		s.done <- err
+	}()
+
+	s.send("xboard")
+}
+
+func (s *ServerTestSuite) TearDownTest() {
+	s.in.Close()
+
+	// Drain anything left so the server isn't blocked writing
+	out := s.out
+	go func() {
+		for out.Scan() {
+		}
+	}()
+
+	select {
+	case err := <-s.done:
+		s.Nil(err)
+	case <-time.After(10 * time.Second):
+		s.Fail("the server didn't finish")
+	}
+}
+
+func (s *ServerTestSuite) send(format string, args ...interface{}) {
+	_, err := fmt.Fprintf(s.in, format+"\n", args...)
+	s.Require().Nil(err)
+}
+
+// Reads lines up to and including the first starting with prefix
+func (s *ServerTestSuite) expect(prefix string) []string {
+	lines := make(chan []string, 1)
+
+	go func() {
+		read := []string{}
+		for s.out.Scan() {
+			read = append(read, s.out.Text())
+			if strings.HasPrefix(s.out.Text(), prefix) {
+				break
+			}
+		}
+		lines <- read
+	}()
+
+	select {
+	case read := <-lines:
+		s.Require().True(len(read) > 0 && strings.HasPrefix(read[len(read)-1], prefix), "no %q in %q", prefix, read)
+		return read
+	case <-time.After(10 * time.Second):
+		s.FailNow("timed out waiting for " + prefix)
+		return nil
+	}
+}
+
+func (s *ServerTestSuite) TestFeatures() {
+	s.send("protover 2")
+	lines := s.expect("feature")
+
+	s.Contains(lines[0], "usermove=1")
+	s.Contains(lines[0], "setboard=1")
+	s.True(strings.HasSuffix(lines[0], "done=1"))
+
+	s.send("accepted usermove")
+	s.send("nonsense")
+	s.expect("Error (unknown command): nonsense")
+}
+
+func (s *ServerTestSuite) TestPlay() {
+	s.send("new")
+	s.send("level 40 0:10 0")
+	s.send("time 1000")
+	s.send("otim 1000")
+	s.send("usermove e2e4")
+	s.send("ping 1")
+	lines := s.expect("pong 1")
+
+	s.Len(lines, 2)
+	s.True(strings.HasPrefix(lines[0], "move "), lines[0])
+
+	s.send("usermove e2e5")
+	s.expect("Illegal move: e2e5")
+}
+
+func (s *ServerTestSuite) TestForce() {
+	s.send("new")
+	s.send("force")
+	s.send("usermove e2e4")
+	s.send("usermove e7e5")
+	s.send("ping 1")
+	s.Equal([]string{"pong 1"}, s.expect("pong"))
+
+	s.send("sd 2")
+	s.send("go")
+	s.expect("move ")
+
+	// The engine plays White now
+	s.send("usermove b8c6")
+	s.expect("move ")
+}
+
+func (s *ServerTestSuite) TestUndo() {
+	s.send("new")
+	s.send("force")
+	s.send("usermove e2e4")
+	s.send("usermove e7e5")
+	s.send("undo")
+	s.send("usermove c7c5")
+	s.send("remove")
+	s.send("usermove d2d4")
+	s.send("usermove c7c5")
+	s.send("ping 1")
+	s.Equal([]string{"pong 1"}, s.expect("pong"))
+
+	s.send("undo")
+	s.send("undo")
+	s.send("undo")
+	s.send("usermove e2e4")
+	s.send("ping 2")
+	s.Equal([]string{"pong 2"}, s.expect("pong"))
+}
+
+func (s *ServerTestSuite) TestMate() {
+	s.send("setboard 6k1/5ppp/8/8/8/8/5PPP/3R2K1 w - - 0 1")
+	s.send("sd 3")
+	s.send("post")
+	s.send("go")
+	lines := s.expect("move")
+
+	s.Equal("1 100001 ", lines[0][:9])
+	s.True(strings.HasSuffix(lines[0], " Rd8#"), lines[0])
+	s.Equal("move d1d8", lines[len(lines)-1])
+	s.expect("1-0 {White mates}")
+
+	// The user mating the engine is reported too
+	s.send("setboard 6k1/5ppp/8/8/8/8/5PPP/3R2K1 w - - 0 1")
+	s.send("usermove d1d8")
+	s.expect("1-0 {White mates}")
+	s.send("ping 1")
+	s.Equal([]string{"pong 1"}, s.expect("pong"))
+
+	s.send("setboard bad")
+	s.expect("tellusererror Illegal position")
+}
+
+func (s *ServerTestSuite) TestDraws() {
+	s.send("force")
+	for _, move := range []string{"g1f3", "g8f6", "f3g1", "f6g8", "g1f3", "g8f6", "f3g1", "f6g8"} {
+		s.send("usermove " + move)
+	}
+	s.expect("1/2-1/2 {Threefold repetition}")
+
+	s.send("setboard 7k/8/6K1/8/8/8/8/5Q2 w - - 0 1")
+	s.send("usermove f1f7")
+	s.expect("1/2-1/2 {Stalemate}")
+
+	s.send("setboard 7k/8/6K1/8/8/8/1p6/B7 w - - 0 1")
+	s.send("usermove a1b2")
+	s.expect("1/2-1/2 {Insufficient material}")
+}
+
+func (s *ServerTestSuite) TestSetboardInForce() {
+	s.send("new")
+	s.send("force")
+	s.send("setboard rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR w KQkq - 0 1")
+	s.send("usermove e2e4")
+	s.send("ping 1")
+	s.Equal([]string{"pong 1"}, s.expect("pong 1"), "the engine stays in force mode")
+}
+
+func (s *ServerTestSuite) TestMoveNow() {
+	s.send("st 60")
+	s.send("go")
+
+	s.send("?")
+	s.expect("move ")
+
+	// An aborted search doesn't move
+	s.send("force")
+	s.send("go")
+	s.send("force")
+	s.send("ping 1")
+	lines := s.expect("pong 1")
+	s.Equal("pong 1", lines[len(lines)-1])
+	for _, line := range lines[:len(lines)-1] {
+		s.False(strings.HasPrefix(line, "move "), line)
+	}
+}
+
+func (s *ServerTestSuite) TestLevel() {
+	s.send("level 40 0:30 2")
+	s.send("level 0 5 0.5")
+	s.send("cores 4")
+	s.send("level 0 x 0")
+	s.expect("Error (bad time control): x")
+	s.send("ping 1")
+	s.expect("pong 1")
+
+	s.Equal(5*time.Minute, s.server.base)
+	s.Equal(500*time.Millisecond, s.server.increment)
+	s.Equal(0, s.server.movesPerControl)
+	s.Equal(4, s.server.threads)
+}
+
+func (s *ServerTestSuite) TestSettingsWhileThinking() {
+	table := s.server.engine.Table
+
+	s.send("st 60")
+	s.send("go")
+	s.send("memory 1")
+	s.send("cores 2")
+	s.send("?")
+	s.expect("move ")
+	s.send("ping 1")
+	s.expect("pong 1")
+
+	// The search that was in progress kept its own
+	s.Same(table, s.server.engine.Table)
+	s.Equal(1, s.server.engine.Threads)
+
+	s.send("force")
+	s.send("sd 1")
+	s.send("go")
+	s.expect("move ")
+	s.send("ping 2")
+	s.expect("pong 2")
+
+	s.NotSame(table, s.server.engine.Table)
+	s.Equal(2, s.server.engine.Threads)
+}
+
+func (s *ServerTestSuite) TestScore() {
+	s.Equal(-35, Score(-35))
+	s.Equal(100002, Score(31997))
+	s.Equal(-100001, Score(-31998))
+}
@@ -0,0 +1,121 @@
+package pawn
+
+// Zobrist hashing gives every position a 64 bit key that is updated
+// incrementally as moves are made. Positions that are the same under the
+// rules of chess — same pieces, side to move, castling rights and en
+// passant capture — hash the same however they were reached.
+var (
+	pieceKeys     [2][King + 1][64]uint64 // Indexed by color, material and square
+	castlingKeys  [allCastlingRights + 1]uint64
+	enPassantKeys [8]uint64 // Indexed by file
+	sideToMoveKey uint64    // Mixed in when Black is to move
+)
+
+func init() {
+	// splitmix64 from a fixed seed so hashes are stable between runs
+	seed := uint64(0x5041574e) // "PAWN"
+	next := func() uint64 {
+		seed += 0x9e3779b97f4a7c15
+		z := seed
+		z = (z ^ (z >> 30)) * 0xbf58476d1ce4e5b9
+		z = (z ^ (z >> 27)) * 0x94d049bb133111eb
+		return z ^ (z >> 31)
+	}
+
+	for color := range pieceKeys {
+		for material := Pawn; material <= King; material++ {
+			for square := range pieceKeys[color][material] {
+				pieceKeys[color][material][square] = next()
+			}
+		}
+	}
+
+	for rights := range castlingKeys {
+		castlingKeys[rights] = next()
+	}
+
+	for file := range enPassantKeys {
+		enPassantKeys[file] = next()
+	}
+
+	sideToMoveKey = next()
+}
+
+func (c Color) index() int {
+	if c == White {
+		return 0
+	}
+
+	return 1
+}
+
+func pieceKey(piece Piece, square int) uint64 {
+	if piece == NoPiece {
+		return 0
+	}
+
+	return pieceKeys[piece.Color.index()][piece.Material][square]
+}
+
+// The Zobrist hash of the position
+func (b Board) Hash() uint64 {
+	return b.hash
+}
+
+// Computes the hash from scratch rather than incrementally
+func (b Board) computeHash() uint64 {
+	var hash uint64
+
+	for index, square := range b.Squares {
+		hash ^= pieceKey(square.Piece, index)
+	}
+
+	hash ^= castlingKeys[b.castling]
+	hash ^= b.enPassantKey()
+
+	if b.turnToMove() == Black {
+		hash ^= sideToMoveKey
+	}
+
+	return hash
+}
+
+func (b *Board) setPiece(square int, piece Piece) {
+	b.hash ^= pieceKey(b.Squares[square].Piece, square) ^ pieceKey(piece, square)
+	b.Squares[square].Piece = piece
+}
+
+func (b *Board) setCastling(rights castlingRights) {
+	b.hash ^= castlingKeys[b.castling] ^ castlingKeys[rights]
+	b.castling = rights
+}
+
+// Must be called after the move that sets it so the side to move is the one
+// that may capture
+func (b *Board) setEnPassant(position Position) {
+	b.hash ^= b.enPassantKey()
+	b.enPassant = position
+	b.hash ^= b.enPassantKey()
+}
+
+// En passant only distinguishes a position when a pawn can actually make the
+// capture
+func (b Board) enPassantKey() uint64 {
+	if b.enPassant == (Position{}) {
+		return 0
+	}
+
+	capturer := Piece{b.turnToMove(), Pawn}
+	origin := Position{b.enPassant.File, b.enPassant.Rank - 1}
+	if capturer.Color == Black {
+		origin.Rank = b.enPassant.Rank + 1
+	}
+
+	for _, direction := range [...]Direction{Left, Right} {
+		if ray := rays[origin.index()][direction]; len(ray) > 0 && b.Squares[ray[0]].Piece == capturer {
+			return enPassantKeys[b.enPassant.File.index()]
+		}
+	}
+
+	return 0
+}
@@ -0,0 +1,89 @@
+package pawn
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type ZobristTestSuite struct {
+	suite.Suite
+}
+
+func TestZobristTestSuite(t *testing.T) {
+	suite.Run(t, new(ZobristTestSuite))
+}
+
+func (s *ZobristTestSuite) boardAfter(moves ...AlgebraicNotation) *Board {
+	board := NewBoard()
+	for _, an := range moves {
+		_, err := board.MoveFromAlgebraic(an)
+		s.Require().Nil(err, string(an))
+	}
+
+	return board
+}
+
+func (s *ZobristTestSuite) TestIncrementalHash() {
+	board := NewBoard()
+	s.Equal(board.computeHash(), board.Hash())
+
+	for _, an := range ParsePGN(draw).Turns() {
+		board.MoveFromAlgebraic(an)
+		s.Equal(board.computeHash(), board.Hash(), string(an))
+	}
+
+	for board.UnmakeMove() == nil {
+		s.Equal(board.computeHash(), board.Hash())
+	}
+	s.Equal(NewBoard().Hash(), board.Hash())
+}
+
+// e5 puts a pawn beside d5 after the chance to take it en passant has gone
+func (s *ZobristTestSuite) TestIncrementalHashEnPassantExpiring() {
+	board := s.boardAfter("e4", "d5", "e5", "f5")
+	s.Equal(board.computeHash(), board.Hash())
+
+	board.MoveFromAlgebraic("Nf3")
+	s.Equal(board.computeHash(), board.Hash())
+}
+
+func (s *ZobristTestSuite) TestTranspositions() {
+	s.Equal(
+		s.boardAfter("e4", "e6", "d4").Hash(),
+		s.boardAfter("d4", "e6", "e4").Hash(),
+	)
+
+	s.Equal(
+		s.boardAfter("Nf3", "Nf6", "Ng1", "Ng8").Hash(),
+		NewBoard().Hash(),
+	)
+
+	s.NotEqual(
+		s.boardAfter("Nf3", "Nf6", "Ng1").Hash(),
+		s.boardAfter("Nf3").Hash(),
+		"side to move",
+	)
+}
+
+func (s *ZobristTestSuite) TestCastlingRights() {
+	s.NotEqual(
+		s.boardAfter("e4", "e5", "Ke2", "Ke7", "Ke1", "Ke8").Hash(),
+		s.boardAfter("e4", "e5").Hash(),
+	)
+}
+
+func (s *ZobristTestSuite) TestEnPassant() {
+	// A capture is possible so the position differs from reaching it with
+	// single steps
+	s.NotEqual(
+		s.boardAfter("e4", "a6", "e5", "d5").Hash(),
+		s.boardAfter("e3", "a6", "e4", "d6", "e5", "d5").Hash(),
+	)
+
+	// No pawn can capture so it doesn't
+	s.Equal(
+		s.boardAfter("e4", "a6", "e5", "h5").Hash(),
+		s.boardAfter("e3", "h6", "e4", "a6", "e5", "h5").Hash(),
+	)
+}